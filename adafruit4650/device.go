@@ -28,11 +28,12 @@ const (
 
 // Device represents an Adafruit 4650 device
 type Device struct {
-	bus     drivers.I2C
-	Address uint8
-	buffer  []byte
-	width   int16
-	height  int16
+	bus      drivers.I2C
+	Address  uint8
+	buffer   []byte
+	width    int16
+	height   int16
+	rotation drivers.Rotation
 }
 
 // New creates a new device, not configuring anything yet.
@@ -96,9 +97,11 @@ func (d *Device) ClearBuffer() {
 // SetPixel modifies the internal buffer. Since this display has a bit-depth of 1 bit any non-zero
 // color component will be treated as 'on',  otherwise 'off'.
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
 		return
 	}
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
 
 	// RAM layout
 	//    *-----> y
@@ -174,9 +177,31 @@ func (d *Device) setRAMPosition(page uint8, column uint8) error {
 	return d.writeCommands(cmds)
 }
 
-// Size returns the current size of the display.
+// Sleep turns the display off and puts the controller in sleep mode, or wakes
+// it up again. The display memory is kept while sleeping.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		return d.writeCommands([]byte{0xae}) // display off, sleep mode
+	}
+	return d.writeCommands([]byte{0xaf}) // display on
+}
+
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the currently configured rotation.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the display (clock-wise). Size returns
+// the rotated size right away; to rotate the image that is shown, clear the
+// buffer and redraw it.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 func (d *Device) writeCommands(commands []byte) error {
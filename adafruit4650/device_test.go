@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
 	"tinygo.org/x/tinyfont"
 	"tinygo.org/x/tinyfont/freemono"
 )
@@ -127,6 +128,17 @@ func TestDevice_Display(t *testing.T) {
 	assertEqualImages(t, actual, expected)
 }
 
+func TestDevice_Rotation(t *testing.T) {
+	dev := New(newMock())
+	dev.Configure()
+
+	tester.TestRotation(t, &dev, func(x, y int16) bool {
+		// Same RAM layout as in SetPixel.
+		index := (height - y - 1) + height*(x/8)
+		return dev.buffer[index]&(1<<uint8(x%8)) != 0
+	})
+}
+
 func drawPlus(d drivers.Displayer) {
 	for i := int16(0); i < 128; i++ {
 		d.SetPixel(i, 32, color.RGBA{R: 1})
@@ -0,0 +1,92 @@
+// Package backlight controls the brightness of a display backlight using PWM.
+//
+// Most TFT displays have a backlight pin that turns the LEDs of the backlight
+// on and off, usually through a transistor. Driving this pin with a PWM signal
+// instead of a fixed level dims the backlight, which saves power.
+package backlight // import "tinygo.org/x/drivers/backlight"
+
+import "machine"
+
+// PWM is the interface necessary for controlling a backlight.
+type PWM interface {
+	Configure(config machine.PWMConfig) error
+	Channel(pin machine.Pin) (channel uint8, err error)
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
+// Config is the configuration of a backlight.
+type Config struct {
+	// PWM period in nanoseconds. The default is 1ms (1kHz), which is fast
+	// enough to avoid visible flicker and slow enough for the transistors
+	// used on most display modules.
+	Period uint64
+
+	// The backlight is on when the pin is low, as on some display modules
+	// with a P-channel transistor.
+	ActiveLow bool
+
+	// Set the duty cycle linearly from the brightness. By default, the
+	// brightness is corrected so that the steps look evenly spaced to the
+	// human eye.
+	Linear bool
+}
+
+// Device is a backlight driven by a PWM output.
+type Device struct {
+	pwm        PWM
+	channel    uint8
+	activeLow  bool
+	linear     bool
+	brightness uint8
+}
+
+// New configures the PWM and returns a backlight on the given pin, which is
+// off initially. Please check the chip documentation which pins can be
+// controlled by the given PWM.
+func New(pwm PWM, pin machine.Pin, config Config) (Device, error) {
+	if config.Period == 0 {
+		config.Period = 1e6 // 1ms
+	}
+	err := pwm.Configure(machine.PWMConfig{
+		Period: config.Period,
+	})
+	if err != nil {
+		return Device{}, err
+	}
+	channel, err := pwm.Channel(pin)
+	if err != nil {
+		return Device{}, err
+	}
+	d := Device{
+		pwm:       pwm,
+		channel:   channel,
+		activeLow: config.ActiveLow,
+		linear:    config.Linear,
+	}
+	d.SetBrightness(0)
+	return d, nil
+}
+
+// SetBrightness sets the brightness of the backlight, from 0 (off) to 255
+// (fully on).
+func (d *Device) SetBrightness(brightness uint8) {
+	d.brightness = brightness
+	top := uint64(d.pwm.Top())
+	var value uint64
+	if d.linear {
+		value = top * uint64(brightness) / 255
+	} else {
+		// Perceived brightness is roughly the square root of the duty cycle.
+		value = top * uint64(brightness) * uint64(brightness) / (255 * 255)
+	}
+	if d.activeLow {
+		value = top - value
+	}
+	d.pwm.Set(d.channel, uint32(value))
+}
+
+// Brightness returns the brightness set with SetBrightness.
+func (d *Device) Brightness() uint8 {
+	return d.brightness
+}
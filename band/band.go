@@ -0,0 +1,108 @@
+// Package band implements double buffered rendering for displays without a
+// framebuffer, such as most SPI TFT displays.
+//
+// The screen is drawn in horizontal bands (strips) a few lines high. While one
+// band buffer is being sent to the display, the next band is drawn into the
+// other buffer. On SPI buses that support asynchronous transfers (see
+// drivers.SPIAsync) this means drawing and sending happen at the same time,
+// which can nearly double the frame rate of animations. On other buses the
+// bands are sent synchronously, which is still a lot faster than drawing
+// individual pixels.
+package band // import "tinygo.org/x/drivers/band"
+
+import (
+	"errors"
+
+	"tinygo.org/x/drivers/pixel"
+)
+
+var errTooWide = errors.New("band: rectangle is wider than the band buffer")
+
+// Displayer is a display that can send image buffers in the background. It is
+// implemented by the st7789, st7735, ili9341, gc9a01 and ssd1351 drivers.
+type Displayer[T pixel.Color] interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+
+	// StartDrawBitmap starts sending the image to the display. The image must
+	// not be modified until the transfer has finished.
+	StartDrawBitmap(x, y int16, bitmap pixel.Image[T]) error
+
+	// Wait blocks until the last transfer has finished.
+	Wait() error
+}
+
+// DrawFunc draws into the image buffer img, which covers the area of the
+// screen starting at x, y with the size of img. The previous contents of img is
+// undefined, so every pixel must be drawn.
+type DrawFunc[T pixel.Color] func(img pixel.Image[T], x, y int)
+
+// Renderer draws the screen in bands, using two band buffers.
+type Renderer[T pixel.Color] struct {
+	display Displayer[T]
+	buffers [2]pixel.Image[T]
+	next    int
+}
+
+// New creates a new renderer with two band buffers that are as wide as the
+// display and the given number of lines high.
+func New[T pixel.Color](display Displayer[T], lines int) *Renderer[T] {
+	width, _ := display.Size()
+	return NewWithBuffers(display,
+		pixel.NewImage[T](int(width), lines),
+		pixel.NewImage[T](int(width), lines))
+}
+
+// NewWithBuffers creates a new renderer that uses the two given buffers. They
+// must have the same size.
+func NewWithBuffers[T pixel.Color](display Displayer[T], buf1, buf2 pixel.Image[T]) *Renderer[T] {
+	if buf1.Len() != buf2.Len() {
+		panic("band: buffers must have the same size")
+	}
+	return &Renderer[T]{
+		display: display,
+		buffers: [2]pixel.Image[T]{buf1, buf2},
+	}
+}
+
+// Render redraws the whole screen.
+func (r *Renderer[T]) Render(draw DrawFunc[T]) error {
+	width, height := r.display.Size()
+	return r.RenderRect(0, 0, int(width), int(height), draw)
+}
+
+// RenderRect redraws the given rectangle of the screen. The rectangle may be
+// narrower than the band buffers, in which case each band covers more lines.
+//
+// It returns as soon as the last band has started sending. There's no need to
+// call Wait before drawing to the display again, as the display driver waits
+// for the transfer to finish before sending anything else.
+func (r *Renderer[T]) RenderRect(x, y, width, height int, draw DrawFunc[T]) error {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	lines := r.buffers[0].Len() / width
+	if lines == 0 {
+		return errTooWide
+	}
+	for bandY := y; bandY < y+height; bandY += lines {
+		if y+height-bandY < lines {
+			lines = y + height - bandY
+		}
+		// The buffer that is drawn into here was last used two bands ago. The
+		// StartDrawBitmap call for the previous band has waited for that
+		// transfer to finish, so it's safe to reuse.
+		img := r.buffers[r.next].Rescale(width, lines)
+		draw(img, x, bandY)
+		if err := r.display.StartDrawBitmap(int16(x), int16(bandY), img); err != nil {
+			return err
+		}
+		r.next ^= 1
+	}
+	return nil
+}
+
+// Wait blocks until the last band has been sent to the display.
+func (r *Renderer[T]) Wait() error {
+	return r.display.Wait()
+}
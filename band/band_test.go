@@ -0,0 +1,98 @@
+package band_test
+
+import (
+	"testing"
+
+	"tinygo.org/x/drivers/band"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// fakeDisplay records the bands that were sent, and checks that a buffer isn't
+// drawn into while it's still being "sent".
+type fakeDisplay struct {
+	width    int16
+	height   int16
+	screen   pixel.Image[pixel.RGB888]
+	inFlight pixel.Image[pixel.RGB888]
+	bands    int
+}
+
+func (d *fakeDisplay) Size() (int16, int16) {
+	return d.width, d.height
+}
+
+func (d *fakeDisplay) StartDrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB888]) error {
+	d.Wait()
+	d.inFlight = bitmap
+	width, height := bitmap.Size()
+	for by := 0; by < height; by++ {
+		for bx := 0; bx < width; bx++ {
+			d.screen.Set(int(x)+bx, int(y)+by, bitmap.Get(bx, by))
+		}
+	}
+	d.bands++
+	return nil
+}
+
+func (d *fakeDisplay) Wait() error {
+	d.inFlight = pixel.Image[pixel.RGB888]{}
+	return nil
+}
+
+func TestRender(t *testing.T) {
+	display := &fakeDisplay{width: 10, height: 7}
+	display.screen = pixel.NewImage[pixel.RGB888](10, 7)
+	r := band.New[pixel.RGB888](display, 3)
+
+	err := r.Render(func(img pixel.Image[pixel.RGB888], x, y int) {
+		if display.inFlight.Len() != 0 && &display.inFlight.RawBuffer()[0] == &img.RawBuffer()[0] {
+			t.Errorf("drawing into a buffer that is still being sent")
+		}
+		width, height := img.Size()
+		for by := 0; by < height; by++ {
+			for bx := 0; bx < width; bx++ {
+				img.Set(bx, by, pixel.NewRGB888(uint8(x+bx), uint8(y+by), 0))
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if display.bands != 3 {
+		t.Errorf("expected 3 bands, got %d", display.bands)
+	}
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 10; x++ {
+			if c := display.screen.Get(x, y); c != pixel.NewRGB888(uint8(x), uint8(y), 0) {
+				t.Errorf("unexpected pixel at %d, %d: %v", x, y, c)
+			}
+		}
+	}
+}
+
+func TestRenderRect(t *testing.T) {
+	display := &fakeDisplay{width: 10, height: 7}
+	display.screen = pixel.NewImage[pixel.RGB888](10, 7)
+	r := band.New[pixel.RGB888](display, 2)
+
+	// A 5 pixel wide rectangle fits 4 lines in each 10x2 buffer.
+	err := r.RenderRect(2, 1, 5, 6, func(img pixel.Image[pixel.RGB888], x, y int) {
+		img.FillSolidColor(pixel.NewRGB888(255, 255, 255))
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if display.bands != 2 {
+		t.Errorf("expected 2 bands, got %d", display.bands)
+	}
+	if c := display.screen.Get(6, 6); c != pixel.NewRGB888(255, 255, 255) {
+		t.Errorf("expected pixel inside the rectangle to be drawn")
+	}
+	if c := display.screen.Get(7, 6); c != pixel.NewRGB888(0, 0, 0) {
+		t.Errorf("expected pixel outside the rectangle to be left alone")
+	}
+
+	if err := r.RenderRect(0, 0, 21, 1, func(pixel.Image[pixel.RGB888], int, int) {}); err == nil {
+		t.Errorf("expected an error for a rectangle wider than the buffers")
+	}
+}
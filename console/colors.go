@@ -0,0 +1,55 @@
+package console
+
+import "image/color"
+
+// Palette contains the 16 standard ANSI colors: black, red, green, yellow,
+// blue, magenta, cyan and white, followed by their bright variants. It may be
+// modified to change the colors used by escape sequences.
+var Palette = [16]color.RGBA{
+	{R: 0, G: 0, B: 0, A: 255},
+	{R: 170, G: 0, B: 0, A: 255},
+	{R: 0, G: 170, B: 0, A: 255},
+	{R: 170, G: 85, B: 0, A: 255},
+	{R: 0, G: 0, B: 170, A: 255},
+	{R: 170, G: 0, B: 170, A: 255},
+	{R: 0, G: 170, B: 170, A: 255},
+	{R: 170, G: 170, B: 170, A: 255},
+	{R: 85, G: 85, B: 85, A: 255},
+	{R: 255, G: 85, B: 85, A: 255},
+	{R: 85, G: 255, B: 85, A: 255},
+	{R: 255, G: 255, B: 85, A: 255},
+	{R: 85, G: 85, B: 255, A: 255},
+	{R: 255, G: 85, B: 255, A: 255},
+	{R: 85, G: 255, B: 255, A: 255},
+	{R: 255, G: 255, B: 255, A: 255},
+}
+
+// color256 returns a color from the xterm 256 color palette: the 16 standard
+// colors, a 6x6x6 color cube and 24 shades of gray.
+func color256(index int) color.RGBA {
+	switch {
+	case index < 0 || index > 255:
+		return color.RGBA{A: 255}
+	case index < 16:
+		return Palette[index]
+	case index < 232:
+		index -= 16
+		return color.RGBA{
+			R: cubeLevel(index / 36),
+			G: cubeLevel(index / 6 % 6),
+			B: cubeLevel(index % 6),
+			A: 255,
+		}
+	default:
+		level := uint8(8 + (index-232)*10)
+		return color.RGBA{R: level, G: level, B: level, A: 255}
+	}
+}
+
+// cubeLevel returns the intensity of a level (0-5) in the 6x6x6 color cube.
+func cubeLevel(level int) uint8 {
+	if level == 0 {
+		return 0
+	}
+	return uint8(55 + level*40)
+}
@@ -0,0 +1,461 @@
+// Package console implements a text console on TFT displays that support
+// vertical hardware scrolling, such as the st7789, ili9341 and gc9a01.
+//
+// New lines are appended at the bottom of the scroll area and the display
+// memory is scrolled by the display itself, so only a single line of text is
+// sent to the display for every new line. This makes the console fast enough
+// to be used as the output of the log package:
+//
+//	log.SetOutput(console)
+//
+// A number of text lines at the top and bottom of the screen can be kept out of
+// the scroll area, to show a fixed header and footer (see SetHeader and
+// SetFooter).
+//
+// The console understands the most common ANSI escape sequences: SGR (colors)
+// using the 16 standard colors and 24-bit colors, erase in line (ESC [K) and
+// erase in display (ESC [2J).
+//
+// Hardware scrolling only works in the direction in which the display refreshes
+// its panel, so the display must be used in its default (or upside-down)
+// orientation.
+package console // import "tinygo.org/x/drivers/console"
+
+import (
+	"image/color"
+	"unicode/utf8"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// Displayer is a display that supports vertical hardware scrolling. It is
+// implemented by the st7789, ili9341 and gc9a01 drivers.
+type Displayer[T pixel.Color] interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+
+	// DrawBitmap copies the image to the display at the given coordinates.
+	DrawBitmap(x, y int16, bitmap pixel.Image[T]) error
+
+	// SetScrollArea sets the fixed areas at the top and bottom of the display,
+	// in pixels. The area in between is scrolled.
+	SetScrollArea(topFixedArea, bottomFixedArea int16)
+
+	// SetScroll sets the line of display memory that is shown at the top of
+	// the scroll area.
+	SetScroll(line int16)
+}
+
+// Config is the configuration of a console.
+type Config struct {
+	// Font used to draw text. Required.
+	Font *font.Font
+
+	// Number of text lines at the top and bottom of the screen that are not
+	// scrolled.
+	HeaderLines int
+	FooterLines int
+
+	// Default text colors. The default is white text on a black background.
+	Foreground color.RGBA
+	Background color.RGBA
+
+	// Colors of the header and footer. The default is the inverse of the
+	// text colors.
+	StatusForeground color.RGBA
+	StatusBackground color.RGBA
+}
+
+// Parser states.
+const (
+	stateText uint8 = iota
+	stateEscape
+	stateCSI
+)
+
+// Maximum number of parameters in an escape sequence. Parameters after this
+// are ignored.
+const maxParams = 8
+
+// Console is a scrolling text console. It implements io.Writer.
+type Console[T pixel.Color] struct {
+	display Displayer[T]
+	font    *font.Font
+	config  Config
+
+	width      int
+	lineHeight int
+	top        int // height of the header in pixels
+	rows       int // number of text lines in the scroll area
+	offset     int // scroll offset in pixels
+	row        int // text line of the cursor in the scroll area
+
+	line   pixel.Image[T] // current line
+	status pixel.Image[T] // buffer for the header and footer, allocated on first use
+	penX   int
+	filled int  // background has been drawn up to here
+	prev   rune // previous rune, for kerning
+	dirty  bool // line has changed since it was last sent
+
+	fg, bg color.RGBA
+	bold   bool
+
+	state   uint8
+	params  [maxParams]int
+	nparams int
+	utf8buf [utf8.UTFMax]byte
+	utf8len int
+}
+
+// New creates a new console on the given display and clears the screen.
+func New[T pixel.Color](display Displayer[T], config Config) *Console[T] {
+	if config.Foreground == (color.RGBA{}) {
+		config.Foreground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	if config.Background == (color.RGBA{}) {
+		config.Background = color.RGBA{A: 255}
+	}
+	if config.StatusForeground == (color.RGBA{}) && config.StatusBackground == (color.RGBA{}) {
+		config.StatusForeground = config.Background
+		config.StatusBackground = config.Foreground
+	}
+	width, height := display.Size()
+	lineHeight := config.Font.LineHeight()
+	top := config.HeaderLines * lineHeight
+	rows := (int(height) - top - config.FooterLines*lineHeight) / lineHeight
+	if rows < 1 {
+		panic("console: display too small for the font")
+	}
+
+	c := &Console[T]{
+		display:    display,
+		font:       config.Font,
+		config:     config,
+		width:      int(width),
+		lineHeight: lineHeight,
+		top:        top,
+		rows:       rows,
+		line:       pixel.NewImage[T](int(width), lineHeight),
+		prev:       -1,
+		fg:         config.Foreground,
+		bg:         config.Background,
+	}
+
+	// Any pixels left over below the scroll area become part of the footer.
+	display.SetScrollArea(int16(top), height-int16(top+rows*lineHeight))
+	c.Clear()
+	return c
+}
+
+// Clear clears the scroll area and moves the cursor to the top.
+func (c *Console[T]) Clear() error {
+	c.offset = 0
+	c.row = 0
+	c.display.SetScroll(int16(c.top))
+	c.clearLine()
+	for row := 0; row < c.rows; row++ {
+		if err := c.display.DrawBitmap(0, int16(c.top+row*c.lineHeight), c.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetHeader shows the text on the given line of the header.
+func (c *Console[T]) SetHeader(line int, text string) error {
+	if line < 0 || line >= c.config.HeaderLines {
+		return nil
+	}
+	return c.drawStatus(line*c.lineHeight, text)
+}
+
+// SetFooter shows the text on the given line of the footer.
+func (c *Console[T]) SetFooter(line int, text string) error {
+	if line < 0 || line >= c.config.FooterLines {
+		return nil
+	}
+	return c.drawStatus(c.top+c.rows*c.lineHeight+line*c.lineHeight, text)
+}
+
+// drawStatus draws a line of text in a fixed area of the screen.
+func (c *Console[T]) drawStatus(y int, text string) error {
+	if c.status.Len() == 0 {
+		c.status = pixel.NewImage[T](c.width, c.lineHeight)
+	}
+	bg := c.config.StatusBackground
+	c.status.FillSolidColor(pixel.NewColor[T](bg.R, bg.G, bg.B))
+	font.Draw(c.status, c.font, 0, int(c.font.Ascent), text, c.config.StatusForeground)
+	return c.display.DrawBitmap(0, int16(y), c.status)
+}
+
+// Write writes the text to the console. Incomplete lines are shown right away.
+// UTF-8 sequences and escape sequences may be split over multiple calls.
+func (c *Console[T]) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		switch c.state {
+		case stateEscape:
+			if b == '[' {
+				c.state = stateCSI
+				c.params = [maxParams]int{}
+				c.nparams = 0
+			} else {
+				// Unsupported escape sequence.
+				c.state = stateText
+			}
+		case stateCSI:
+			switch {
+			case b >= '0' && b <= '9':
+				if c.nparams == 0 {
+					c.nparams = 1
+				}
+				if c.nparams <= maxParams {
+					c.params[c.nparams-1] = c.params[c.nparams-1]*10 + int(b-'0')
+				}
+			case b == ';':
+				if c.nparams == 0 {
+					c.nparams = 1
+				}
+				c.nparams++
+			case b >= 0x40 && b <= 0x7e:
+				// Final byte of the sequence.
+				c.state = stateText
+				if err := c.control(b); err != nil {
+					return n, err
+				}
+			}
+		default:
+			if err := c.writeByte(b); err != nil {
+				return n, err
+			}
+		}
+		n++
+	}
+	return n, c.flush()
+}
+
+// writeByte handles a single byte of text.
+func (c *Console[T]) writeByte(b byte) error {
+	if c.utf8len == 0 && b < utf8.RuneSelf {
+		switch b {
+		case 0x1b:
+			c.state = stateEscape
+		case '\n':
+			return c.newline()
+		case '\r':
+			// Move to the start of the line. Text that follows overwrites
+			// the line.
+			c.penX = 0
+			c.filled = 0
+			c.prev = -1
+		case '\t':
+			// Move to the next multiple of 8 spaces.
+			tab := 8 * c.advance(' ')
+			if tab > 0 {
+				c.penX = (c.penX/tab + 1) * tab
+				c.prev = -1
+			}
+		default:
+			if b >= ' ' && b != 0x7f {
+				return c.writeRune(rune(b))
+			}
+		}
+		return nil
+	}
+
+	c.utf8buf[c.utf8len] = b
+	c.utf8len++
+	if !utf8.FullRune(c.utf8buf[:c.utf8len]) {
+		return nil
+	}
+	r, _ := utf8.DecodeRune(c.utf8buf[:c.utf8len])
+	c.utf8len = 0
+	return c.writeRune(r)
+}
+
+// writeRune draws a single rune on the current line, wrapping to the next line
+// when it doesn't fit.
+func (c *Console[T]) writeRune(r rune) error {
+	g := c.font.Glyph(r)
+	if g == nil {
+		return nil
+	}
+	if c.prev >= 0 {
+		c.penX += c.font.Kern(c.prev, r)
+	}
+	if c.penX+int(g.XAdvance) > c.width && c.penX > 0 {
+		if err := c.newline(); err != nil {
+			return err
+		}
+	}
+
+	// Draw the background of the glyph cell, without overwriting the previous
+	// glyph when it overlaps due to kerning.
+	right := c.penX + int(g.XAdvance)
+	if right > c.width {
+		right = c.width
+	}
+	if c.filled < right {
+		bg := pixel.NewColor[T](c.bg.R, c.bg.G, c.bg.B)
+		for y := 0; y < c.lineHeight; y++ {
+			for x := c.filled; x < right; x++ {
+				c.line.Set(x, y, bg)
+			}
+		}
+		c.filled = right
+	}
+
+	font.DrawGlyph(c.line, c.font, g, c.penX, int(c.font.Ascent), c.fg)
+	c.penX += int(g.XAdvance)
+	c.prev = r
+	c.dirty = true
+	return nil
+}
+
+// advance returns the horizontal advance of the rune in the font.
+func (c *Console[T]) advance(r rune) int {
+	if g := c.font.Glyph(r); g != nil {
+		return int(g.XAdvance)
+	}
+	return 0
+}
+
+// newline moves the cursor to the start of the next line, scrolling the
+// display when the cursor is already on the last line.
+func (c *Console[T]) newline() error {
+	if err := c.flush(); err != nil {
+		return err
+	}
+	c.clearLine()
+	if c.row < c.rows-1 {
+		// The next line was cleared before.
+		c.row++
+		return nil
+	}
+
+	// Scroll the top line to the bottom, after clearing it in display memory.
+	c.offset = (c.offset + c.lineHeight) % (c.rows * c.lineHeight)
+	if err := c.display.DrawBitmap(0, int16(c.lineY()), c.line); err != nil {
+		return err
+	}
+	c.display.SetScroll(int16(c.top + c.offset))
+	return nil
+}
+
+// lineY returns the y coordinate in display memory of the current line.
+func (c *Console[T]) lineY() int {
+	return c.top + (c.offset+c.row*c.lineHeight)%(c.rows*c.lineHeight)
+}
+
+// clearLine clears the current line buffer using the default background color
+// and moves the cursor to the start of the line.
+func (c *Console[T]) clearLine() {
+	bg := c.config.Background
+	c.line.FillSolidColor(pixel.NewColor[T](bg.R, bg.G, bg.B))
+	c.penX = 0
+	c.filled = 0
+	c.prev = -1
+}
+
+// flush sends the current line to the display if it has changed.
+func (c *Console[T]) flush() error {
+	if !c.dirty {
+		return nil
+	}
+	c.dirty = false
+	return c.display.DrawBitmap(0, int16(c.lineY()), c.line)
+}
+
+// control handles a CSI escape sequence with the given final byte.
+func (c *Console[T]) control(final byte) error {
+	switch final {
+	case 'm':
+		c.sgr()
+	case 'K':
+		// Erase in line: only erasing to the end of the line is supported.
+		if c.params[0] == 0 && c.filled < c.width {
+			bg := pixel.NewColor[T](c.bg.R, c.bg.G, c.bg.B)
+			for y := 0; y < c.lineHeight; y++ {
+				for x := c.filled; x < c.width; x++ {
+					c.line.Set(x, y, bg)
+				}
+			}
+			c.filled = c.width
+			c.dirty = true
+		}
+	case 'J':
+		// Erase in display: only erasing the entire display is supported.
+		if c.params[0] == 2 {
+			c.dirty = false
+			return c.Clear()
+		}
+	}
+	return nil
+}
+
+// sgr handles the Select Graphic Rendition escape sequence (ESC [ ... m).
+func (c *Console[T]) sgr() {
+	n := c.nparams
+	if n == 0 {
+		n = 1 // ESC [m is the same as ESC [0m
+	}
+	if n > maxParams {
+		n = maxParams
+	}
+	for i := 0; i < n; i++ {
+		p := c.params[i]
+		switch {
+		case p == 0:
+			c.fg = c.config.Foreground
+			c.bg = c.config.Background
+			c.bold = false
+		case p == 1:
+			c.bold = true
+		case p == 22:
+			c.bold = false
+		case p >= 30 && p <= 37:
+			index := p - 30
+			if c.bold {
+				index += 8
+			}
+			c.fg = Palette[index]
+		case p == 39:
+			c.fg = c.config.Foreground
+		case p >= 40 && p <= 47:
+			c.bg = Palette[p-40]
+		case p == 49:
+			c.bg = c.config.Background
+		case p >= 90 && p <= 97:
+			c.fg = Palette[p-90+8]
+		case p >= 100 && p <= 107:
+			c.bg = Palette[p-100+8]
+		case p == 38 || p == 48:
+			// Extended colors: 5;n (256 colors) or 2;r;g;b (24-bit).
+			var col color.RGBA
+			if i+1 >= n {
+				return
+			}
+			switch c.params[i+1] {
+			case 5:
+				if i+2 >= n {
+					return
+				}
+				col = color256(c.params[i+2])
+				i += 2
+			case 2:
+				if i+4 >= n {
+					return
+				}
+				col = color.RGBA{R: uint8(c.params[i+2]), G: uint8(c.params[i+3]), B: uint8(c.params[i+4]), A: 255}
+				i += 4
+			default:
+				return
+			}
+			if p == 38 {
+				c.fg = col
+			} else {
+				c.bg = col
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package console_test
+
+import (
+	"image/color"
+	"testing"
+
+	"tinygo.org/x/drivers/console"
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// testFont is a tiny 2-bit font with 2x2 glyphs that fill the whole glyph.
+var testFont = &font.Font{
+	BitsPerPixel: 2,
+	Ascent:       2,
+	Descent:      0,
+	YAdvance:     3,
+	Glyphs: []font.Glyph{
+		{Rune: ' ', XAdvance: 3},
+		{Rune: 'A', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b11_11_11_11}},
+		{Rune: 'é', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b11_11_11_11}},
+	},
+}
+
+// fakeDisplay has display memory like a TFT display, and shows it using the
+// current scroll settings.
+type fakeDisplay struct {
+	memory          pixel.Image[pixel.RGB888]
+	topFixed        int
+	bottomFixed     int
+	scroll          int
+	scrollAreaCalls int
+}
+
+func newFakeDisplay(width, height int) *fakeDisplay {
+	return &fakeDisplay{memory: pixel.NewImage[pixel.RGB888](width, height)}
+}
+
+func (d *fakeDisplay) Size() (int16, int16) {
+	width, height := d.memory.Size()
+	return int16(width), int16(height)
+}
+
+func (d *fakeDisplay) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB888]) error {
+	width, height := bitmap.Size()
+	for by := 0; by < height; by++ {
+		for bx := 0; bx < width; bx++ {
+			d.memory.Set(int(x)+bx, int(y)+by, bitmap.Get(bx, by))
+		}
+	}
+	return nil
+}
+
+func (d *fakeDisplay) SetScrollArea(topFixedArea, bottomFixedArea int16) {
+	d.topFixed = int(topFixedArea)
+	d.bottomFixed = int(bottomFixedArea)
+	d.scrollAreaCalls++
+}
+
+func (d *fakeDisplay) SetScroll(line int16) {
+	d.scroll = int(line)
+}
+
+// visible returns the pixel that is shown on screen at x, y.
+func (d *fakeDisplay) visible(x, y int) pixel.RGB888 {
+	_, height := d.memory.Size()
+	scrollHeight := height - d.topFixed - d.bottomFixed
+	if y >= d.topFixed && y < d.topFixed+scrollHeight {
+		y = d.topFixed + (y-d.topFixed+d.scroll-d.topFixed)%scrollHeight
+	}
+	return d.memory.Get(x, y)
+}
+
+var (
+	white = pixel.NewRGB888(255, 255, 255)
+	black = pixel.NewRGB888(0, 0, 0)
+)
+
+func TestScroll(t *testing.T) {
+	// One header line, three scrolled lines, one footer line and one pixel
+	// left over.
+	display := newFakeDisplay(9, 16)
+	c := console.New[pixel.RGB888](display, console.Config{
+		Font:        testFont,
+		HeaderLines: 1,
+		FooterLines: 1,
+	})
+	if display.topFixed != 3 || display.bottomFixed != 4 {
+		t.Fatalf("unexpected scroll area: top %d, bottom %d", display.topFixed, display.bottomFixed)
+	}
+
+	// Fill the screen, without scrolling.
+	c.Write([]byte("A\n A\n  A"))
+	if display.scroll != 3 {
+		t.Errorf("expected no scrolling yet, got scroll %d", display.scroll)
+	}
+	for i, y := range []int{3, 6, 9} {
+		if display.visible(i*3, y) != white || display.visible(i*3, y+2) != black {
+			t.Errorf("expected glyph on line %d", i)
+		}
+	}
+
+	// Adding another line scrolls everything up by one line.
+	c.Write([]byte("\nA"))
+	if display.scroll != 6 {
+		t.Errorf("expected to scroll by one line, got scroll %d", display.scroll)
+	}
+	for i, y := range []int{3, 6} {
+		if display.visible((i+1)*3, y) != white || display.visible(0, y) != black {
+			t.Errorf("expected line %d to be scrolled up", i)
+		}
+	}
+	if display.visible(0, 9) != white || display.visible(6, 9) != black {
+		t.Errorf("expected new line at the bottom")
+	}
+
+	// Long lines wrap, which scrolls the display by two lines and back to
+	// where it started.
+	c.Write([]byte("\nAAAA"))
+	if display.scroll != 3 {
+		t.Errorf("expected to scroll by two lines, got scroll %d", display.scroll)
+	}
+	if display.visible(6, 6) != white || display.visible(0, 9) != white || display.visible(3, 9) != black {
+		t.Errorf("expected long line to wrap")
+	}
+}
+
+func TestCarriageReturn(t *testing.T) {
+	display := newFakeDisplay(9, 16)
+	c := console.New[pixel.RGB888](display, console.Config{Font: testFont})
+
+	// CRLF keeps the line.
+	c.Write([]byte("AA\r\n"))
+	if display.visible(0, 0) != white || display.visible(3, 0) != white {
+		t.Errorf("expected line to be kept after CRLF")
+	}
+
+	// Text after CR overwrites the start of the line, and erasing the line
+	// after CR clears it.
+	c.Write([]byte("AA\r \n"))
+	if display.visible(0, 3) != black || display.visible(3, 3) != white {
+		t.Errorf("expected start of line to be overwritten after CR")
+	}
+	c.Write([]byte("AA\r\x1b[K\n"))
+	if display.visible(0, 6) != black || display.visible(3, 6) != black {
+		t.Errorf("expected line to be erased after CR")
+	}
+}
+
+func TestColors(t *testing.T) {
+	display := newFakeDisplay(9, 3)
+	c := console.New[pixel.RGB888](display, console.Config{Font: testFont})
+
+	// The escape sequences and the UTF-8 character are split over multiple
+	// writes.
+	for _, s := range []string{"\x1b[3", "1mA\x1b[0;44m", "\xc3", "\xa9\x1b[", "m"} {
+		if n, err := c.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q): unexpected result %d, %v", s, n, err)
+		}
+	}
+	red := console.Palette[1]
+	blue := console.Palette[4]
+	for _, tc := range []struct {
+		x, y     int
+		expected color.RGBA
+	}{
+		{0, 0, red},                            // foreground of 'A'
+		{0, 2, color.RGBA{}},                   // default background
+		{3, 0, color.RGBA{255, 255, 255, 255}}, // foreground of 'é'
+		{5, 0, blue},                           // background of 'é'
+		{6, 0, color.RGBA{}},                   // nothing written here
+	} {
+		got := display.visible(tc.x, tc.y)
+		if expected := pixel.NewRGB888(tc.expected.R, tc.expected.G, tc.expected.B); got != expected {
+			t.Errorf("pixel %d, %d: expected %v, got %v", tc.x, tc.y, expected, got)
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	display := newFakeDisplay(9, 9)
+	c := console.New[pixel.RGB888](display, console.Config{
+		Font:        testFont,
+		HeaderLines: 1,
+		FooterLines: 1,
+	})
+	c.SetHeader(0, "A")
+	c.SetFooter(0, " A")
+	// Status lines are inverted by default.
+	if display.visible(0, 0) != black || display.visible(3, 0) != white {
+		t.Errorf("unexpected header")
+	}
+	if display.visible(0, 6) != white || display.visible(3, 6) != black {
+		t.Errorf("unexpected footer")
+	}
+}
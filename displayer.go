@@ -13,6 +13,15 @@ type Displayer interface {
 	Display() error
 }
 
+// Sleeper is a display that can be put in a low power state. Depending on the
+// display this turns off the panel, powers down the controller or puts it in
+// deep sleep. Waking it up again restores the display, although e-paper
+// displays may need a full refresh afterwards.
+type Sleeper interface {
+	// Sleep puts the display in a low power state, or wakes it up again.
+	Sleep(sleepEnabled bool) error
+}
+
 // Rotation is how much a display has been rotated. Displays can be rotated, and
 // sometimes also mirrored.
 type Rotation uint8
@@ -28,3 +37,39 @@ const (
 	Rotation180Mirror
 	Rotation270Mirror
 )
+
+// IsMirrored returns whether the rotation also mirrors the display.
+func (r Rotation) IsMirrored() bool {
+	return r%8 >= Rotation0Mirror
+}
+
+// Size returns the size of a rotated display, given the size of the display
+// without rotation.
+func (r Rotation) Size(width, height int16) (int16, int16) {
+	if r%2 == 1 {
+		return height, width
+	}
+	return width, height
+}
+
+// Transform converts a position on the rotated display to a position on the
+// display without rotation, where width and height are the size without
+// rotation. Mirrored rotations first mirror the x coordinate, then rotate.
+//
+// Drivers for displays that can't rotate in hardware use it to rotate in
+// software.
+func (r Rotation) Transform(x, y, width, height int16) (int16, int16) {
+	if r.IsMirrored() {
+		w, _ := r.Size(width, height)
+		x = w - 1 - x
+	}
+	switch r % 4 {
+	case Rotation90:
+		return width - 1 - y, x
+	case Rotation180:
+		return width - 1 - x, height - 1 - y
+	case Rotation270:
+		return y, height - 1 - x
+	}
+	return x, y
+}
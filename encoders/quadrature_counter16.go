@@ -0,0 +1,47 @@
+package encoders
+
+// Counter16 is a QuadratureCounter for 16-bit hardware counters, such as the
+// pulse counter (PCNT) of the ESP32 or a timer in encoder mode on the STM32.
+// It extends the hardware count to an int, by adding up the differences
+// between reads. The hardware count may be signed or unsigned, and may wrap
+// around, as long as Count is called at least once per 32767 counts.
+type Counter16 struct {
+	configure func() error
+	read      func() uint16
+	last      uint16
+	count     int
+}
+
+// NewCounter16 returns a counter for a 16-bit hardware counter. The configure
+// function sets up the hardware to count the edges of both encoder inputs, and
+// may be nil when this is done already. The read function returns the
+// current hardware count.
+func NewCounter16(configure func() error, read func() uint16) *Counter16 {
+	return &Counter16{configure: configure, read: read}
+}
+
+// Configure configures the hardware counter.
+func (c *Counter16) Configure(cfg QuadratureConfig) error {
+	if c.configure != nil {
+		err := c.configure()
+		if err != nil {
+			return err
+		}
+	}
+	c.last = c.read()
+	return nil
+}
+
+// Count returns the extended count.
+func (c *Counter16) Count() int {
+	value := c.read()
+	c.count += int(int16(value - c.last))
+	c.last = value
+	return c.count
+}
+
+// SetCount overwrites the extended count. The hardware count isn't changed.
+func (c *Counter16) SetCount(count int) {
+	c.last = c.read()
+	c.count = count
+}
@@ -1,34 +1,283 @@
 package encoders
 
+import (
+	"errors"
+	"time"
+)
+
+var errNoIndex = errors.New("encoders: counter has no index input")
+
+// minWindowCounts is the number of counts in a velocity window above which the
+// velocity is the number of counts divided by the window. Below it, the
+// velocity is the inverse of the time between counts.
+const minWindowCounts = 4
+
 type QuadratureDevice struct {
 	cfg  QuadratureConfig
-	impl quadratureImpl
+	impl QuadratureCounter
+	now  func() time.Time
+
+	homing       bool
+	lastPosition int // position at the previous call to Steps
+
+	// Velocity estimation.
+	velocity    float32
+	windowCount int
+	windowStart time.Time
+
+	// Last observed change of the count, for counters that don't time the
+	// counts themselves.
+	edgeCount  int
+	edgeTime   time.Time
+	edgePeriod time.Duration
 }
 
 type QuadratureConfig struct {
+	// Precision is the number of counts per position, 4 by default. For
+	// rotary knobs, this is the number of counts per detent, so that the
+	// position changes by one for every click.
 	Precision int
+
+	// CountsPerRevolution is the number of counts per revolution of the
+	// shaft, which is four times the number of pulses per revolution of an
+	// encoder. It is only needed for RPM.
+	CountsPerRevolution int
+
+	// VelocityWindow is the time over which velocity is measured, 50ms by
+	// default. At low speed, with fewer than 4 counts per window, the
+	// velocity is measured from the time between counts instead.
+	VelocityWindow time.Duration
+
+	// VelocityTimeout is the time without counts after which the velocity is
+	// zero, 1s by default.
+	VelocityTimeout time.Duration
+
+	// AccelerationSpeed enables acceleration for Steps: for every
+	// AccelerationSpeed positions per second, every position turned counts
+	// as one more step. It is disabled by default.
+	AccelerationSpeed float32
+
+	// MaxAcceleration limits the number of steps per position with
+	// acceleration, 10 by default.
+	MaxAcceleration int
+}
+
+// QuadratureCounter counts the edges of a quadrature encoder. It is
+// implemented by the backends of a QuadratureDevice, and can be implemented
+// outside of this package to use a hardware counter.
+type QuadratureCounter interface {
+	// Configure prepares the counter for counting.
+	Configure(cfg QuadratureConfig) error
+
+	// Count returns the number of counts: four per pulse, increasing in one
+	// direction and decreasing in the other.
+	Count() int
+
+	// SetCount overwrites the number of counts.
+	SetCount(count int)
 }
 
-type quadratureImpl interface {
-	configure(cfg QuadratureConfig) error
-	readValue() int
-	writeValue(int)
+// QuadratureEdgeTimer is implemented by counters that record the time of the
+// last count. This makes the velocity accurate at low speed, where it is
+// measured from the time between counts.
+type QuadratureEdgeTimer interface {
+	// LastEdge returns the time of the last count, and the time between the
+	// last two counts, which is negative when the count decreased. The
+	// period is 0 before the second count.
+	LastEdge() (t time.Time, period time.Duration)
+}
+
+// QuadratureIndexer is implemented by counters with an index input (Z), which
+// has a pulse at one position per revolution.
+type QuadratureIndexer interface {
+	// ArmIndex makes the counter set its count to the given value on the
+	// next index pulse.
+	ArmIndex(count int)
+
+	// Indexed returns whether there was an index pulse since ArmIndex.
+	Indexed() bool
+}
+
+// NewQuadrature returns an encoder device that uses the given counter, for
+// example a hardware counter.
+func NewQuadrature(counter QuadratureCounter) *QuadratureDevice {
+	return &QuadratureDevice{impl: counter}
 }
 
 func (enc *QuadratureDevice) Configure(cfg QuadratureConfig) error {
 	if cfg.Precision < 1 {
 		cfg.Precision = 4
 	}
+	if cfg.VelocityWindow <= 0 {
+		cfg.VelocityWindow = 50 * time.Millisecond
+	}
+	if cfg.VelocityTimeout <= 0 {
+		cfg.VelocityTimeout = time.Second
+	}
+	if cfg.MaxAcceleration < 1 {
+		cfg.MaxAcceleration = 10
+	}
 	enc.cfg = cfg
-	return enc.impl.configure(cfg)
+	err := enc.impl.Configure(cfg)
+	if err != nil {
+		return err
+	}
+	enc.reset()
+	return nil
 }
 
 // Position returns the stored int value for the encoder
 func (enc *QuadratureDevice) Position() int {
-	return enc.impl.readValue() / enc.cfg.Precision
+	return enc.impl.Count() / enc.cfg.Precision
 }
 
 // SetPosition overwrites the currently stored value with the specified int value
 func (enc *QuadratureDevice) SetPosition(v int) {
-	enc.impl.writeValue(v * enc.cfg.Precision)
+	enc.impl.SetCount(v * enc.cfg.Precision)
+	enc.reset()
+}
+
+// Steps returns the number of positions turned since the previous call, which
+// is convenient for knobs that adjust a value. With acceleration, turning
+// quickly returns more steps.
+func (enc *QuadratureDevice) Steps() int {
+	enc.checkHomed()
+	position := enc.Position()
+	steps := position - enc.lastPosition
+	enc.lastPosition = position
+	if steps == 0 || enc.cfg.AccelerationSpeed <= 0 {
+		return steps
+	}
+	speed := enc.Velocity() / float32(enc.cfg.Precision)
+	if speed < 0 {
+		speed = -speed
+	}
+	factor := 1 + int(speed/enc.cfg.AccelerationSpeed)
+	return steps * min(factor, enc.cfg.MaxAcceleration)
+}
+
+// Velocity returns the velocity in counts per second, which is negative when
+// the count decreases. It is updated at most once per velocity window, and
+// must be called regularly to keep track of slow movement.
+func (enc *QuadratureDevice) Velocity() float32 {
+	now := enc.now()
+	count := enc.impl.Count()
+	if enc.checkHomed() {
+		return enc.velocity
+	}
+	enc.observe(count, now)
+
+	elapsed := now.Sub(enc.windowStart)
+	if elapsed < enc.cfg.VelocityWindow {
+		return enc.velocity
+	}
+	counts := count - enc.windowCount
+	enc.windowCount = count
+	enc.windowStart = now
+	if counts >= minWindowCounts || counts <= -minWindowCounts {
+		enc.velocity = float32(counts) / float32(elapsed.Seconds())
+		return enc.velocity
+	}
+
+	// Too few counts to measure over the window, so use the time between
+	// counts.
+	edge, period := enc.edgeTime, enc.edgePeriod
+	if timer, ok := enc.impl.(QuadratureEdgeTimer); ok {
+		edge, period = timer.LastEdge()
+	}
+	enc.velocity = 0
+	if period == 0 {
+		return 0
+	}
+	since := now.Sub(edge)
+	if since >= enc.cfg.VelocityTimeout {
+		return 0
+	}
+	interval := period
+	if interval < 0 {
+		interval = -interval
+	}
+	if since > interval {
+		// No count for longer than the last period, so the encoder is
+		// slowing down.
+		interval = since
+	}
+	enc.velocity = float32(time.Second) / float32(interval)
+	if period < 0 {
+		enc.velocity = -enc.velocity
+	}
+	return enc.velocity
+}
+
+// RPM returns the velocity in revolutions per minute, which is negative when
+// the count decreases. It is 0 when CountsPerRevolution isn't configured.
+func (enc *QuadratureDevice) RPM() float32 {
+	if enc.cfg.CountsPerRevolution <= 0 {
+		return 0
+	}
+	return enc.Velocity() * 60 / float32(enc.cfg.CountsPerRevolution)
+}
+
+// Home makes the encoder set its position to the given value on the next
+// index pulse. It returns an error when the counter has no index input.
+func (enc *QuadratureDevice) Home(position int) error {
+	indexer, ok := enc.impl.(QuadratureIndexer)
+	if !ok {
+		return errNoIndex
+	}
+	enc.homing = true
+	indexer.ArmIndex(position * enc.cfg.Precision)
+	return nil
+}
+
+// Homed returns whether the position was set by an index pulse since the last
+// call to Home.
+func (enc *QuadratureDevice) Homed() bool {
+	indexer, ok := enc.impl.(QuadratureIndexer)
+	return ok && indexer.Indexed()
+}
+
+// checkHomed returns whether an index pulse ended homing since the last call.
+// The count then jumped to the home position, so Steps and the velocity
+// estimation start over.
+func (enc *QuadratureDevice) checkHomed() bool {
+	if !enc.homing {
+		return false
+	}
+	if indexer, ok := enc.impl.(QuadratureIndexer); ok && indexer.Indexed() {
+		enc.homing = false
+		enc.reset()
+		return true
+	}
+	return false
+}
+
+// observe keeps track of the time between counts, for counters that don't
+// record it themselves.
+func (enc *QuadratureDevice) observe(count int, now time.Time) {
+	counts := count - enc.edgeCount
+	if counts == 0 {
+		return
+	}
+	if !enc.edgeTime.IsZero() {
+		enc.edgePeriod = now.Sub(enc.edgeTime) / time.Duration(counts)
+	}
+	enc.edgeCount = count
+	enc.edgeTime = now
+}
+
+// reset restarts the velocity estimation and Steps after the count changed
+// without movement.
+func (enc *QuadratureDevice) reset() {
+	if enc.now == nil {
+		enc.now = time.Now
+	}
+	count := enc.impl.Count()
+	enc.lastPosition = enc.Position()
+	enc.velocity = 0
+	enc.windowCount = count
+	enc.windowStart = enc.now()
+	enc.edgeCount = count
+	enc.edgeTime = time.Time{}
+	enc.edgePeriod = 0
 }
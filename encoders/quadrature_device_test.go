@@ -0,0 +1,200 @@
+package encoders
+
+import (
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/internal/fakeclock"
+)
+
+type fakeCounter struct {
+	count   int
+	armed   bool
+	home    int
+	indexed bool
+}
+
+func (c *fakeCounter) Configure(cfg QuadratureConfig) error { return nil }
+func (c *fakeCounter) Count() int                           { return c.count }
+func (c *fakeCounter) SetCount(count int)                   { c.count = count }
+
+func (c *fakeCounter) index() {
+	if c.armed {
+		c.count = c.home
+		c.armed = false
+		c.indexed = true
+	}
+}
+
+type fakeIndexer struct{ *fakeCounter }
+
+func (c fakeIndexer) ArmIndex(count int) { c.home, c.armed, c.indexed = count, true, false }
+func (c fakeIndexer) Indexed() bool      { return c.indexed }
+
+func newTestDevice(t *testing.T, counter QuadratureCounter, cfg QuadratureConfig) (*QuadratureDevice, *fakeclock.Clock) {
+	t.Helper()
+	clock := fakeclock.New()
+	enc := NewQuadrature(counter)
+	enc.now = clock.Now
+	if err := enc.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return enc, clock
+}
+
+func TestPosition(t *testing.T) {
+	counter := &fakeCounter{}
+	enc, _ := newTestDevice(t, counter, QuadratureConfig{})
+	for _, tc := range []struct{ count, position int }{
+		{0, 0}, {3, 0}, {4, 1}, {-1, 0}, {-4, -1}, {-5, -1},
+	} {
+		counter.count = tc.count
+		if got := enc.Position(); got != tc.position {
+			t.Errorf("count %d: got position %d, want %d", tc.count, got, tc.position)
+		}
+	}
+	enc.SetPosition(-3)
+	if counter.count != -12 {
+		t.Errorf("SetPosition(-3): got count %d, want -12", counter.count)
+	}
+}
+
+func TestVelocity(t *testing.T) {
+	counter := &fakeCounter{}
+	enc, clock := newTestDevice(t, counter, QuadratureConfig{CountsPerRevolution: 100})
+
+	// High speed: 20 counts per 50ms window.
+	for i := 0; i < 5; i++ {
+		clock.Advance(10 * time.Millisecond)
+		counter.count += 4
+		enc.Velocity()
+	}
+	if got := enc.Velocity(); got != 400 {
+		t.Errorf("high speed: got %v counts/s, want 400", got)
+	}
+	if got := enc.RPM(); got != 240 {
+		t.Errorf("high speed: got %v RPM, want 240", got)
+	}
+
+	// Low speed backwards: one count every 100ms, measured from the time
+	// between counts.
+	for i := 0; i < 10; i++ {
+		clock.Advance(50 * time.Millisecond)
+		if i%2 == 0 {
+			counter.count--
+		}
+		enc.Velocity()
+	}
+	if got := enc.Velocity(); got != -10 {
+		t.Errorf("low speed: got %v counts/s, want -10", got)
+	}
+
+	// Slowing down, and stopped after the timeout.
+	clock.Advance(200 * time.Millisecond)
+	if got := enc.Velocity(); got != -4 {
+		t.Errorf("slowing down: got %v counts/s, want -4", got)
+	}
+	clock.Advance(time.Second)
+	if got := enc.Velocity(); got != 0 {
+		t.Errorf("stopped: got %v counts/s, want 0", got)
+	}
+}
+
+type fakeEdgeTimer struct {
+	*fakeCounter
+	edge   time.Time
+	period time.Duration
+}
+
+func (c *fakeEdgeTimer) LastEdge() (time.Time, time.Duration) { return c.edge, c.period }
+
+func TestVelocityEdgeTimer(t *testing.T) {
+	counter := &fakeEdgeTimer{fakeCounter: &fakeCounter{}}
+	enc, clock := newTestDevice(t, counter, QuadratureConfig{})
+	clock.Advance(60 * time.Millisecond)
+	counter.count = 2
+	counter.edge = clock.Now().Add(-5 * time.Millisecond)
+	counter.period = 20 * time.Millisecond
+	if got := enc.Velocity(); got != 50 {
+		t.Errorf("got %v counts/s, want 50", got)
+	}
+}
+
+func TestSteps(t *testing.T) {
+	counter := &fakeCounter{}
+	enc, clock := newTestDevice(t, counter, QuadratureConfig{AccelerationSpeed: 5, MaxAcceleration: 3})
+
+	// Slow: one detent.
+	clock.Advance(time.Second)
+	counter.count = 4
+	if got := enc.Steps(); got != 1 {
+		t.Errorf("slow: got %d steps, want 1", got)
+	}
+
+	// 2 detents in 50ms is 40 detents per second, limited to 3 steps per
+	// detent.
+	clock.Advance(50 * time.Millisecond)
+	counter.count = 12
+	if got := enc.Steps(); got != 6 {
+		t.Errorf("fast: got %d steps, want 6", got)
+	}
+	if got := enc.Steps(); got != 0 {
+		t.Errorf("unchanged: got %d steps, want 0", got)
+	}
+}
+
+func TestHome(t *testing.T) {
+	enc, _ := newTestDevice(t, &fakeCounter{}, QuadratureConfig{})
+	if err := enc.Home(0); err != errNoIndex {
+		t.Errorf("Home without index: got error %v, want %v", err, errNoIndex)
+	}
+
+	counter := fakeIndexer{&fakeCounter{count: 37}}
+	enc, _ = newTestDevice(t, counter, QuadratureConfig{})
+	if err := enc.Home(10); err != nil {
+		t.Fatal(err)
+	}
+	if enc.Homed() {
+		t.Error("homed before index pulse")
+	}
+	counter.index()
+	if !enc.Homed() || enc.Position() != 10 {
+		t.Errorf("after index pulse: got homed %v, position %d, want true, 10", enc.Homed(), enc.Position())
+	}
+	if got := enc.Velocity(); got != 0 {
+		t.Errorf("got velocity %v after homing, want 0", got)
+	}
+
+	// Steps doesn't count the jump to the home position.
+	counter = fakeIndexer{&fakeCounter{count: 37}}
+	enc, _ = newTestDevice(t, counter, QuadratureConfig{})
+	enc.Home(0)
+	counter.index()
+	if got := enc.Steps(); got != 0 {
+		t.Errorf("got %d steps after homing, want 0", got)
+	}
+}
+
+func TestCounter16(t *testing.T) {
+	var hw uint16 = 0xfff0
+	c := NewCounter16(nil, func() uint16 { return hw })
+	if err := c.Configure(QuadratureConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		hw    uint16
+		count int
+	}{
+		{0xfffe, 14}, {0x0010, 32}, {0xffff, 15}, {0x8000, -32752},
+	} {
+		hw = tc.hw
+		if got := c.Count(); got != tc.count {
+			t.Errorf("hardware count %#x: got %d, want %d", tc.hw, got, tc.count)
+		}
+	}
+	c.SetCount(100)
+	hw += 3
+	if got := c.Count(); got != 103 {
+		t.Errorf("after SetCount: got %d, want 103", got)
+	}
+}
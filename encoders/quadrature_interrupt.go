@@ -10,7 +10,9 @@ package encoders
 
 import (
 	"machine"
+	"runtime/interrupt"
 	"runtime/volatile"
+	"time"
 )
 
 var (
@@ -23,7 +25,17 @@ var (
 // This constructur is only available for TinyGo targets for which machine.PinToggle
 // is defined as a valid interrupt type.
 func NewQuadratureViaInterrupt(pinA, pinB machine.Pin) *QuadratureDevice {
-	return &QuadratureDevice{impl: &quadInterruptImpl{pinA: pinA, pinB: pinB, oldAB: 0b00000011}}
+	return NewQuadrature(&quadInterruptImpl{pinA: pinA, pinB: pinB, oldAB: 0b00000011})
+}
+
+// NewQuadratureWithIndexViaInterrupt returns a rotary encoder device like
+// NewQuadratureViaInterrupt, with an index input (Z) for homing with
+// QuadratureDevice.Home.
+func NewQuadratureWithIndexViaInterrupt(pinA, pinB, pinZ machine.Pin) *QuadratureDevice {
+	return NewQuadrature(&quadInterruptIndexImpl{
+		quadInterruptImpl: quadInterruptImpl{pinA: pinA, pinB: pinB, oldAB: 0b00000011},
+		pinZ:              pinZ,
+	})
 }
 
 type quadInterruptImpl struct {
@@ -34,9 +46,14 @@ type quadInterruptImpl struct {
 
 	oldAB int
 	value volatile.Register32
+
+	// Time of the last count and between the last two counts, for the
+	// velocity at low speed.
+	lastEdge time.Time
+	period   time.Duration
 }
 
-func (enc *quadInterruptImpl) configure(cfg QuadratureConfig) error {
+func (enc *quadInterruptImpl) Configure(cfg QuadratureConfig) error {
 	enc.pinA.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
 	enc.pinA.SetInterrupt(machine.PinToggle, enc.interrupt)
 
@@ -55,15 +72,77 @@ func (enc *quadInterruptImpl) interrupt(pin machine.Pin) {
 	if bHigh {
 		enc.oldAB |= 1
 	}
-	enc.writeValue(enc.readValue() + int(states[enc.oldAB&0x0f]))
+	delta := states[enc.oldAB&0x0f]
+	if delta == 0 {
+		return
+	}
+	enc.SetCount(enc.Count() + int(delta))
+
+	now := time.Now()
+	if !enc.lastEdge.IsZero() {
+		enc.period = now.Sub(enc.lastEdge)
+		if delta < 0 {
+			enc.period = -enc.period
+		}
+	}
+	enc.lastEdge = now
 }
 
-// readValue gets the value using volatile operations and returns it as an int
-func (enc *quadInterruptImpl) readValue() int {
-	return int(enc.value.Get())
+// Count gets the value using volatile operations and returns it as an int
+func (enc *quadInterruptImpl) Count() int {
+	return int(int32(enc.value.Get()))
 }
 
-// writeValue set the value to the specified int using volatile operations
-func (enc *quadInterruptImpl) writeValue(v int) {
+// SetCount set the value to the specified int using volatile operations
+func (enc *quadInterruptImpl) SetCount(v int) {
 	enc.value.Set(uint32(v))
 }
+
+// LastEdge returns the time of the last count and the time between the last
+// two counts.
+func (enc *quadInterruptImpl) LastEdge() (time.Time, time.Duration) {
+	mask := interrupt.Disable()
+	t, period := enc.lastEdge, enc.period
+	interrupt.Restore(mask)
+	return t, period
+}
+
+type quadInterruptIndexImpl struct {
+	quadInterruptImpl
+
+	pinZ      machine.Pin
+	homeValue uint32
+	armed     volatile.Register8
+	indexed   volatile.Register8
+}
+
+func (enc *quadInterruptIndexImpl) Configure(cfg QuadratureConfig) error {
+	err := enc.quadInterruptImpl.Configure(cfg)
+	if err != nil {
+		return err
+	}
+	enc.pinZ.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	return enc.pinZ.SetInterrupt(machine.PinRising, enc.index)
+}
+
+func (enc *quadInterruptIndexImpl) index(pin machine.Pin) {
+	if enc.armed.Get() != 0 {
+		enc.value.Set(enc.homeValue)
+		enc.armed.Set(0)
+		enc.indexed.Set(1)
+	}
+}
+
+// ArmIndex sets the count to the given value on the next index pulse.
+func (enc *quadInterruptIndexImpl) ArmIndex(count int) {
+	mask := interrupt.Disable()
+	enc.homeValue = uint32(count)
+	enc.indexed.Set(0)
+	enc.armed.Set(1)
+	interrupt.Restore(mask)
+}
+
+// Indexed returns whether there was an index pulse since ArmIndex.
+func (enc *quadInterruptIndexImpl) Indexed() bool {
+	return enc.indexed.Get() != 0
+}
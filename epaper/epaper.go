@@ -0,0 +1,314 @@
+// Package epaper manages refreshes of e-paper displays.
+//
+// E-paper displays can update parts of the screen quickly using a partial
+// refresh, but every partial refresh leaves a bit of ghosting behind. A full
+// refresh (where the screen flashes a few times) removes the ghosting again.
+// Refreshes also take a long time, up to several seconds, during which the
+// display can't accept new updates.
+//
+// A Manager keeps track of all this: it collects the areas of the screen that
+// have changed, merges them into a few rectangles, uses partial refreshes where
+// possible with a full refresh every few updates, and powers down the display
+// when it's idle. It never waits for the display itself: call Update regularly
+// (for example from the main loop) to start the next refresh once the display
+// is ready, or call Flush to wait for all changes to be shown.
+//
+// The manager works with the uc8151 and the waveshare-epd epd2in13, epd2in9 and
+// epd4in2 drivers. They must be configured to not block on refreshes (see
+// SetBlocking on the uc8151 and epd4in2), otherwise Update will block as well.
+package epaper // import "tinygo.org/x/drivers/epaper"
+
+import (
+	"time"
+)
+
+// Displayer is an e-paper display with a framebuffer that supports partial
+// refreshes.
+type Displayer interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+
+	// Display sends the whole framebuffer to the display and starts a refresh.
+	Display() error
+
+	// DisplayRect sends part of the framebuffer to the display and starts a
+	// refresh of only that part. The horizontal coordinates may be rounded to
+	// a multiple of 8 by the driver.
+	DisplayRect(x, y, width, height int16) error
+
+	// SetFullRefresh selects the waveform for the next refresh: a slow full
+	// refresh that removes ghosting, or a fast partial refresh.
+	SetFullRefresh(full bool)
+
+	// IsBusy returns whether the display is still busy refreshing.
+	IsBusy() bool
+
+	// Sleep puts the display in a low power state, or wakes it up again.
+	Sleep(sleepEnabled bool) error
+}
+
+// Config is the configuration of a Manager.
+type Config struct {
+	// Number of partial refreshes after which a full refresh is done, to get
+	// rid of ghosting. The default is 10. Set to -1 to never do a full refresh
+	// automatically.
+	FullRefreshEvery int
+
+	// Power down the display after each refresh. The display is woken up
+	// again for the next refresh, which is then always a full refresh as
+	// the display may have lost its state.
+	PowerDown bool
+
+	// Time between checks of the busy state in Wait and Flush. The default is
+	// 10ms.
+	PollInterval time.Duration
+
+	// Time after starting a refresh before the busy state is checked, as
+	// displays only signal busy a short while after the refresh command. The
+	// default is 100ms. Set to -1 to check right away.
+	SettleTime time.Duration
+}
+
+// Rect is a rectangle on the screen.
+type Rect struct {
+	X, Y, Width, Height int16
+}
+
+// Empty returns whether the rectangle has no area.
+func (r Rect) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Union returns the smallest rectangle that contains both rectangles.
+func (r Rect) Union(s Rect) Rect {
+	if r.Empty() {
+		return s
+	}
+	if s.Empty() {
+		return r
+	}
+	x0, y0 := min(r.X, s.X), min(r.Y, s.Y)
+	x1, y1 := max(r.X+r.Width, s.X+s.Width), max(r.Y+r.Height, s.Y+s.Height)
+	return Rect{x0, y0, x1 - x0, y1 - y0}
+}
+
+// Overlaps returns whether the rectangles overlap or touch.
+func (r Rect) Overlaps(s Rect) bool {
+	return r.X <= s.X+s.Width && s.X <= r.X+r.Width &&
+		r.Y <= s.Y+s.Height && s.Y <= r.Y+r.Height
+}
+
+// area returns the area of the rectangle.
+func (r Rect) area() int {
+	if r.Empty() {
+		return 0
+	}
+	return int(r.Width) * int(r.Height)
+}
+
+// Maximum number of separate dirty rectangles. More rectangles are merged.
+const maxRects = 4
+
+// Manager schedules refreshes of an e-paper display.
+type Manager struct {
+	display Displayer
+	config  Config
+	width   int16
+	height  int16
+
+	rects  [maxRects]Rect
+	nrects int
+
+	partialCount int  // partial refreshes since the last full refresh
+	fullPending  bool // next refresh must be a full refresh
+	refreshing   bool // a refresh was started and may not have finished yet
+	sleeping     bool
+	started      time.Time // start of the last refresh
+
+	now func() time.Time
+}
+
+// New returns a new Manager for the given display. The first refresh is always
+// a full refresh.
+func New(display Displayer, config Config) *Manager {
+	if config.FullRefreshEvery == 0 {
+		config.FullRefreshEvery = 10
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 10 * time.Millisecond
+	}
+	if config.SettleTime == 0 {
+		config.SettleTime = 100 * time.Millisecond
+	}
+	width, height := display.Size()
+	return &Manager{
+		display:     display,
+		config:      config,
+		width:       width,
+		height:      height,
+		fullPending: true,
+		now:         time.Now,
+	}
+}
+
+// Invalidate marks the given area of the framebuffer as changed, so that it
+// will be refreshed on the next Update.
+func (m *Manager) Invalidate(x, y, width, height int16) {
+	r := Rect{x, y, width, height}
+
+	// Clip to the screen.
+	if r.X < 0 {
+		r.Width += r.X
+		r.X = 0
+	}
+	if r.Y < 0 {
+		r.Height += r.Y
+		r.Y = 0
+	}
+	r.Width = min(r.Width, m.width-r.X)
+	r.Height = min(r.Height, m.height-r.Y)
+	if r.Empty() {
+		return
+	}
+
+	// Merge with all rectangles it overlaps with. Merging may cause the result
+	// to overlap with other rectangles, so repeat until nothing changes.
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < m.nrects; i++ {
+			if r.Overlaps(m.rects[i]) {
+				r = r.Union(m.rects[i])
+				m.removeRect(i)
+				merged = true
+				break
+			}
+		}
+	}
+
+	if m.nrects == maxRects {
+		// No room left: merge the rectangle with the one that grows the
+		// least.
+		best, bestGrowth := 0, -1
+		for i := 0; i < m.nrects; i++ {
+			u := r.Union(m.rects[i])
+			growth := u.area() - r.area() - m.rects[i].area()
+			if bestGrowth < 0 || growth < bestGrowth {
+				best, bestGrowth = i, growth
+			}
+		}
+		r = r.Union(m.rects[best])
+		m.removeRect(best)
+	}
+	m.rects[m.nrects] = r
+	m.nrects++
+}
+
+// InvalidateAll marks the whole screen as changed. The screen is refreshed with
+// a partial refresh, unless a full refresh is due. Use ForceFullRefresh for a
+// full refresh.
+func (m *Manager) InvalidateAll() {
+	m.nrects = 0
+	m.Invalidate(0, 0, m.width, m.height)
+}
+
+// removeRect removes the dirty rectangle at index i.
+func (m *Manager) removeRect(i int) {
+	copy(m.rects[i:m.nrects], m.rects[i+1:m.nrects])
+	m.nrects--
+}
+
+// Dirty returns the areas of the screen that still need to be refreshed.
+func (m *Manager) Dirty() []Rect {
+	return m.rects[:m.nrects]
+}
+
+// ForceFullRefresh makes the next refresh a full refresh of the whole screen,
+// to remove any ghosting.
+func (m *Manager) ForceFullRefresh() {
+	m.fullPending = true
+	m.InvalidateAll()
+}
+
+// Pending returns whether there are changes that haven't been sent to the
+// display yet.
+func (m *Manager) Pending() bool {
+	return m.nrects != 0
+}
+
+// Busy returns whether the display is busy refreshing.
+func (m *Manager) Busy() bool {
+	if !m.refreshing {
+		return false
+	}
+	if m.now().Sub(m.started) < m.config.SettleTime {
+		return true
+	}
+	return m.display.IsBusy()
+}
+
+// Update starts the next refresh if there are pending changes and the display
+// is ready. It doesn't wait for the refresh to finish, so it should be called
+// regularly until Pending returns false. When there's nothing left to do, the
+// display is powered down if configured.
+func (m *Manager) Update() error {
+	if m.Busy() {
+		return nil
+	}
+	m.refreshing = false
+
+	if m.nrects == 0 {
+		if m.config.PowerDown && !m.sleeping {
+			m.sleeping = true
+			return m.display.Sleep(true)
+		}
+		return nil
+	}
+
+	if m.sleeping {
+		if err := m.display.Sleep(false); err != nil {
+			return err
+		}
+		m.sleeping = false
+		m.fullPending = true
+	}
+
+	full := m.fullPending ||
+		(m.config.FullRefreshEvery > 0 && m.partialCount >= m.config.FullRefreshEvery)
+
+	m.refreshing = true
+	m.started = m.now()
+	if full {
+		m.display.SetFullRefresh(true)
+		m.nrects = 0
+		m.fullPending = false
+		m.partialCount = 0
+		return m.display.Display()
+	}
+
+	r := m.rects[0]
+	m.removeRect(0)
+	m.partialCount++
+	m.display.SetFullRefresh(false)
+	return m.display.DisplayRect(r.X, r.Y, r.Width, r.Height)
+}
+
+// Wait blocks until the display has finished the current refresh.
+func (m *Manager) Wait() {
+	for m.Busy() {
+		time.Sleep(m.config.PollInterval)
+	}
+}
+
+// Flush sends all pending changes to the display and waits until they are
+// shown. When configured, the display is powered down afterwards.
+func (m *Manager) Flush() error {
+	for {
+		m.Wait()
+		if err := m.Update(); err != nil {
+			return err
+		}
+		if !m.refreshing {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package epaper_test
+
+import (
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/epaper"
+)
+
+// fakeDisplay records refreshes. Each refresh keeps the display busy for a
+// number of IsBusy calls.
+type fakeDisplay struct {
+	full      bool
+	busy      int
+	sleeping  bool
+	refreshes []string
+	rects     []epaper.Rect
+}
+
+func (d *fakeDisplay) Size() (int16, int16) {
+	return 128, 64
+}
+
+func (d *fakeDisplay) Display() error {
+	d.refresh("full")
+	return nil
+}
+
+func (d *fakeDisplay) DisplayRect(x, y, width, height int16) error {
+	if d.full {
+		d.refresh("full-rect")
+	} else {
+		d.refresh("partial")
+	}
+	d.rects = append(d.rects, epaper.Rect{x, y, width, height})
+	return nil
+}
+
+func (d *fakeDisplay) refresh(kind string) {
+	if d.busy > 0 {
+		panic("refresh while busy")
+	}
+	if d.sleeping {
+		panic("refresh while sleeping")
+	}
+	if kind == "full" && !d.full {
+		kind = "full-partial"
+	}
+	d.refreshes = append(d.refreshes, kind)
+	d.busy = 2
+}
+
+func (d *fakeDisplay) SetFullRefresh(full bool) {
+	d.full = full
+}
+
+func (d *fakeDisplay) IsBusy() bool {
+	if d.busy > 0 {
+		d.busy--
+		return true
+	}
+	return false
+}
+
+func (d *fakeDisplay) Sleep(sleepEnabled bool) error {
+	d.sleeping = sleepEnabled
+	return nil
+}
+
+func TestInvalidate(t *testing.T) {
+	m := epaper.New(&fakeDisplay{}, epaper.Config{})
+
+	m.Invalidate(0, 0, 8, 8)
+	m.Invalidate(4, 4, 8, 8)     // overlaps the first
+	m.Invalidate(100, 50, 50, 8) // clipped to the screen
+	if dirty := m.Dirty(); len(dirty) != 2 ||
+		dirty[0] != (epaper.Rect{0, 0, 12, 12}) ||
+		dirty[1] != (epaper.Rect{100, 50, 28, 8}) {
+		t.Errorf("unexpected dirty rectangles: %v", dirty)
+	}
+
+	// Too many separate rectangles get merged.
+	for i := int16(0); i < 4; i++ {
+		m.Invalidate(i*20, 30, 2, 2)
+	}
+	if dirty := m.Dirty(); len(dirty) != 4 {
+		t.Errorf("expected 4 dirty rectangles, got %v", dirty)
+	}
+
+	m.InvalidateAll()
+	if dirty := m.Dirty(); len(dirty) != 1 || dirty[0] != (epaper.Rect{0, 0, 128, 64}) {
+		t.Errorf("unexpected dirty rectangles: %v", dirty)
+	}
+}
+
+func TestRefreshPolicy(t *testing.T) {
+	display := &fakeDisplay{}
+	m := epaper.New(display, epaper.Config{FullRefreshEvery: 2, SettleTime: -1})
+
+	// The first refresh is a full refresh.
+	m.Invalidate(0, 0, 8, 8)
+	if err := m.Flush(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// Then partial refreshes, until a full refresh is needed.
+	for i := 0; i < 3; i++ {
+		m.Invalidate(8, 8, 8, 8)
+		m.Flush()
+	}
+
+	// And a full refresh when asked for.
+	m.ForceFullRefresh()
+	m.Flush()
+
+	// Changing the whole screen still uses a partial refresh.
+	m.InvalidateAll()
+	m.Flush()
+	if last := display.rects[len(display.rects)-1]; last != (epaper.Rect{0, 0, 128, 64}) {
+		t.Errorf("expected a refresh of the whole screen, got %v", last)
+	}
+
+	expected := []string{"full", "partial", "partial", "full", "full", "partial"}
+	if len(display.refreshes) != len(expected) {
+		t.Fatalf("expected refreshes %v, got %v", expected, display.refreshes)
+	}
+	for i := range expected {
+		if display.refreshes[i] != expected[i] {
+			t.Errorf("expected refreshes %v, got %v", expected, display.refreshes)
+			break
+		}
+	}
+}
+
+func TestAsyncUpdate(t *testing.T) {
+	display := &fakeDisplay{}
+	m := epaper.New(display, epaper.Config{PowerDown: true, SettleTime: -1})
+	m.InvalidateAll()
+	m.Update()
+	m.Invalidate(0, 0, 8, 8)
+
+	// Update doesn't start a new refresh while the display is busy.
+	m.Update()
+	if len(display.refreshes) != 1 || !m.Pending() {
+		t.Fatalf("expected a single refresh, got %v", display.refreshes)
+	}
+	for m.Pending() {
+		m.Update()
+	}
+	if len(display.refreshes) != 2 || display.refreshes[1] != "partial" {
+		t.Fatalf("expected a partial refresh, got %v", display.refreshes)
+	}
+
+	// Once idle, the display is powered down. Waking it up again forces a
+	// full refresh.
+	m.Flush()
+	if !display.sleeping {
+		t.Errorf("expected display to be powered down")
+	}
+	m.Invalidate(0, 0, 8, 8)
+	m.Flush()
+	if last := display.refreshes[len(display.refreshes)-1]; last != "full" {
+		t.Errorf("expected a full refresh after waking up, got %s", last)
+	}
+}
+
+func TestSettleTime(t *testing.T) {
+	display := &fakeDisplay{}
+	m := epaper.New(display, epaper.Config{SettleTime: 20 * time.Millisecond})
+	m.InvalidateAll()
+	m.Update()
+
+	// The busy state isn't checked right after starting a refresh.
+	if !m.Busy() || display.busy != 2 {
+		t.Errorf("expected busy without checking the display")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !m.Busy() || display.busy != 1 {
+		t.Errorf("expected the display to be checked after the settle time")
+	}
+	m.Wait()
+	if m.Busy() {
+		t.Errorf("expected refresh to be done")
+	}
+}
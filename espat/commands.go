@@ -108,4 +108,7 @@ const (
 
 	// Set timeout when ESP8266/ESP32 runs as TCP server
 	SetServerTimeout = "+CIPSTO"
+
+	// Show the remote IP and port with received data
+	ShowRemoteInfo = "+CIPDINFO"
 )
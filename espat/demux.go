@@ -0,0 +1,255 @@
+package espat
+
+import (
+	"bytes"
+	"net/netip"
+	"strings"
+
+	"tinygo.org/x/drivers/netdev"
+)
+
+const (
+	// maxLinks is the number of connections (link IDs) of the ESP8266/ESP32
+	// in multiple connection mode.
+	maxLinks = 5
+
+	// maxSockets is the number of sockets, one more than links for the
+	// listening socket.
+	maxSockets = maxLinks + 1
+
+	// maxPackets is the number of received UDP datagrams queued per socket.
+	maxPackets = 4
+)
+
+type packet struct {
+	from netip.AddrPort
+	data []byte
+}
+
+type socket struct {
+	protocol int
+	laddr    netip.AddrPort // Set in Bind()
+	raddr    netip.AddrPort // Set in Connect() and Accept()
+	link     int            // Link ID, or -1 when not connected
+	closed   bool           // Link closed by the remote end
+	data     []byte         // Received TCP data
+	packets  []packet       // Received UDP datagrams
+	backlog  []*socket      // Connections to a listening socket
+}
+
+// demux splits the bytes received from the ESP8266/ESP32 into command
+// responses, socket data and link events.
+type demux struct {
+	// command responses that come back from the ESP8266/ESP32
+	response []byte
+	// bytes received from the ESP8266/ESP32 that aren't processed yet
+	rx []byte
+	// data received from a TCP/UDP connection forwarded by the ESP8266/ESP32,
+	// in single connection mode
+	data   []byte
+	mux    bool              // Multiple connection mode, data has a link ID
+	links  [maxLinks]*socket // Sockets by link ID
+	server *socket           // The listening TCP socket
+}
+
+var ipd = []byte("+IPD,")
+
+// process takes socket data and link events out of the received bytes, and
+// moves the other complete lines to the command response.
+func (d *demux) process() {
+	rx := d.rx
+	for len(rx) > 0 {
+		if bytes.HasPrefix(rx, ipd) || bytes.HasPrefix(ipd, rx) {
+			n := d.parseIPD(rx)
+			if n == 0 {
+				// wait for the rest
+				break
+			}
+			rx = rx[n:]
+			continue
+		}
+
+		// the prompt to send data doesn't end with a newline
+		if rx[0] == '>' {
+			d.response = append(d.response, '>')
+			rx = bytes.TrimLeft(rx[1:], " ")
+			continue
+		}
+
+		i := bytes.IndexByte(rx, '\n')
+		if i == -1 {
+			break
+		}
+		line := rx[:i+1]
+		if !d.linkEvent(bytes.TrimSpace(line)) {
+			d.response = append(d.response, line...)
+		}
+		rx = rx[i+1:]
+	}
+	d.rx = append(d.rx[:0], rx...)
+}
+
+// Maximum length of the header of socket data. Longer headers are garbage.
+const maxIPDHeader = 64
+
+// parseIPD parses socket data, in one of the forms:
+//
+//	+IPD,<len>[,<remote IP>,<remote port>]:<data>
+//	+IPD,<link ID>,<len>[,<remote IP>,<remote port>]:<data>
+//
+// It returns the number of bytes used, or 0 if the data isn't complete.
+func (d *demux) parseIPD(rx []byte) int {
+	// the header is on the first line
+	header := rx
+	if i := bytes.IndexByte(rx, '\n'); i != -1 {
+		header = rx[:i]
+	}
+	link, v, from, e := parseIPDHeader(header, d.mux)
+	if e <= 0 {
+		if len(header) == len(rx) && len(rx) <= maxIPDHeader {
+			// wait for the rest of the line
+			return 0
+		}
+		// not expected data here, so skip the line
+		return skipLine(rx)
+	}
+	if len(rx) < e+v {
+		return 0
+	}
+	data := rx[e : e+v]
+
+	// load up the socket data
+	switch {
+	case link == -1:
+		d.data = append(d.data, data...)
+	case d.links[link] == nil:
+		// data for a link that was closed already
+	case d.links[link].protocol == netdev.IPPROTO_UDP:
+		s := d.links[link]
+		if !from.IsValid() {
+			from = s.raddr
+		}
+		if len(s.packets) < maxPackets {
+			s.packets = append(s.packets, packet{from: from, data: append([]byte(nil), data...)})
+		}
+	default:
+		s := d.links[link]
+		s.data = append(s.data, data...)
+	}
+
+	return e + v
+}
+
+// parseIPDHeader parses the header of socket data up to the ":" before the
+// data, and returns its length. The link ID is only there in multiple
+// connection mode, and is -1 otherwise. The length is 0 when the header isn't
+// complete, and -1 when it isn't valid.
+//
+// The remote IP may be an IPv6 address, which contains colons but no commas,
+// so it ends at the comma before the port.
+func parseIPDHeader(rx []byte, mux bool) (link, length int, from netip.AddrPort, n int) {
+	link = -1
+	if len(rx) <= len(ipd) {
+		return link, 0, from, 0
+	}
+	p := len(ipd)
+	if mux {
+		v, k := ipdNumber(rx[p:])
+		switch {
+		case p+k == len(rx):
+			return link, 0, from, 0
+		case k == 0 || rx[p+k] != ',' || v >= maxLinks:
+			return link, 0, from, -1
+		}
+		link = v
+		p += k + 1
+	}
+
+	length, k := ipdNumber(rx[p:])
+	switch {
+	case p+k == len(rx):
+		return link, 0, from, 0
+	case k == 0:
+		return link, 0, from, -1
+	}
+	p += k
+
+	if rx[p] == ',' {
+		p++
+		c := bytes.IndexByte(rx[p:], ',')
+		if c == -1 {
+			return link, 0, from, 0
+		}
+		ip, err := netip.ParseAddr(strings.Trim(string(rx[p:p+c]), `"`))
+		p += c + 1
+		port, k := ipdNumber(rx[p:])
+		switch {
+		case p+k == len(rx):
+			return link, 0, from, 0
+		case k == 0:
+			return link, 0, from, -1
+		}
+		p += k
+		if err == nil {
+			from = netip.AddrPortFrom(ip, uint16(port))
+		}
+	}
+
+	if rx[p] != ':' {
+		return link, 0, from, -1
+	}
+	return link, length, from, p + 1
+}
+
+// ipdNumber returns the decimal number at the start of b, and the number of
+// digits. It reads at most 5 digits, which is plenty for lengths and ports.
+func ipdNumber(b []byte) (int, int) {
+	v, n := 0, 0
+	for n < len(b) && n < 5 && b[n] >= '0' && b[n] <= '9' {
+		v = v*10 + int(b[n]-'0')
+		n++
+	}
+	return v, n
+}
+
+// skipLine returns the length of the first line of rx, or of rx if there is
+// no newline.
+func skipLine(rx []byte) int {
+	if i := bytes.IndexByte(rx, '\n'); i != -1 {
+		return i + 1
+	}
+	return len(rx)
+}
+
+// linkEvent handles a "<link ID>,CONNECT" or "<link ID>,CLOSED" line, and
+// returns whether the line was a link event.
+func (d *demux) linkEvent(line []byte) bool {
+	id, event, ok := bytes.Cut(line, []byte(","))
+	if !ok || len(id) != 1 || id[0] < '0' || id[0] >= '0'+maxLinks {
+		return false
+	}
+	link := int(id[0] - '0')
+
+	switch string(event) {
+	case "CONNECT":
+		// connections to the server get a link not taken by a socket
+		if d.links[link] == nil && d.server != nil {
+			client := &socket{
+				protocol: netdev.IPPROTO_TCP,
+				laddr:    d.server.laddr,
+				link:     link,
+			}
+			d.links[link] = client
+			d.server.backlog = append(d.server.backlog, client)
+		}
+	case "CLOSED", "CONNECT FAIL":
+		if s := d.links[link]; s != nil {
+			s.closed = true
+			d.links[link] = nil
+		}
+	default:
+		return false
+	}
+
+	return true
+}
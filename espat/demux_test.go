@@ -0,0 +1,126 @@
+package espat
+
+import (
+	"net/netip"
+	"testing"
+
+	"tinygo.org/x/drivers/netdev"
+)
+
+// feed processes received bytes, one byte at a time to check that partial
+// data is kept for later.
+func feed(d *demux, s string) {
+	for i := 0; i < len(s); i++ {
+		d.rx = append(d.rx, s[i])
+		d.process()
+	}
+}
+
+func TestResponse(t *testing.T) {
+	var d demux
+	feed(&d, "AT+CIPSEND=0,4\r\n\r\nOK\r\n> ")
+	if got, want := string(d.response), "AT+CIPSEND=0,4\r\n\r\nOK\r\n>"; got != want {
+		t.Errorf("got response %q, want %q", got, want)
+	}
+}
+
+func TestSingleConnection(t *testing.T) {
+	var d demux
+	feed(&d, "+IPD,5:hello\r\nOK\r\n+IPD,3:a\nb")
+	if got := string(d.data); got != "helloa\nb" {
+		t.Errorf("got data %q, want %q", got, "helloa\nb")
+	}
+	if got := string(d.response); got != "\r\nOK\r\n" {
+		t.Errorf("got response %q, want %q", got, "\r\nOK\r\n")
+	}
+
+	// Unexpected headers are skipped.
+	feed(&d, "+IPD,x:\r\n+IPD,-1:\r\n+IPD,1,2;\r\n")
+	if string(d.data) != "helloa\nb" || len(d.rx) != 0 {
+		t.Errorf("got data %q and %q left over after bad headers", d.data, d.rx)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	d := demux{mux: true}
+	d.server = &socket{protocol: netdev.IPPROTO_TCP, laddr: netip.MustParseAddrPort("0.0.0.0:80"), link: -1}
+	client := &socket{protocol: netdev.IPPROTO_TCP, link: 4}
+	d.links[4] = client
+
+	// Connections to the server are queued, the others belong to a socket.
+	feed(&d, "0,CONNECT\r\n4,CONNECT\r\n")
+	if len(d.server.backlog) != 1 || d.links[0] != d.server.backlog[0] {
+		t.Fatalf("got backlog %v, want link 0", d.server.backlog)
+	}
+	accepted := d.links[0]
+	if accepted.link != 0 || accepted.laddr.Port() != 80 {
+		t.Errorf("got link %d on %v, want link 0 on port 80", accepted.link, accepted.laddr)
+	}
+	if d.links[4] != client {
+		t.Error("connect event took over the link of a socket")
+	}
+
+	feed(&d, "+IPD,0,4,192.168.1.2,5000:ping+IPD,4,4:pong")
+	if string(accepted.data) != "ping" || string(client.data) != "pong" {
+		t.Errorf("got data %q and %q, want ping and pong", accepted.data, client.data)
+	}
+
+	feed(&d, "0,CLOSED\r\n4,CONNECT FAIL\r\n+IPD,0,3:abc")
+	if !accepted.closed || !client.closed || d.links[0] != nil || d.links[4] != nil {
+		t.Error("links not closed")
+	}
+	if len(d.response) != 0 || len(d.rx) != 0 {
+		t.Errorf("got response %q and %q left over, want nothing", d.response, d.rx)
+	}
+
+	// Data for a link that doesn't exist.
+	feed(&d, "+IPD,5,3:abc\r\n")
+	if got := string(d.response); got != "" {
+		t.Errorf("got response %q, want nothing", got)
+	}
+
+	// Not a link event.
+	feed(&d, "5,CONNECT\r\n")
+	if got := string(d.response); got != "5,CONNECT\r\n" {
+		t.Errorf("got response %q, want the line", got)
+	}
+}
+
+func TestDatagrams(t *testing.T) {
+	d := demux{mux: true}
+	raddr := netip.MustParseAddrPort("10.0.0.1:123")
+	s := &socket{protocol: netdev.IPPROTO_UDP, link: 2, raddr: raddr}
+	d.links[2] = s
+
+	feed(&d, "+IPD,2,2,10.0.0.9,4000:hi+IPD,2,3:yo!")
+	if len(s.packets) != 2 {
+		t.Fatalf("got %d datagrams, want 2", len(s.packets))
+	}
+	if p := s.packets[0]; string(p.data) != "hi" || p.from != netip.MustParseAddrPort("10.0.0.9:4000") {
+		t.Errorf("got %q from %v, want hi from 10.0.0.9:4000", p.data, p.from)
+	}
+	if p := s.packets[1]; string(p.data) != "yo!" || p.from != raddr {
+		t.Errorf("got %q from %v, want yo! from %v", p.data, p.from, raddr)
+	}
+
+	// The remote IP may be an IPv6 address, with or without quotes.
+	s.packets = nil
+	feed(&d, "+IPD,2,3,fe80::1,80:one+IPD,2,3,\"2001:db8::2\",443:two")
+	if len(s.packets) != 2 {
+		t.Fatalf("got %d datagrams, want 2", len(s.packets))
+	}
+	if p := s.packets[0]; string(p.data) != "one" || p.from != netip.MustParseAddrPort("[fe80::1]:80") {
+		t.Errorf("got %q from %v, want one from [fe80::1]:80", p.data, p.from)
+	}
+	if p := s.packets[1]; string(p.data) != "two" || p.from != netip.MustParseAddrPort("[2001:db8::2]:443") {
+		t.Errorf("got %q from %v, want two from [2001:db8::2]:443", p.data, p.from)
+	}
+
+	// Datagrams are dropped when the queue is full.
+	for i := 0; i < maxPackets; i++ {
+		feed(&d, "+IPD,2,1:x")
+	}
+	if len(s.packets) != maxPackets {
+		t.Errorf("got %d datagrams, want %d", len(s.packets), maxPackets)
+	}
+}
@@ -1,3 +1,5 @@
+//go:build tinygo
+
 // Package espat implements TCP/UDP wireless communication over serial
 // with a separate ESP8266 or ESP32 board using the Espressif AT command set
 // across a UART interface.
@@ -21,8 +23,10 @@
 package espat // import "tinygo.org/x/drivers/espat"
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"machine"
 	"net"
 	"net/netip"
@@ -42,28 +46,34 @@ type Config struct {
 	Rx   machine.Pin
 }
 
-type socket struct {
-	inUse    bool
-	protocol int
-	laddr    netip.AddrPort
-}
+var (
+	errServerInUse = errors.New("Only one listening socket supported")
+	errNotBound    = errors.New("Must Bind before Listening")
+
+	// errMultipleConnections is returned by the single connection methods
+	// once sockets have switched the ESP8266/ESP32 to multiple connections.
+	errMultipleConnections = errors.New("Not supported in multiple connection mode")
+)
 
 type Device struct {
-	cfg  *Config
-	uart *machine.UART
-	// command responses that come back from the ESP8266/ESP32
-	response []byte
-	// data received from a TCP/UDP connection forwarded by the ESP8266/ESP32
-	data   []byte
-	socket socket
-	mu     sync.Mutex
+	cfg   *Config
+	uart  *machine.UART
+	chunk [64]byte
+	demux
+	sockets [maxSockets]*socket
+	// generation of each sockfd, which changes when the socket is closed
+	gens [maxSockets]uint32
+	mu   sync.Mutex
 }
 
 func NewDevice(cfg *Config) *Device {
 	return &Device{
-		cfg:      cfg,
-		response: make([]byte, 1500),
-		data:     make([]byte, 0, 1500),
+		cfg: cfg,
+		demux: demux{
+			response: make([]byte, 0, 1500),
+			rx:       make([]byte, 0, 1500),
+			data:     make([]byte, 0, 1500),
+		},
 	}
 }
 
@@ -126,6 +136,9 @@ func (d *Device) NetNotify(cb func(netlink.Event)) {
 }
 
 func (d *Device) GetHostByName(name string) (netip.Addr, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	ip, err := d.GetDNS(name)
 	if err != nil {
 		return netip.Addr{}, err
@@ -138,6 +151,9 @@ func (d *Device) GetHardwareAddr() (net.HardwareAddr, error) {
 }
 
 func (d *Device) Addr() (netip.Addr, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	resp, err := d.GetClientIP()
 	if err != nil {
 		return netip.Addr{}, err
@@ -152,6 +168,35 @@ func (d *Device) Addr() (netip.Addr, error) {
 	return netip.Addr{}, fmt.Errorf("Error getting IP address")
 }
 
+// newSockfd returns the next available sockfd, or -1 if none available
+func (d *Device) newSockfd() int {
+	for sockfd, s := range d.sockets {
+		if s == nil {
+			return sockfd
+		}
+	}
+	return -1
+}
+
+func (d *Device) getSocket(sockfd int) (*socket, error) {
+	if sockfd < 0 || sockfd >= len(d.sockets) || d.sockets[sockfd] == nil {
+		return nil, netdev.ErrInvalidSocketFd
+	}
+	return d.sockets[sockfd], nil
+}
+
+// freeLink returns an unused link ID, or -1 if none available.  Incoming
+// connections get the lowest free link ID, so search from the top to stay
+// clear of them.
+func (d *Device) freeLink() int {
+	for link := maxLinks - 1; link >= 0; link-- {
+		if d.links[link] == nil {
+			return link
+		}
+	}
+	return -1
+}
+
 func (d *Device) Socket(domain int, stype int, protocol int) (int, error) {
 
 	switch domain {
@@ -168,68 +213,218 @@ func (d *Device) Socket(domain int, stype int, protocol int) (int, error) {
 		return -1, netdev.ErrProtocolNotSupported
 	}
 
-	// Only supporting single connection mode, so only one socket at a time
-	if d.socket.inUse {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Sockets use multiple connections, with the remote address of
+	// received data.  The single connection methods stop working from here.
+	if !d.mux {
+		if err := d.SetMux(TCPMuxMultiple); err != nil {
+			return -1, err
+		}
+		if err := d.SetRemoteInfo(true); err != nil {
+			return -1, err
+		}
+	}
+
+	sockfd := d.newSockfd()
+	if sockfd == -1 {
 		return -1, netdev.ErrNoMoreSockets
 	}
-	d.socket.inUse = true
-	d.socket.protocol = protocol
 
-	return 0, nil
+	d.sockets[sockfd] = &socket{
+		protocol: protocol,
+		link:     -1,
+	}
+
+	return sockfd, nil
 }
 
+// Bind sets the local address of a socket.  UDP sockets start receiving
+// datagrams on the local port right away, from any remote address.
 func (d *Device) Bind(sockfd int, ip netip.AddrPort) error {
-	d.socket.laddr = ip
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return err
+	}
+
+	s.laddr = ip
+
+	if s.protocol == netdev.IPPROTO_UDP && s.link == -1 {
+		return d.openLink(s, "", netip.AddrPortFrom(netip.IPv4Unspecified(), 0))
+	}
+
 	return nil
 }
 
-func (d *Device) Connect(sockfd int, host string, ip netip.AddrPort) error {
+// openLink starts a connection for s on a free link.
+func (d *Device) openLink(s *socket, host string, ip netip.AddrPort) error {
+	link := d.freeLink()
+	if link == -1 {
+		return netdev.ErrNoMoreSockets
+	}
+
 	var err error
 	var addr = ip.Addr().String()
 	var rport = strconv.Itoa(int(ip.Port()))
-	var lport = strconv.Itoa(int(d.socket.laddr.Port()))
+	var lport = strconv.Itoa(int(s.laddr.Port()))
 
-	switch d.socket.protocol {
+	// Claim the link first, so its CONNECT isn't taken for an incoming
+	// connection
+	d.links[link] = s
+
+	switch s.protocol {
 	case netdev.IPPROTO_TCP:
-		err = d.ConnectTCPSocket(addr, rport)
+		err = d.connectLink(link, "TCP", addr, rport, "")
 	case netdev.IPPROTO_UDP:
-		err = d.ConnectUDPSocket(addr, rport, lport)
+		err = d.connectLink(link, "UDP", addr, rport, lport)
 	case netdev.IPPROTO_TLS:
-		err = d.ConnectSSLSocket(host, rport)
+		err = d.connectLink(link, "SSL", host, rport, "")
 	}
 
 	if err != nil {
+		d.links[link] = nil
+		return err
+	}
+
+	s.link = link
+	s.closed = false
+	return nil
+}
+
+func (d *Device) Connect(sockfd int, host string, ip netip.AddrPort) error {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return err
+	}
+
+	s.raddr = ip
+
+	// Bound UDP sockets send to the remote address with each datagram
+	if s.protocol == netdev.IPPROTO_UDP && s.link != -1 {
+		return nil
+	}
+
+	if err := d.openLink(s, host, ip); err != nil {
+		if err == netdev.ErrNoMoreSockets {
+			return err
+		}
 		if host == "" {
-			return fmt.Errorf("Connect to %s timed out", ip)
+			return fmt.Errorf("Connect to %s failed", ip)
 		} else {
-			return fmt.Errorf("Connect to %s:%d timed out", host, ip.Port())
+			return fmt.Errorf("Connect to %s:%d failed", host, ip.Port())
 		}
 	}
 
 	return nil
 }
 
+// Listen starts a TCP server on the port of the local address.  The
+// ESP8266/ESP32 supports a single TCP server.  UDP sockets listen from Bind.
 func (d *Device) Listen(sockfd int, backlog int) error {
-	switch d.socket.protocol {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return err
+	}
+
+	switch s.protocol {
+	case netdev.IPPROTO_TCP:
 	case netdev.IPPROTO_UDP:
+		return nil
 	default:
 		return netdev.ErrProtocolNotSupported
 	}
+
+	if d.server != nil {
+		return errServerInUse
+	}
+	if s.laddr.Port() == 0 {
+		return errNotBound
+	}
+
+	if err := d.StartServer(int(s.laddr.Port())); err != nil {
+		return err
+	}
+
+	d.server = s
 	return nil
 }
 
 func (d *Device) Accept(sockfd int) (int, netip.AddrPort, error) {
-	return -1, netip.AddrPort{}, netdev.ErrNotSupported
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return -1, netip.AddrPort{}, err
+	}
+	gen := d.gens[sockfd]
+
+	if s != d.server {
+		return -1, netip.AddrPort{}, netdev.ErrProtocolNotSupported
+	}
+
+	for {
+		d.receive()
+
+		if len(s.backlog) > 0 {
+			break
+		}
+
+		// Accept() will be sleeping most of the time, checking for
+		// new clients every 1/10 sec.
+		d.mu.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		d.mu.Lock()
+
+		// Check if the socket was closed while sleeping
+		if d.gens[sockfd] != gen {
+			return -1, netip.AddrPort{}, netdev.ErrInvalidSocketFd
+		}
+	}
+
+	client := s.backlog[0]
+
+	clientfd := d.newSockfd()
+	if clientfd == -1 {
+		return -1, netip.AddrPort{}, netdev.ErrNoMoreSockets
+	}
+
+	s.backlog = s.backlog[1:]
+	d.sockets[clientfd] = client
+
+	if !client.closed {
+		// The remote address is only for information, so ignore errors
+		client.raddr, _ = d.linkAddr(client.link)
+	}
+
+	return clientfd, client.raddr, nil
 }
 
-func (d *Device) sendChunk(sockfd int, buf []byte, deadline time.Time) (int, error) {
+func (d *Device) sendChunk(s *socket, buf []byte, raddr netip.AddrPort, deadline time.Time) (int, error) {
 	// Check if we've timed out
 	if !deadline.IsZero() {
 		if time.Now().After(deadline) {
 			return -1, netdev.ErrTimeout
 		}
 	}
-	err := d.StartSocketSend(len(buf))
+	if s.closed {
+		return -1, io.EOF
+	}
+	err := d.startLinkSend(s.link, len(buf), raddr)
 	if err != nil {
 		return -1, err
 	}
@@ -249,6 +444,21 @@ func (d *Device) Send(sockfd int, buf []byte, flags int, deadline time.Time) (in
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return -1, err
+	}
+
+	if s.link == -1 {
+		return -1, net.ErrClosed
+	}
+
+	// A datagram is sent in one piece, to the connected address, or else
+	// to the last sender
+	if s.protocol == netdev.IPPROTO_UDP {
+		return d.sendChunk(s, buf, s.raddr, deadline)
+	}
+
 	// Break large bufs into chunks so we don't overrun the hw queue
 
 	chunkSize := 1436
@@ -257,7 +467,7 @@ func (d *Device) Send(sockfd int, buf []byte, flags int, deadline time.Time) (in
 		if end > len(buf) {
 			end = len(buf)
 		}
-		_, err := d.sendChunk(sockfd, buf[i:end], deadline)
+		_, err := d.sendChunk(s, buf[i:end], netip.AddrPort{}, deadline)
 		if err != nil {
 			return -1, err
 		}
@@ -266,38 +476,87 @@ func (d *Device) Send(sockfd int, buf []byte, flags int, deadline time.Time) (in
 	return len(buf), nil
 }
 
-func (d *Device) Recv(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+// SendTo sends a datagram to addr on a bound UDP socket.
+func (d *Device) SendTo(sockfd int, buf []byte, addr netip.AddrPort, deadline time.Time) (int, error) {
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	var length = len(buf)
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return -1, err
+	}
 
-	// Limit length read size to chunk large read requests
-	if length > 1436 {
-		length = 1436
+	if s.protocol != netdev.IPPROTO_UDP {
+		return -1, netdev.ErrProtocolNotSupported
 	}
+	if s.link == -1 {
+		return -1, net.ErrClosed
+	}
+
+	return d.sendChunk(s, buf, addr, deadline)
+}
+
+func (d *Device) Recv(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	n, _, err := d.RecvFrom(sockfd, buf, deadline)
+	return n, err
+}
+
+// RecvFrom receives data from a socket, and returns the remote address it
+// came from.  For UDP sockets, it receives one datagram, and the part that
+// doesn't fit in buf is dropped.  RecvFrom waits for data until the deadline,
+// or forever when the deadline is zero.
+func (d *Device) RecvFrom(sockfd int, buf []byte, deadline time.Time) (int, netip.AddrPort, error) {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return -1, netip.AddrPort{}, err
+	}
+	gen := d.gens[sockfd]
 
 	for {
+		d.receive()
+
+		if len(s.packets) > 0 {
+			p := s.packets[0]
+			s.packets = s.packets[1:]
+			return copy(buf, p.data), p.from, nil
+		}
+
+		if len(s.data) > 0 {
+			n := copy(buf, s.data)
+			s.data = s.data[n:]
+			return n, s.raddr, nil
+		}
+
+		if s.closed {
+			return -1, s.raddr, io.EOF
+		}
+
+		if s.link == -1 {
+			return -1, netip.AddrPort{}, net.ErrClosed
+		}
+
 		// Check if we've timed out
 		if !deadline.IsZero() {
 			if time.Now().After(deadline) {
-				return -1, netdev.ErrTimeout
+				return -1, netip.AddrPort{}, netdev.ErrTimeout
 			}
 		}
 
-		n, err := d.ReadSocket(buf[:length])
-		if err != nil {
-			return -1, err
-		}
-		if n == 0 {
-			d.mu.Unlock()
-			time.Sleep(100 * time.Millisecond)
-			d.mu.Lock()
-			continue
-		}
+		// Unlock while we sleep, so others can make progress
+		d.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		d.mu.Lock()
 
-		return n, nil
+		// Check if the socket was closed (and the sockfd maybe reused)
+		// while sleeping
+		if d.gens[sockfd] != gen {
+			return -1, netip.AddrPort{}, net.ErrClosed
+		}
 	}
 }
 
@@ -305,8 +564,38 @@ func (d *Device) Close(sockfd int) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.socket.inUse = false
-	return d.DisconnectSocket()
+	s, err := d.getSocket(sockfd)
+	if err != nil {
+		return err
+	}
+
+	d.sockets[sockfd] = nil
+	d.gens[sockfd]++
+
+	if s == d.server {
+		d.server = nil
+		// Close connections that weren't accepted
+		for _, client := range s.backlog {
+			d.closeSocketLink(client)
+		}
+		return d.StopServer()
+	}
+
+	return d.closeSocketLink(s)
+}
+
+// closeSocketLink closes the link of s, if it's still connected.
+func (d *Device) closeSocketLink(s *socket) error {
+	if s.link == -1 {
+		return nil
+	}
+	link := s.link
+	s.link = -1
+	if s.closed {
+		return nil
+	}
+	d.links[link] = nil
+	return d.closeLink(link)
 }
 
 func (d *Device) SetSockOpt(sockfd int, level int, opt int, value interface{}) error {
@@ -339,27 +628,27 @@ func (d *Device) Read(b []byte) (n int, err error) {
 const pause = 300
 
 // Execute sends an AT command to the ESP8266/ESP32.
-func (d Device) Execute(cmd string) error {
+func (d *Device) Execute(cmd string) error {
 	_, err := d.Write([]byte("AT" + cmd + "\r\n"))
 	return err
 }
 
 // Query sends an AT command to the ESP8266/ESP32 that returns the
 // current value for some configuration parameter.
-func (d Device) Query(cmd string) (string, error) {
+func (d *Device) Query(cmd string) (string, error) {
 	_, err := d.Write([]byte("AT" + cmd + "?\r\n"))
 	return "", err
 }
 
 // Set sends an AT command with params to the ESP8266/ESP32 for a
 // configuration value to be set.
-func (d Device) Set(cmd, params string) error {
+func (d *Device) Set(cmd, params string) error {
 	_, err := d.Write([]byte("AT" + cmd + "=" + params + "\r\n"))
 	return err
 }
 
 // Version returns the ESP8266/ESP32 firmware version info.
-func (d Device) Version() []byte {
+func (d *Device) Version() []byte {
 	d.Execute(Version)
 	r, err := d.Response(2000)
 	if err != nil {
@@ -370,7 +659,7 @@ func (d Device) Version() []byte {
 }
 
 // Echo sets the ESP8266/ESP32 echo setting.
-func (d Device) Echo(set bool) {
+func (d *Device) Echo(set bool) {
 	if set {
 		d.Execute(EchoConfigOn)
 	} else {
@@ -383,13 +672,18 @@ func (d Device) Echo(set bool) {
 // Reset restarts the ESP8266/ESP32 firmware. Due to how the baud rate changes,
 // this messes up communication with the ESP8266/ESP32 module. So make sure you know
 // what you are doing when you call this.
-func (d Device) Reset() {
+func (d *Device) Reset() {
 	d.Execute(Restart)
 	d.Response(100)
 }
 
-// ReadSocket returns the data that has already been read in from the responses.
+// ReadSocket returns the data that has already been read in from the responses,
+// in single connection mode.
 func (d *Device) ReadSocket(b []byte) (n int, err error) {
+	if d.mux {
+		return 0, errMultipleConnections
+	}
+
 	// make sure no data in buffer
 	d.Response(300)
 
@@ -412,69 +706,53 @@ func (d *Device) ReadSocket(b []byte) (n int, err error) {
 // Response gets the next response bytes from the ESP8266/ESP32.
 // The call will retry for up to timeout milliseconds before returning nothing.
 func (d *Device) Response(timeout int) ([]byte, error) {
-	// read data
-	var size int
-	var start, end int
-	pause := 100 // pause to wait for 100 ms
-	retries := timeout / pause
-
-	for {
-		size = d.uart.Buffered()
+	return d.waitResponse(timeout, false)
+}
 
-		if size > 0 {
-			end += size
-			d.uart.Read(d.response[start:end])
+// waitResponse waits for up to timeout milliseconds for the end of a command
+// response, or, with prompt, for the ">" prompt to send data.
+func (d *Device) waitResponse(timeout int, prompt bool) ([]byte, error) {
+	d.response = d.response[:0]
+	start := time.Now()
 
-			// if "+IPD" then read socket data
-			if strings.Contains(string(d.response[:end]), "+IPD") {
-				// handle socket data
-				return nil, d.parseIPD(end)
-			}
+	for {
+		d.receive()
 
-			// if "OK" then the command worked
-			if strings.Contains(string(d.response[:end]), "OK") {
-				return d.response[start:end], nil
-			}
+		// if ">" then the device is ready for the data to send
+		if prompt && bytes.Contains(d.response, []byte(">")) {
+			return d.response, nil
+		}
 
-			// if "Error" then the command failed
-			if strings.Contains(string(d.response[:end]), "ERROR") {
-				return d.response[start:end], errors.New("response error:" + string(d.response[start:end]))
-			}
+		// if "OK" then the command worked
+		if !prompt && bytes.Contains(d.response, []byte("OK")) {
+			return d.response, nil
+		}
 
-			// if anything else, then keep reading data in?
-			start = end
+		// if "Error" then the command failed
+		if bytes.Contains(d.response, []byte("ERROR")) || bytes.Contains(d.response, []byte("FAIL")) {
+			return d.response, errors.New("response error:" + string(d.response))
 		}
 
 		// wait longer?
-		retries--
-		if retries == 0 {
-			return nil, errors.New("response timeout error:" + string(d.response[start:end]))
+		if time.Since(start) >= time.Duration(timeout)*time.Millisecond {
+			return nil, errors.New("response timeout error:" + string(d.response))
 		}
 
-		time.Sleep(time.Duration(pause) * time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
 	}
 }
 
-func (d *Device) parseIPD(end int) error {
-	// find the "+IPD," to get length
-	s := strings.Index(string(d.response[:end]), "+IPD,")
-
-	// find the ":"
-	e := strings.Index(string(d.response[:end]), ":")
-
-	// find the data length
-	val := string(d.response[s+5 : e])
-
-	// TODO: verify count
-	v, err := strconv.Atoi(val)
-	if err != nil {
-		// not expected data here. what to do?
-		return err
+// receive reads what the ESP8266/ESP32 sent so far, without waiting, and
+// processes it.
+func (d *Device) receive() {
+	for d.uart.Buffered() > 0 {
+		n, _ := d.uart.Read(d.chunk[:])
+		if n == 0 {
+			break
+		}
+		d.rx = append(d.rx, d.chunk[:n]...)
 	}
-
-	// load up the socket data
-	d.data = append(d.data, d.response[e+1:e+1+v]...)
-	return nil
+	d.process()
 }
 
 // IsSocketDataAvailable returns of there is socket data available
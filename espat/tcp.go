@@ -1,7 +1,10 @@
+//go:build tinygo
+
 package espat
 
 import (
 	"errors"
+	"net/netip"
 	"strconv"
 	"strings"
 )
@@ -33,8 +36,13 @@ func (d *Device) GetDNS(domain string) (string, error) {
 }
 
 // ConnectTCPSocket creates a new TCP socket connection for the ESP8266/ESP32.
-// Currently only supports single connection mode.
+// Only for single connection mode (TCPMuxSingle), see Device.Connect for
+// multiple connections. It fails once Device.Socket has switched to multiple
+// connection mode.
 func (d *Device) ConnectTCPSocket(addr, port string) error {
+	if d.mux {
+		return errMultipleConnections
+	}
 	protocol := "TCP"
 	val := "\"" + protocol + "\",\"" + addr + "\"," + port + ",120"
 	err := d.Set(TCPConnect, val)
@@ -49,7 +57,11 @@ func (d *Device) ConnectTCPSocket(addr, port string) error {
 }
 
 // ConnectUDPSocket creates a new UDP connection for the ESP8266/ESP32.
+// Only for single connection mode (TCPMuxSingle).
 func (d *Device) ConnectUDPSocket(addr, sendport, listenport string) error {
+	if d.mux {
+		return errMultipleConnections
+	}
 	protocol := "UDP"
 	val := "\"" + protocol + "\",\"" + addr + "\"," + sendport + "," + listenport + ",0"
 	err := d.Set(TCPConnect, val)
@@ -64,8 +76,11 @@ func (d *Device) ConnectUDPSocket(addr, sendport, listenport string) error {
 }
 
 // ConnectSSLSocket creates a new SSL socket connection for the ESP8266/ESP32.
-// Currently only supports single connection mode.
+// Only for single connection mode (TCPMuxSingle).
 func (d *Device) ConnectSSLSocket(addr, port string) error {
+	if d.mux {
+		return errMultipleConnections
+	}
 	protocol := "SSL"
 	val := "\"" + protocol + "\",\"" + addr + "\"," + port + ",120"
 	d.Set(TCPConnect, val)
@@ -77,8 +92,12 @@ func (d *Device) ConnectSSLSocket(addr, port string) error {
 	return nil
 }
 
-// DisconnectSocket disconnects the ESP8266/ESP32 from the current TCP/UDP connection.
+// DisconnectSocket disconnects the ESP8266/ESP32 from the current TCP/UDP connection,
+// in single connection mode.
 func (d *Device) DisconnectSocket() error {
+	if d.mux {
+		return errMultipleConnections
+	}
 	err := d.Execute(TCPClose)
 	if err != nil {
 		return err
@@ -90,13 +109,120 @@ func (d *Device) DisconnectSocket() error {
 	return nil
 }
 
+// connectLink creates a new connection on a link in multiple connection mode.
+// The protocol is "TCP", "UDP" or "SSL".  UDP connections have a local port,
+// and can send to other remote addresses than addr.
+func (d *Device) connectLink(link int, protocol, addr, port, lport string) error {
+	val := strconv.Itoa(link) + ",\"" + protocol + "\",\"" + addr + "\"," + port
+	timeout := 3000
+	switch protocol {
+	case "TCP":
+		val += ",120"
+	case "UDP":
+		val += "," + lport + ",2"
+	case "SSL":
+		val += ",120"
+		// this operation takes longer, so wait up to 6 seconds to complete.
+		timeout = 6000
+	}
+	err := d.Set(TCPConnect, val)
+	if err != nil {
+		return err
+	}
+	_, err = d.Response(timeout)
+	return err
+}
+
+// closeLink closes the connection on a link in multiple connection mode.
+func (d *Device) closeLink(link int) error {
+	err := d.Set(TCPClose, strconv.Itoa(link))
+	if err != nil {
+		return err
+	}
+	_, err = d.Response(1000)
+	return err
+}
+
+// linkAddr returns the remote address of a link in multiple connection mode.
+func (d *Device) linkAddr(link int) (netip.AddrPort, error) {
+	d.Execute(TCPStatus)
+	resp, err := d.Response(1000)
+	if err != nil {
+		return netip.AddrPort{}, err
+	}
+	// +CIPSTATUS:<link ID>,<"type">,<"remote IP">,<remote port>,<local port>,<tetype>
+	prefix := "+CIPSTATUS:" + strconv.Itoa(link) + ","
+	for _, line := range strings.Split(string(resp), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		r := strings.Split(line[len(prefix):], ",")
+		if len(r) < 3 {
+			break
+		}
+		ip, err := netip.ParseAddr(strings.Trim(r[1], `"`))
+		if err != nil {
+			return netip.AddrPort{}, err
+		}
+		port, err := strconv.Atoi(r[2])
+		if err != nil {
+			return netip.AddrPort{}, err
+		}
+		return netip.AddrPortFrom(ip, uint16(port)), nil
+	}
+	return netip.AddrPort{}, errors.New("Link status not found")
+}
+
+// StartServer starts a TCP server on port, in multiple connection mode.
+func (d *Device) StartServer(port int) error {
+	err := d.Set(ServerConfig, "1,"+strconv.Itoa(port))
+	if err != nil {
+		return err
+	}
+	_, err = d.Response(1000)
+	return err
+}
+
+// StopServer stops the TCP server.
+func (d *Device) StopServer() error {
+	err := d.Set(ServerConfig, "0")
+	if err != nil {
+		return err
+	}
+	_, err = d.Response(1000)
+	return err
+}
+
+// SetRemoteInfo sets whether the ESP8266/ESP32 sends the remote IP and port
+// with received data.
+func (d *Device) SetRemoteInfo(on bool) error {
+	val := "0"
+	if on {
+		val = "1"
+	}
+	err := d.Set(ShowRemoteInfo, val)
+	if err != nil {
+		return err
+	}
+	_, err = d.Response(pause)
+	return err
+}
+
 // SetMux sets the ESP8266/ESP32 current client TCP/UDP configuration for concurrent connections
 // either single TCPMuxSingle or multiple TCPMuxMultiple (up to 4).
 func (d *Device) SetMux(mode int) error {
 	val := strconv.Itoa(mode)
-	d.Set(TCPMultiple, val)
-	_, err := d.Response(pause)
-	return err
+	err := d.Set(TCPMultiple, val)
+	if err != nil {
+		return err
+	}
+	_, err = d.Response(pause)
+	if err != nil {
+		return err
+	}
+	d.mux = mode == TCPMuxMultiple
+	return nil
 }
 
 // GetMux returns the ESP8266/ESP32 current client TCP/UDP configuration for concurrent connections.
@@ -120,14 +246,36 @@ func (d *Device) GetTCPTransferMode() ([]byte, error) {
 	return d.Response(pause)
 }
 
-// StartSocketSend gets the ESP8266/ESP32 ready to receive TCP/UDP socket data.
+// StartSocketSend gets the ESP8266/ESP32 ready to receive TCP/UDP socket data,
+// in single connection mode.
 func (d *Device) StartSocketSend(size int) error {
+	if d.mux {
+		return errMultipleConnections
+	}
 	val := strconv.Itoa(size)
 	d.Set(TCPSend, val)
+	return d.waitPrompt()
+}
+
+// startLinkSend gets the ESP8266/ESP32 ready to receive TCP/UDP socket data
+// for a link, in multiple connection mode.  UDP data is sent to raddr, if
+// valid, or else to the remote address of the link.
+func (d *Device) startLinkSend(link int, size int, raddr netip.AddrPort) error {
+	val := strconv.Itoa(link) + "," + strconv.Itoa(size)
+	if raddr.IsValid() {
+		val += ",\"" + raddr.Addr().String() + "\"," + strconv.Itoa(int(raddr.Port()))
+	}
+	err := d.Set(TCPSend, val)
+	if err != nil {
+		return err
+	}
+	return d.waitPrompt()
+}
 
+func (d *Device) waitPrompt() error {
 	// when ">" is received, it indicates
 	// ready to receive data
-	r, err := d.Response(2000)
+	r, err := d.waitResponse(2000, true)
 	if err != nil {
 		return err
 	}
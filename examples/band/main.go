@@ -0,0 +1,40 @@
+package main
+
+// Scroll a color gradient over a st7789 display, drawing the next band while
+// the previous one is being sent.
+
+import (
+	"machine"
+
+	"tinygo.org/x/drivers/band"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/st7789"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 62500000,
+		Mode:      0,
+	})
+	display := st7789.New(machine.SPI0,
+		machine.GP12, // TFT_RESET
+		machine.GP8,  // TFT_DC
+		machine.GP9,  // TFT_CS
+		machine.GP13) // TFT_LITE
+	display.Configure(st7789.Config{
+		Rotation:  st7789.NO_ROTATION,
+		RowOffset: 80,
+	})
+
+	renderer := band.New[pixel.RGB565BE](&display, 16)
+	for offset := 0; ; offset++ {
+		renderer.Render(func(img pixel.Image[pixel.RGB565BE], x, y int) {
+			width, height := img.Size()
+			for by := 0; by < height; by++ {
+				for bx := 0; bx < width; bx++ {
+					img.Set(bx, by, pixel.NewRGB565BE(uint8(x+bx+offset), uint8(y+by), uint8(offset)))
+				}
+			}
+		})
+	}
+}
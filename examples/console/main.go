@@ -0,0 +1,74 @@
+package main
+
+// Show log output on a st7789 display, using hardware scrolling to add new
+// lines at the bottom of the screen.
+
+import (
+	"log"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/console"
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/st7789"
+	"tinygo.org/x/tinyfont"
+	"tinygo.org/x/tinyfont/freemono"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 62500000,
+		Mode:      0,
+	})
+	display := st7789.New(machine.SPI0,
+		machine.GP12, // TFT_RESET
+		machine.GP8,  // TFT_DC
+		machine.GP9,  // TFT_CS
+		machine.GP13) // TFT_LITE
+	display.Configure(st7789.Config{
+		Rotation: st7789.NO_ROTATION,
+		Width:    240,
+		Height:   320,
+	})
+
+	term := console.New[pixel.RGB565BE](&display, console.Config{
+		Font:        fromTinyfont(&freemono.Regular9pt7b),
+		HeaderLines: 1,
+		FooterLines: 1,
+	})
+	term.SetHeader(0, "TinyGo console")
+	log.SetOutput(term)
+	log.SetFlags(0)
+
+	start := time.Now()
+	for i := 0; ; i++ {
+		term.SetFooter(0, "uptime: "+time.Since(start).Truncate(time.Second).String())
+		switch {
+		case i%10 == 0:
+			log.Printf("\x1b[31merror\x1b[0m: message %d", i)
+		case i%3 == 0:
+			log.Printf("\x1b[33mwarning\x1b[0m: message %d", i)
+		default:
+			log.Printf("\x1b[32minfo\x1b[0m: message %d", i)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// fromTinyfont converts a 1-bit tinyfont font to a font.Font. The glyph layout
+// is the same, so the bitmaps can be reused as-is.
+func fromTinyfont(tf *tinyfont.Font) *font.Font {
+	f := &font.Font{
+		BitsPerPixel: 1,
+		Ascent:       -tf.BBox[3],
+		Descent:      -(tf.BBox[1] + tf.BBox[3]),
+		YAdvance:     tf.YAdvance,
+		Glyphs:       make([]font.Glyph, len(tf.Glyphs)),
+		Fallback:     '?',
+	}
+	for i, g := range tf.Glyphs {
+		f.Glyphs[i] = font.Glyph(g)
+	}
+	return f
+}
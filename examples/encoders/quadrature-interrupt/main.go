@@ -4,6 +4,7 @@ package main
 
 import (
 	"machine"
+	"time"
 
 	"tinygo.org/x/drivers/encoders"
 )
@@ -16,13 +17,16 @@ func main() {
 
 	enc.Configure(encoders.QuadratureConfig{
 		Precision: 4,
+		// Turning quickly moves the value further.
+		AccelerationSpeed: 10,
 	})
 
-	for oldValue := 0; ; {
-		if newValue := enc.Position(); newValue != oldValue {
-			println("value: ", newValue)
-			oldValue = newValue
+	for value := 0; ; {
+		if steps := enc.Steps(); steps != 0 {
+			value += steps
+			println("value: ", value, "velocity: ", int(enc.Velocity()), "counts/s")
 		}
+		time.Sleep(5 * time.Millisecond)
 	}
 
 }
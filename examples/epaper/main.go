@@ -0,0 +1,61 @@
+package main
+
+// Update small parts of an e-paper display using partial refreshes, with a
+// full refresh every few updates to remove ghosting. The display is powered
+// down between updates.
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/epaper"
+	"tinygo.org/x/drivers/waveshare-epd/epd2in13"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 8000000,
+		Mode:      0,
+	})
+
+	display := epd2in13.New(machine.SPI0, machine.P6, machine.P7, machine.P8, machine.P9)
+	display.Configure(epd2in13.Config{})
+	display.ClearBuffer()
+
+	manager := epaper.New(&display, epaper.Config{
+		FullRefreshEvery: 5,
+		PowerDown:        true,
+	})
+	manager.InvalidateAll()
+
+	black := color.RGBA{A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// Fill a progress bar, one block every two seconds.
+	for i := int16(0); ; i = (i + 1) % 10 {
+		if i == 0 {
+			// Start over with an empty bar.
+			fill(&display, 16, 100, 80, 40, white)
+			manager.Invalidate(16, 100, 80, 40)
+		}
+		fill(&display, 16+i*8, 100, 8, 40, black)
+		manager.Invalidate(16+i*8, 100, 8, 40)
+
+		// Keep calling Update until the changes are on the screen. Other
+		// work could be done in between.
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			manager.Update()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
+
+func fill(display *epd2in13.Device, x, y, width, height int16, c color.RGBA) {
+	for i := x; i < x+width; i++ {
+		for j := y; j < y+height; j++ {
+			display.SetPixel(i, j, c)
+		}
+	}
+}
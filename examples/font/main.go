@@ -0,0 +1,58 @@
+package main
+
+// Draw text into an image buffer and send it to a st7789 display in one go.
+
+import (
+	"image/color"
+	"machine"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/st7789"
+	"tinygo.org/x/tinyfont"
+	"tinygo.org/x/tinyfont/freesans"
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 8000000,
+		Mode:      0,
+	})
+	display := st7789.New(machine.SPI0,
+		machine.P6, // TFT_RESET
+		machine.P7, // TFT_DC
+		machine.P8, // TFT_CS
+		machine.P9) // TFT_LITE
+	display.Configure(st7789.Config{
+		Rotation:  st7789.NO_ROTATION,
+		RowOffset: 80,
+	})
+	width, _ := display.Size()
+
+	f := fromTinyfont(&freesans.Regular9pt7b)
+
+	// Render a few lines of text into a strip, and send the strip to the
+	// display.
+	img := pixel.NewImage[pixel.RGB565BE](int(width), f.LineHeight()*4)
+	img.FillSolidColor(pixel.NewRGB565BE(0, 0, 64))
+	text := "Hello from TinyGo! This text is wrapped to fit the display."
+	font.DrawWrapped(img, f, 4, int(f.Ascent), int(width)-8, text, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	display.DrawBitmap(0, 40, img)
+}
+
+// fromTinyfont converts a 1-bit tinyfont font to a font.Font. The glyph layout
+// is the same, so the bitmaps can be reused as-is.
+func fromTinyfont(tf *tinyfont.Font) *font.Font {
+	f := &font.Font{
+		BitsPerPixel: 1,
+		Ascent:       -tf.BBox[3],
+		Descent:      -(tf.BBox[1] + tf.BBox[3]),
+		YAdvance:     tf.YAdvance,
+		Glyphs:       make([]font.Glyph, len(tf.Glyphs)),
+		Fallback:     '?',
+	}
+	for i, g := range tf.Glyphs {
+		f.Glyphs[i] = font.Glyph(g)
+	}
+	return f
+}
@@ -0,0 +1,29 @@
+//go:build m5stack_core2
+
+package main
+
+import (
+	"machine"
+
+	"tinygo.org/x/drivers/ft6336"
+	"tinygo.org/x/drivers/i2csoft"
+)
+
+// Size of the touch area, which extends below the 320x240 display for the
+// touch buttons.
+const (
+	touchWidth  = 320
+	touchHeight = 270
+)
+
+// initDevices initializes the touch screen of each board.
+func initDevices() (touchScreen, error) {
+	i2c := i2csoft.New(machine.SCL0_PIN, machine.SDA0_PIN)
+	i2c.Configure(i2csoft.I2CConfig{Frequency: 100e3})
+
+	device := ft6336.New(i2c, machine.Pin(39))
+	device.Configure(ft6336.Config{})
+	device.SetPeriodActive(0x00)
+
+	return device, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	"tinygo.org/x/drivers/touch"
+	"tinygo.org/x/drivers/touch/calibration"
+	"tinygo.org/x/drivers/touch/gesture"
+)
+
+// touchScreen is a touch screen that supports multiple touches.
+type touchScreen interface {
+	touch.Pointer
+	touch.MultiPointer
+}
+
+// touchArea is the size of the touch area, to map the touches to pixels.
+type touchArea struct{}
+
+func (touchArea) Size() (int16, int16) {
+	return touchWidth, touchHeight
+}
+
+func main() {
+	device, _ := initDevices()
+
+	// The driver scales the touches to 16 bit, scale them back to pixels.
+	matrix := calibration.Scale(0, touchWidth*((1<<16)/touchWidth), 0, touchHeight*((1<<16)/touchHeight), touchWidth, touchHeight)
+	pointer := calibration.New(device, touchArea{}, matrix, calibration.Config{})
+	recognizer := gesture.New(gesture.Config{})
+
+	var points [2]touch.TrackedPoint
+	for {
+		n := pointer.ReadTouchPoints(points[:])
+		for _, p := range points[:n] {
+			println("touch", p.ID, p.Event.String(), p.X, p.Y)
+		}
+
+		g := recognizer.Update(points[:n])
+		switch g.Kind {
+		case gesture.None:
+		case gesture.Swipe:
+			println(g.Kind.String(), g.Direction.String(), "from", g.X, g.Y)
+		case gesture.Pinch:
+			println(g.Kind.String(), "at", g.X, g.Y, "distance", g.Distance, "change", g.Delta)
+		default:
+			println(g.Kind.String(), "at", g.X, g.Y)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -19,12 +19,16 @@ func main() {
 	)
 
 	display = hub75.New(machine.SPI0, 11, 12, 6, 10, 18, 20)
-	display.Configure(hub75.Config{
-		Width:      64,
-		Height:     32,
-		RowPattern: 16,
-		ColorDepth: 6,
+	err := display.Configure(hub75.Config{
+		Width:        64,
+		Height:       32,
+		RowPattern:   16,
+		ColorDepth:   6,
+		DoubleBuffer: true,
 	})
+	if err != nil {
+		println("failed to configure display:", err.Error())
+	}
 
 	colors := []color.RGBA{
 		{255, 0, 0, 255},
@@ -0,0 +1,63 @@
+package main
+
+// Dims the display after 10 seconds without input and turns it off after 30
+// seconds. Press the button to wake it up again.
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/backlight"
+	"tinygo.org/x/drivers/idle"
+	"tinygo.org/x/drivers/st7789"
+)
+
+// Configuration for the Adafruit Feather nRF52840 with an ST7789 display.
+// Please change the pins and PWM if you want to try this example on a different
+// board.
+var (
+	pwm       = machine.PWM0
+	resetPin  = machine.D5
+	dcPin     = machine.D6
+	csPin     = machine.D9
+	blPin     = machine.D10
+	buttonPin = machine.BUTTON
+)
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 8000000,
+		Mode:      0,
+	})
+	display := st7789.New(machine.SPI0, resetPin, dcPin, csPin, blPin)
+	display.Configure(st7789.Config{})
+	display.FillScreen(color.RGBA{0, 0, 255, 255})
+
+	light, err := backlight.New(pwm, blPin, backlight.Config{})
+	if err != nil {
+		for {
+			println("could not configure backlight:", err.Error())
+			time.Sleep(time.Second)
+		}
+	}
+
+	buttonPin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+
+	manager := idle.New(&display, &light, idle.Config{
+		DimAfter:   10 * time.Second,
+		SleepAfter: 30 * time.Second,
+	})
+	state := manager.State()
+	for {
+		if !buttonPin.Get() {
+			manager.Activity()
+		}
+		manager.Update()
+		if manager.State() != state {
+			state = manager.State()
+			println("display is", state.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
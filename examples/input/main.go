@@ -0,0 +1,43 @@
+package main
+
+// Reads four buttons to ground and a 4x4 keypad, and prints the input events.
+// Holding a button repeats it, pressing the first two buttons together is a
+// chord.
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/input"
+	"tinygo.org/x/drivers/keypad4x4"
+)
+
+func main() {
+	buttons := []machine.Pin{machine.GP2, machine.GP3, machine.GP4, machine.GP5}
+	for _, pin := range buttons {
+		pin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	}
+	keypad := keypad4x4.NewDevice(machine.GP6, machine.GP7, machine.GP8, machine.GP9,
+		machine.GP10, machine.GP11, machine.GP12, machine.GP13)
+	keypad.Configure()
+
+	events := make(chan input.Event, 8)
+	manager := input.New(input.Config{
+		RepeatDelay:    400 * time.Millisecond,
+		RepeatInterval: 100 * time.Millisecond,
+	}, input.Chan(events))
+	manager.Add(input.Pins(true, buttons[0], buttons[1], buttons[2], buttons[3]), len(buttons))
+	firstKey, _ := manager.Add(input.Keypad(keypad), 16)
+	go manager.Run(5 * time.Millisecond)
+
+	for event := range events {
+		switch {
+		case event.Type == input.Chord && event.Keys == 0b11:
+			println("buttons 0 and 1 pressed together")
+		case event.Key >= firstKey:
+			println("keypad", event.Key-firstKey, event.Type.String())
+		default:
+			println("button", event.Key, event.Type.String())
+		}
+	}
+}
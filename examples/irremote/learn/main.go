@@ -0,0 +1,71 @@
+// This example learns a code from any remote and sends it again when a button
+// is pressed. Codes of the supported protocols are decoded and re-encoded,
+// other codes are sent as they were captured.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/irremote"
+)
+
+var (
+	// Configuration for the Raspberry Pi Pico: an IR receiver on GP26, an IR
+	// LED (through a transistor) on GP16 and a button to ground on GP15.
+	pinIRIn  = machine.GP26
+	pwm      = machine.PWM0
+	pinIROut = machine.GP16
+	button   = machine.GP15
+)
+
+func main() {
+	receiver := irremote.NewReceiver(pinIRIn)
+	receiver.Configure()
+	receiver.StartCapture()
+
+	sender := irremote.NewSender(pwm, pinIROut)
+	err := sender.Configure()
+	if err != nil {
+		println("failed to configure PWM")
+		return
+	}
+
+	button.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+
+	var (
+		pulses  [irremote.MaxPulses]time.Duration
+		learned []time.Duration
+		data    irremote.Data
+		decoded bool
+		pressed bool
+	)
+	for {
+		if n := receiver.ReadPulses(pulses[:]); n > 0 {
+			learned = append(learned[:0], pulses[:n]...)
+			data, decoded = irremote.Decode(learned, irremote.Protocols...)
+			if decoded {
+				println("learned", data.Protocol.String(), "address", data.Address, "command", data.Command)
+			} else {
+				println("learned unknown code with", n, "pulses")
+			}
+		}
+
+		if !button.Get() && !pressed && len(learned) > 0 {
+			if decoded {
+				// Toggle codes must change on every key press.
+				data.Flags ^= irremote.DataFlagToggle
+				err = sender.Send(data)
+			} else {
+				err = sender.SendPulses(learned, 38000)
+			}
+			if err != nil {
+				println("failed to send:", err.Error())
+			}
+			println("sent")
+		}
+		pressed = !button.Get()
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,56 @@
+// Draws a short animation into the frames of the IS31FL3731 on an Adafruit
+// CharlieWing, and lets the chip play it by itself.
+package main
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/is31fl3731"
+	"tinygo.org/x/tinyfont"
+)
+
+func main() {
+	bus := machine.I2C0
+	err := bus.Configure(machine.I2CConfig{})
+	if err != nil {
+		println("could not configure I2C:", err)
+		return
+	}
+
+	display := is31fl3731.NewAdafruitCharlieWing15x7(bus, is31fl3731.I2C_ADDRESS_74)
+	err = display.Configure()
+	if err != nil {
+		println("could not configure is31fl3731 driver:", err)
+		return
+	}
+
+	// Draw the text "GO" in a different brightness in each frame.
+	for frame := uint8(0); frame < 8; frame++ {
+		level := uint8(32 + int(frame)*31)
+		c := color.RGBA{R: level, G: level, B: level, A: 255}
+		display.ClearBuffer()
+		tinyfont.WriteLine(&display, &tinyfont.TomThumb, int16(frame%4), 6, "GO", c)
+		display.SetDrawFrame(frame)
+		display.Display()
+	}
+
+	// Play all 8 frames in an endless loop, and fade the LEDs in and out.
+	display.StartAutoPlay(is31fl3731.AutoPlay{
+		FrameDelay: 150 * time.Millisecond,
+	})
+	display.EnableBreath(is31fl3731.Breath{
+		FadeIn:     400 * time.Millisecond,
+		FadeOut:    400 * time.Millisecond,
+		Extinguish: 50 * time.Millisecond,
+	})
+
+	for {
+		frame, _, err := display.AutoPlayState()
+		if err == nil {
+			println("showing frame", frame)
+		}
+		time.Sleep(time.Second)
+	}
+}
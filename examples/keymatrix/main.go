@@ -0,0 +1,56 @@
+package main
+
+// Scans a 3x4 macro pad with a diode per key, wired to GPIO pins. The bottom
+// right key switches to a second layer while held.
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/keymatrix"
+)
+
+func main() {
+	rows := []machine.Pin{machine.GP2, machine.GP3, machine.GP4}
+	columns := []machine.Pin{machine.GP6, machine.GP7, machine.GP8, machine.GP9}
+	for _, pin := range rows {
+		pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	}
+	for _, pin := range columns {
+		pin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	}
+
+	const T = keymatrix.Transparent
+	keymap := keymatrix.NewKeymap(len(columns),
+		[]keymatrix.Code{
+			'7', '8', '9', '/',
+			'4', '5', '6', '*',
+			'1', '2', '3', keymatrix.Momentary(1),
+		},
+		[]keymatrix.Code{
+			'A', 'B', 'C', '-',
+			'D', 'E', 'F', '+',
+			'0', '.', '=', T,
+		},
+	)
+
+	scanner, err := keymatrix.New(keymatrix.PinRows(rows...), keymatrix.PinColumns(columns...), keymatrix.Config{
+		Rows:    len(rows),
+		Columns: len(columns),
+		Diodes:  true,
+		Keymap:  keymap,
+	}, func(e keymatrix.Event) {
+		if e.Pressed {
+			println("pressed", string(rune(e.Code)))
+		}
+	})
+	if err != nil {
+		println(err.Error())
+		return
+	}
+
+	for {
+		scanner.Scan()
+		time.Sleep(5 * time.Millisecond)
+	}
+}
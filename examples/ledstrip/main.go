@@ -0,0 +1,43 @@
+// Shows a few effects on an 8x8 WS2812 matrix that is wired in a serpentine
+// pattern, limited to 500mA so that it can be powered over USB.
+package main
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/ledstrip"
+	"tinygo.org/x/drivers/ws2812"
+)
+
+var neo = machine.GP16
+
+func main() {
+	neo.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	ws := ws2812.NewWS2812(neo)
+
+	strip := ledstrip.NewMatrix(ws, ledstrip.Matrix{
+		Width:      8,
+		Height:     8,
+		Serpentine: true,
+	}, ledstrip.Config{
+		Gamma:      2.2,
+		MaxCurrent: 500,
+	})
+	strip.SetBrightness(128)
+
+	effects := []ledstrip.Effect{
+		ledstrip.Rainbow{Length: 16},
+		ledstrip.Comet{Color: ledstrip.HSV(150, 255, 255), Tail: 12},
+		ledstrip.Twinkle{Color: ledstrip.HSV(40, 200, 255)},
+	}
+
+	start := time.Now()
+	for {
+		t := time.Since(start)
+		effect := effects[int(t/(10*time.Second))%len(effects)]
+		strip.Render(effect, t)
+		strip.Display()
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,46 @@
+// Scrolls text over a 32x8 LED matrix made of four daisy-chained MAX7219
+// modules (like the common FC-16 modules).
+package main
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/max72xx"
+	"tinygo.org/x/tinyfont"
+)
+
+func main() {
+	// Pins for Arduino Nano 33 IOT
+	err := machine.SPI0.Configure(machine.SPIConfig{
+		SDO:       machine.D11,
+		SCK:       machine.D13,
+		Frequency: 10000000,
+	})
+	if err != nil {
+		println(err.Error())
+	}
+
+	dev := max72xx.NewDevice(machine.SPI0, machine.D6)
+	display := max72xx.NewMatrix(dev)
+	display.Configure(max72xx.MatrixConfig{
+		Width:     4,
+		Height:    1,
+		Intensity: 2,
+	})
+
+	text := "Hello TinyGo!"
+	_, textWidth := tinyfont.LineWidth(&tinyfont.TomThumb, text)
+	width, _ := display.Size()
+	on := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	for {
+		for x := width; x > -int16(textWidth); x-- {
+			display.ClearBuffer()
+			tinyfont.WriteLine(display, &tinyfont.TomThumb, x, 6, text, on)
+			display.Display()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
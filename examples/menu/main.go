@@ -0,0 +1,98 @@
+package main
+
+// Shows a settings menu on an SSD1306 display, controlled by a rotary encoder
+// with a push button. Turning the encoder moves through the menu and changes
+// values, a short press selects and a long press goes back.
+
+import (
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/encoders"
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/input"
+	"tinygo.org/x/drivers/menu"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/ssd1306"
+	"tinygo.org/x/tinyfont"
+	"tinygo.org/x/tinyfont/proggy"
+)
+
+var (
+	brightness = 80
+	mode       = 0
+	beep       = true
+	alarm      = 7 * time.Hour
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{
+		Frequency: 400 * machine.KHz,
+		SDA:       machine.GP4,
+		SCL:       machine.GP5,
+	})
+	display := ssd1306.NewI2C(machine.I2C0)
+	display.Configure(ssd1306.Config{Address: 0x3C, Width: 128, Height: 64})
+	display.ClearDisplay()
+
+	enc := encoders.NewQuadratureViaInterrupt(machine.GP2, machine.GP3)
+	enc.Configure(encoders.QuadratureConfig{Precision: 4})
+
+	button := machine.GP6
+	button.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+	events := make(chan input.Event, 4)
+	buttons := input.New(input.Config{LongPress: 500 * time.Millisecond}, input.Chan(events))
+	buttons.Add(input.Pins(true, button), 1)
+	go buttons.Run(5 * time.Millisecond)
+
+	root := menu.New("Settings",
+		menu.Int("Brightness", &brightness, 0, 100, 5, func(v int) {
+			println("brightness", v)
+		}),
+		menu.Enum("Mode", &mode, []string{"auto", "heat", "cool"}, nil),
+		menu.New("Alarm",
+			menu.Time("Time", &alarm, nil),
+			menu.Bool("Beep", &beep, nil),
+		),
+		menu.Action("Save", func() {
+			println("saved")
+		}),
+	)
+	renderer := menu.NewPixel[pixel.Monochrome](display, menu.PixelConfig{
+		Font: fromTinyfont(&proggy.TinySZ8pt7b),
+	})
+	nav := menu.NewNavigator(root, renderer, menu.Config{ShowTitle: true})
+
+	for {
+		nav.Scroll(enc.Steps())
+		select {
+		case event := <-events:
+			switch {
+			case event.Type == input.LongPress:
+				nav.Handle(menu.Back)
+			case event.Type == input.Release && event.Duration < 500*time.Millisecond:
+				nav.Handle(menu.Select)
+			}
+		default:
+		}
+		nav.Draw()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// fromTinyfont converts a 1-bit tinyfont font to a font.Font. The glyph layout
+// is the same, so the bitmaps can be reused as-is.
+func fromTinyfont(tf *tinyfont.Font) *font.Font {
+	f := &font.Font{
+		BitsPerPixel: 1,
+		Ascent:       -tf.BBox[3],
+		Descent:      -(tf.BBox[1] + tf.BBox[3]),
+		YAdvance:     tf.YAdvance,
+		Glyphs:       make([]font.Glyph, len(tf.Glyphs)),
+		Fallback:     '?',
+	}
+	for i, g := range tf.Glyphs {
+		f.Glyphs[i] = font.Glyph(g)
+	}
+	return f
+}
@@ -0,0 +1,46 @@
+package main
+
+// This example shows the hardware scroll and contrast fade of the SSD1306, on
+// two 128x64 I2C displays that share a bus at the addresses 0x3C and 0x3D.
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/ssd1306"
+	"tinygo.org/x/tinyfont"
+	"tinygo.org/x/tinyfont/proggy"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{
+		Frequency: 400 * machine.KHz,
+	})
+
+	left := ssd1306.NewI2C(machine.I2C0)
+	left.Configure(ssd1306.Config{Address: 0x3C})
+	right := ssd1306.NewI2C(machine.I2C0)
+	right.Configure(ssd1306.Config{Address: 0x3D})
+
+	white := color.RGBA{255, 255, 255, 255}
+	for _, display := range []*ssd1306.Device{left, right} {
+		display.ClearBuffer()
+		tinyfont.WriteLine(display, &proggy.TinySZ8pt7b, 4, 12, "hardware scroll", white)
+		tinyfont.WriteLine(display, &proggy.TinySZ8pt7b, 4, 40, "contrast fade", white)
+		display.Display()
+	}
+
+	// The controllers keep scrolling by themselves.
+	left.StartScroll(ssd1306.ScrollLeft, 0, 1, ssd1306.Scroll5Frames)
+	right.StartDiagonalScroll(ssd1306.ScrollRight, 0, 3, ssd1306.Scroll2Frames, 1)
+
+	for {
+		left.FadeContrast(0, time.Second)
+		left.FadeContrast(255, time.Second)
+		right.InvertColors(true)
+		time.Sleep(time.Second)
+		right.InvertColors(false)
+		time.Sleep(time.Second)
+	}
+}
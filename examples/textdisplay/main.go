@@ -0,0 +1,38 @@
+// Shows a counter and a scrolling message on a character LCD. The same code
+// works on any textdisplay.Device, for example a tm1637 or max72xx display.
+package main
+
+import (
+	"fmt"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers/hd44780i2c"
+	"tinygo.org/x/drivers/textdisplay"
+)
+
+func main() {
+	machine.I2C0.Configure(machine.I2CConfig{
+		Frequency: machine.TWI_FREQ_400KHZ,
+	})
+
+	lcd := hd44780i2c.New(machine.I2C0, 0x27)
+	lcd.Configure(hd44780i2c.Config{
+		Width:  16,
+		Height: 2,
+	})
+
+	display := textdisplay.New(&lcd)
+	display.Clear()
+
+	// A heart, shown for character code 0.
+	display.CreateGlyph(0, []byte{0x00, 0x0A, 0x1F, 0x1F, 0x0E, 0x04, 0x00, 0x00})
+
+	marquee := textdisplay.NewMarquee(display, 1, "Hello from TinyGo \x00 character displays!")
+	for i := 0; ; i++ {
+		display.SetCursor(0, 0)
+		fmt.Fprintf(display, "count: %-8d", i)
+		marquee.Step()
+		time.Sleep(300 * time.Millisecond)
+	}
+}
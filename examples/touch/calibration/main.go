@@ -0,0 +1,76 @@
+package main
+
+// Calibrates the resistive touch screen of the PyPortal, then paints where the
+// screen is touched. The calibration is printed on the serial console, so that
+// it can be hardcoded or stored in flash instead of calibrating every time.
+
+import (
+	"image/color"
+	"machine"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/ili9341"
+	"tinygo.org/x/drivers/touch/calibration"
+	"tinygo.org/x/drivers/touch/resistive"
+)
+
+var (
+	black = color.RGBA{A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+// Touches are ignored below this pressure, on the 16-bit scale of the
+// resistive driver.
+const pressureThreshold = 100 << 6
+
+func main() {
+	machine.TFT_BACKLIGHT.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	machine.InitADC()
+	resistiveTouch := &resistive.FourWire{}
+	resistiveTouch.Configure(&resistive.FourWireConfig{
+		YP: machine.TOUCH_YD,
+		YM: machine.TOUCH_YU,
+		XP: machine.TOUCH_XR,
+		XM: machine.TOUCH_XL,
+	})
+
+	display := ili9341.NewParallel(
+		machine.LCD_DATA0,
+		machine.TFT_WR,
+		machine.TFT_DC,
+		machine.TFT_CS,
+		machine.TFT_RESET,
+		machine.TFT_RD,
+	)
+	display.Configure(ili9341.Config{})
+	display.FillScreen(black)
+	machine.TFT_BACKLIGHT.High()
+
+	config := calibration.Config{PressureThreshold: pressureThreshold}
+	matrix, err := calibration.Calibrate(display, resistiveTouch, 5, config)
+	if err != nil {
+		println("calibration failed:", err.Error())
+		return
+	}
+	data, _ := matrix.MarshalBinary()
+	print("calibration:")
+	for _, b := range data {
+		print(" ", b)
+	}
+	println()
+
+	// The calibration doesn't depend on the rotation, so the display can be
+	// rotated freely afterwards.
+	display.SetRotation(drivers.Rotation90)
+	display.FillScreen(black)
+	pointer := calibration.New(resistiveTouch, display, matrix, config)
+
+	for {
+		point := pointer.ReadTouchPoint()
+		if point.Z > 0 {
+			display.FillRectangle(int16(point.X)-1, int16(point.Y)-1, 3, 3, white)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
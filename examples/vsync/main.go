@@ -0,0 +1,68 @@
+package main
+
+// Animate a bouncing square on a st7789 display without tearing, using the TE
+// pin of the display to track the panel refresh.
+
+import (
+	"machine"
+
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/st7789"
+	"tinygo.org/x/drivers/vsync"
+)
+
+const tePin = machine.GP14
+
+func main() {
+	machine.SPI0.Configure(machine.SPIConfig{
+		Frequency: 62500000,
+		Mode:      0,
+	})
+	display := st7789.New(machine.SPI0,
+		machine.GP12, // TFT_RESET
+		machine.GP8,  // TFT_DC
+		machine.GP9,  // TFT_CS
+		machine.GP13) // TFT_LITE
+	display.Configure(st7789.Config{
+		Rotation:  st7789.NO_ROTATION,
+		RowOffset: 80,
+		FrameRate: st7789.FRAMERATE_39,
+	})
+	width, height := display.Size()
+
+	// Track the panel refresh using the TE pin.
+	display.EnableTEOutput(true)
+	te := vsync.NewTE(int(height))
+	tePin.Configure(machine.PinConfig{Mode: machine.PinInput})
+	tePin.SetInterrupt(machine.PinRising, func(machine.Pin) {
+		te.Interrupt()
+	})
+	pacer := vsync.New[pixel.RGB565BE](&display, te)
+
+	frame := pixel.NewImage[pixel.RGB565BE](int(width), int(height))
+	background := pixel.NewRGB565BE(0, 0, 0)
+	square := pixel.NewRGB565BE(255, 128, 0)
+	x, y, dx, dy := 0, 0, 3, 2
+	for {
+		frame.FillSolidColor(background)
+		for sy := y; sy < y+32; sy++ {
+			for sx := x; sx < x+32; sx++ {
+				frame.Set(sx, sy, square)
+			}
+		}
+		if err := pacer.Present(frame); err != nil {
+			// No TE pulses (yet), draw without pacing.
+			display.DrawBitmap(0, 0, frame)
+		}
+
+		x, y = x+dx, y+dy
+		if x < 0 || x+32 > int(width) {
+			dx = -dx
+			x += 2 * dx
+		}
+		if y < 0 || y+32 > int(height) {
+			dy = -dy
+			y += 2 * dy
+		}
+	}
+}
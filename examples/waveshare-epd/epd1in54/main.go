@@ -32,5 +32,5 @@ func main() {
 	tinyfont.WriteLineRotated(&display, &gophers.Regular58pt, 0, 0, "J K L", black, tinyfont.ROTATION_90)
 
 	display.Display()
-	display.Sleep()
+	display.DeepSleep()
 }
@@ -0,0 +1,110 @@
+package main
+
+// A small control panel on the PyPortal, using the ili9341 display and the
+// resistive touch screen.
+
+import (
+	"machine"
+	"strconv"
+	"time"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/ili9341"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/touch"
+	"tinygo.org/x/drivers/touch/resistive"
+	"tinygo.org/x/drivers/widget"
+	"tinygo.org/x/tinyfont"
+	"tinygo.org/x/tinyfont/freesans"
+)
+
+// Raw touch screen values at the edges of the display.
+const (
+	Xmin = 750
+	Xmax = 325
+	Ymin = 840
+	Ymax = 240
+)
+
+func main() {
+	machine.TFT_BACKLIGHT.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	machine.InitADC()
+	resistiveTouch := &resistive.FourWire{}
+	resistiveTouch.Configure(&resistive.FourWireConfig{
+		YP: machine.TOUCH_YD,
+		YM: machine.TOUCH_YU,
+		XP: machine.TOUCH_XR,
+		XM: machine.TOUCH_XL,
+	})
+
+	display := ili9341.NewParallel(
+		machine.LCD_DATA0,
+		machine.TFT_WR,
+		machine.TFT_DC,
+		machine.TFT_CS,
+		machine.TFT_RESET,
+		machine.TFT_RD,
+	)
+	display.Configure(ili9341.Config{})
+	machine.TFT_BACKLIGHT.High()
+
+	ui := widget.New[pixel.RGB565BE](display, widget.Config{
+		Font: fromTinyfont(&freesans.Regular9pt7b),
+	})
+
+	modes := []string{"Off", "Heat", "Cool", "Fan", "Auto"}
+	modeList := widget.ListState{Selected: 0}
+	target := 21
+	power := false
+	for {
+		ui.Begin(readTouch(resistiveTouch))
+		ui.Label(widget.Rect{10, 10, 220, 24}, "Thermostat")
+		ui.Toggle(widget.Rect{10, 40, 220, 30}, "Power", &power)
+		ui.Label(widget.Rect{10, 80, 220, 24}, "Target: "+strconv.Itoa(target)+" C")
+		ui.Slider(widget.Rect{10, 110, 220, 30}, &target, 10, 30)
+		if ui.Button(widget.Rect{10, 150, 105, 36}, "-") && target > 10 {
+			target--
+		}
+		if ui.Button(widget.Rect{125, 150, 105, 36}, "+") && target < 30 {
+			target++
+		}
+		ui.List(widget.Rect{10, 196, 220, 80}, modes, &modeList)
+		ui.Progress(widget.Rect{10, 290, 220, 16}, target-10, 20)
+		ui.End()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readTouch converts a raw touch point to display coordinates.
+func readTouch(t *resistive.FourWire) touch.Point {
+	point := t.ReadTouchPoint()
+	if point.Z>>6 <= 100 {
+		return touch.Point{}
+	}
+	return touch.Point{
+		X: mapval(point.X>>6, Xmin, Xmax, 0, 240),
+		Y: mapval(point.Y>>6, Ymin, Ymax, 0, 320),
+		Z: 1,
+	}
+}
+
+func mapval(x int, inMin int, inMax int, outMin int, outMax int) int {
+	return (x-inMin)*(outMax-outMin)/(inMax-inMin) + outMin
+}
+
+// fromTinyfont converts a 1-bit tinyfont font to a font.Font. The glyph layout
+// is the same, so the bitmaps can be reused as-is.
+func fromTinyfont(tf *tinyfont.Font) *font.Font {
+	f := &font.Font{
+		BitsPerPixel: 1,
+		Ascent:       -tf.BBox[3],
+		Descent:      -(tf.BBox[1] + tf.BBox[3]),
+		YAdvance:     tf.YAdvance,
+		Glyphs:       make([]font.Glyph, len(tf.Glyphs)),
+		Fallback:     '?',
+	}
+	for i, g := range tf.Glyphs {
+		f.Glyphs[i] = font.Glyph(g)
+	}
+	return f
+}
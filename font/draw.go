@@ -0,0 +1,220 @@
+package font
+
+import (
+	"image/color"
+	"unicode/utf8"
+
+	"tinygo.org/x/drivers/pixel"
+)
+
+// Draw draws the text into the image, with the baseline of the first line at
+// y and the left side of the text at x. Newlines move the pen back to x on the
+// next line. Glyphs that fall partly outside the image are clipped.
+//
+// It returns the pen position after the last glyph, which can be used to
+// continue drawing on the same line.
+func Draw[T pixel.Color](img pixel.Image[T], f *Font, x, y int, text string, c color.RGBA) (int, int) {
+	penX, penY := x, y
+	prev := rune(-1)
+	for _, r := range text {
+		if r == '\n' {
+			penX = x
+			penY += f.LineHeight()
+			prev = -1
+			continue
+		}
+		if prev >= 0 {
+			penX += f.Kern(prev, r)
+		}
+		prev = r
+		g := f.Glyph(r)
+		if g == nil {
+			continue
+		}
+		DrawGlyph(img, f, g, penX, penY, c)
+		penX += int(g.XAdvance)
+	}
+	return penX, penY
+}
+
+// DrawRune draws a single rune with the pen at x, y and returns the horizontal
+// advance.
+func DrawRune[T pixel.Color](img pixel.Image[T], f *Font, x, y int, r rune, c color.RGBA) int {
+	g := f.Glyph(r)
+	if g == nil {
+		return 0
+	}
+	DrawGlyph(img, f, g, x, y, c)
+	return int(g.XAdvance)
+}
+
+// DrawGlyph blends a single glyph into the image with the pen at x, y.
+func DrawGlyph[T pixel.Color](img pixel.Image[T], f *Font, g *Glyph, x, y int, c color.RGBA) {
+	width, height := img.Size()
+	left := x + int(g.XOffset)
+	top := y + int(g.YOffset)
+
+	// Clip the glyph to the image.
+	startX, endX := 0, int(g.Width)
+	startY, endY := 0, int(g.Height)
+	if left < 0 {
+		startX = -left
+	}
+	if top < 0 {
+		startY = -top
+	}
+	if left+endX > width {
+		endX = width - left
+	}
+	if top+endY > height {
+		endY = height - top
+	}
+
+	// Opaque pixels are all the same color, so only convert it once.
+	solid := pixel.NewColor[T](c.R, c.G, c.B)
+	for gy := startY; gy < endY; gy++ {
+		for gx := startX; gx < endX; gx++ {
+			alpha := f.alpha(g, gx, gy)
+			if c.A != 255 {
+				alpha = uint8((uint16(alpha)*uint16(c.A) + 127) / 255)
+			}
+			switch alpha {
+			case 0:
+				// transparent, nothing to draw
+			case 255:
+				img.Set(left+gx, top+gy, solid)
+			default:
+				px, py := left+gx, top+gy
+				img.Set(px, py, Blend(img.Get(px, py), c, alpha))
+			}
+		}
+	}
+}
+
+// Blend returns the color dst with c drawn on top of it using the given alpha
+// value (0-255). The alpha channel of c itself is ignored.
+//
+// Monochrome colors can't be blended, so c is only used when alpha is at least
+// 50%.
+func Blend[T pixel.Color](dst T, c color.RGBA, alpha uint8) T {
+	if dst.BitsPerPixel() == 1 {
+		if alpha >= 128 {
+			return pixel.NewColor[T](c.R, c.G, c.B)
+		}
+		return dst
+	}
+	bg := dst.RGBA()
+	a := uint16(alpha)
+	return pixel.NewColor[T](
+		uint8((uint16(c.R)*a+uint16(bg.R)*(255-a)+127)/255),
+		uint8((uint16(c.G)*a+uint16(bg.G)*(255-a)+127)/255),
+		uint8((uint16(c.B)*a+uint16(bg.B)*(255-a)+127)/255),
+	)
+}
+
+// Measure returns the bounding box of the text relative to the pen position of
+// the first glyph: the width of the widest line and the total height from the
+// ascent of the first line to the descent of the last line.
+func Measure(f *Font, text string) (width, height int) {
+	lines := 1
+	lineWidth := 0
+	prev := rune(-1)
+	for _, r := range text {
+		if r == '\n' {
+			if lineWidth > width {
+				width = lineWidth
+			}
+			lineWidth = 0
+			lines++
+			prev = -1
+			continue
+		}
+		if prev >= 0 {
+			lineWidth += f.Kern(prev, r)
+		}
+		prev = r
+		if g := f.Glyph(r); g != nil {
+			lineWidth += int(g.XAdvance)
+		}
+	}
+	if lineWidth > width {
+		width = lineWidth
+	}
+	height = (lines-1)*f.LineHeight() + int(f.Ascent) - int(f.Descent)
+	return width, height
+}
+
+// LineWidth returns the horizontal advance of a single line of text.
+func LineWidth(f *Font, text string) int {
+	width := 0
+	prev := rune(-1)
+	for _, r := range text {
+		if prev >= 0 {
+			width += f.Kern(prev, r)
+		}
+		prev = r
+		if g := f.Glyph(r); g != nil {
+			width += int(g.XAdvance)
+		}
+	}
+	return width
+}
+
+// NextLine splits off the first line of text that fits within maxWidth pixels.
+// Lines are broken at spaces where possible and at any rune otherwise. Explicit
+// newlines are honored. The returned line doesn't include the trailing space
+// or newline, and rest starts at the next line. It doesn't allocate, so it can
+// be used in a loop:
+//
+//	for text != "" {
+//		var line string
+//		line, text = font.NextLine(f, text, width)
+//		font.Draw(img, f, 0, y, line, c)
+//		y += f.LineHeight()
+//	}
+func NextLine(f *Font, text string, maxWidth int) (line, rest string) {
+	width := 0
+	lastSpace := -1
+	prev := rune(-1)
+	for i, r := range text {
+		if r == '\n' {
+			return text[:i], text[i+1:]
+		}
+		if r == ' ' {
+			lastSpace = i
+		}
+		if prev >= 0 {
+			width += f.Kern(prev, r)
+		}
+		prev = r
+		if g := f.Glyph(r); g != nil {
+			width += int(g.XAdvance)
+		}
+		if width > maxWidth && r != ' ' {
+			if lastSpace >= 0 {
+				return text[:lastSpace], text[lastSpace+1:]
+			}
+			if i == 0 {
+				// Not even a single rune fits, so put it on a line of its own
+				// to guarantee progress.
+				_, size := utf8.DecodeRuneInString(text)
+				return text[:size], text[size:]
+			}
+			return text[:i], text[i:]
+		}
+	}
+	return text, ""
+}
+
+// DrawWrapped draws the text with the baseline of the first line at y,
+// wrapping lines so that they fit within maxWidth pixels. It returns the
+// baseline of the line after the last line that was drawn.
+func DrawWrapped[T pixel.Color](img pixel.Image[T], f *Font, x, y, maxWidth int, text string, c color.RGBA) int {
+	for text != "" {
+		var line string
+		line, text = NextLine(f, text, maxWidth)
+		Draw(img, f, x, y, line, c)
+		y += f.LineHeight()
+	}
+	return y
+}
@@ -0,0 +1,139 @@
+// Package font renders anti-aliased text directly into pixel.Image buffers.
+//
+// Fonts store each glyph as a bitmap of 1, 2 or 4 bit alpha values. Glyphs are
+// blended against the pixels already present in the image, so text can be
+// drawn on top of any background. The resulting image can then be sent to a
+// display in one go using the DrawBitmap method that most color displays
+// provide (st7789, ili9341, gc9a01, etc). This is a lot faster than drawing
+// text pixel by pixel using SetPixel.
+//
+// The glyph layout is the same as the one used in tinyfont, so 1-bit tinyfont
+// fonts can be used by copying the glyphs and setting BitsPerPixel to 1.
+package font // import "tinygo.org/x/drivers/font"
+
+// Glyph is a single character in a font.
+//
+// The bitmap is stored row by row, starting at the most significant bit of the
+// first byte. Rows are not padded: a new row starts right after the last pixel
+// of the previous row. Each pixel is an alpha value of Font.BitsPerPixel bits,
+// where 0 is fully transparent and the maximum value is fully opaque.
+type Glyph struct {
+	Rune     rune
+	Width    uint8
+	Height   uint8
+	XAdvance uint8
+	XOffset  int8 // offset from the pen position to the left of the bitmap
+	YOffset  int8 // offset from the baseline to the top of the bitmap
+	Bitmaps  []byte
+}
+
+// KerningPair adjusts the horizontal distance between two runes.
+type KerningPair struct {
+	Left   rune
+	Right  rune
+	Adjust int8
+}
+
+// Font is a set of glyphs with some additional metrics.
+type Font struct {
+	// Number of bits for each pixel in the glyph bitmaps: 1, 2 or 4.
+	BitsPerPixel uint8
+
+	// Distance from the baseline to the top and bottom of the tallest glyphs.
+	// Ascent is positive (above the baseline), Descent is usually negative.
+	Ascent  int8
+	Descent int8
+
+	// Distance between two baselines.
+	YAdvance uint8
+
+	// Glyphs in the font, sorted by rune.
+	Glyphs []Glyph
+
+	// Kerning pairs, sorted by Left and then Right rune. May be nil.
+	Kerning []KerningPair
+
+	// Rune to use when a rune is not present in the font. If this rune isn't
+	// present either, the missing rune is skipped.
+	Fallback rune
+}
+
+// Glyph returns the glyph for the given rune, or the fallback glyph if the rune
+// is not present in the font. It returns nil if neither could be found.
+func (f *Font) Glyph(r rune) *Glyph {
+	if g := f.lookup(r); g != nil {
+		return g
+	}
+	if f.Fallback != 0 && f.Fallback != r {
+		return f.lookup(f.Fallback)
+	}
+	return nil
+}
+
+// lookup does a binary search for the given rune.
+func (f *Font) lookup(r rune) *Glyph {
+	low, high := 0, len(f.Glyphs)
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if f.Glyphs[mid].Rune < r {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	if low < len(f.Glyphs) && f.Glyphs[low].Rune == r {
+		return &f.Glyphs[low]
+	}
+	return nil
+}
+
+// Kern returns the horizontal adjustment between the two runes, which is
+// usually zero or negative.
+func (f *Font) Kern(left, right rune) int {
+	low, high := 0, len(f.Kerning)
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		pair := f.Kerning[mid]
+		if pair.Left < left || (pair.Left == left && pair.Right < right) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	if low < len(f.Kerning) && f.Kerning[low].Left == left && f.Kerning[low].Right == right {
+		return int(f.Kerning[low].Adjust)
+	}
+	return 0
+}
+
+// LineHeight returns the distance between two baselines in pixels.
+func (f *Font) LineHeight() int {
+	if f.YAdvance != 0 {
+		return int(f.YAdvance)
+	}
+	return int(f.Ascent) - int(f.Descent)
+}
+
+// alpha returns the alpha value (0-255) of the glyph pixel at x, y.
+func (f *Font) alpha(g *Glyph, x, y int) uint8 {
+	bpp := int(f.BitsPerPixel)
+	if bpp == 0 {
+		bpp = 1
+	}
+	bitIndex := (y*int(g.Width) + x) * bpp
+	byteIndex := bitIndex / 8
+	if byteIndex >= len(g.Bitmaps) {
+		return 0
+	}
+	shift := 8 - bpp - bitIndex%8
+	mask := uint8(1)<<bpp - 1
+	value := (g.Bitmaps[byteIndex] >> shift) & mask
+	switch bpp {
+	case 1:
+		return -value // 0 or 0xff
+	case 2:
+		return value * 0x55
+	default:
+		return value * 0x11
+	}
+}
@@ -0,0 +1,128 @@
+package font_test
+
+import (
+	"image/color"
+	"testing"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// testFont is a tiny 2-bit font with 2x2 glyphs.
+var testFont = &font.Font{
+	BitsPerPixel: 2,
+	Ascent:       2,
+	Descent:      0,
+	YAdvance:     3,
+	Glyphs: []font.Glyph{
+		{Rune: ' ', XAdvance: 2},
+		{Rune: '?', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b11_00_00_11}},
+		{Rune: 'A', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b11_11_11_11}},
+		{Rune: 'V', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b10_01_00_00}},
+		{Rune: 'é', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b01_01_01_01}},
+	},
+	Kerning: []font.KerningPair{
+		{Left: 'A', Right: 'V', Adjust: -1},
+		{Left: 'V', Right: 'A', Adjust: -1},
+	},
+	Fallback: '?',
+}
+
+func TestGlyphLookup(t *testing.T) {
+	for _, tc := range []struct {
+		r, expected rune
+	}{
+		{'A', 'A'},
+		{'é', 'é'},
+		{'x', '?'}, // fallback
+	} {
+		g := testFont.Glyph(tc.r)
+		if g == nil || g.Rune != tc.expected {
+			t.Errorf("Glyph(%q): expected %q, got %v", tc.r, tc.expected, g)
+		}
+	}
+	if kern := testFont.Kern('A', 'V'); kern != -1 {
+		t.Errorf("Kern('A', 'V'): expected -1, got %d", kern)
+	}
+	if kern := testFont.Kern('A', 'A'); kern != 0 {
+		t.Errorf("Kern('A', 'A'): expected 0, got %d", kern)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	for _, tc := range []struct {
+		text          string
+		width, height int
+	}{
+		{"", 0, 2},
+		{"A", 3, 2},
+		{"AA", 6, 2},
+		{"AV", 5, 2}, // kerned
+		{"AAA\nA", 9, 5},
+	} {
+		width, height := font.Measure(testFont, tc.text)
+		if width != tc.width || height != tc.height {
+			t.Errorf("Measure(%q): expected %dx%d, got %dx%d", tc.text, tc.width, tc.height, width, height)
+		}
+	}
+}
+
+func TestNextLine(t *testing.T) {
+	text := "AA AA AAAA"
+	var lines []string
+	for text != "" {
+		var line string
+		line, text = font.NextLine(testFont, text, 9)
+		lines = append(lines, line)
+	}
+	expected := []string{"AA", "AA", "AAA", "A"}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected lines %q, got %q", expected, lines)
+	}
+	for i := range lines {
+		if lines[i] != expected[i] {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	img := pixel.NewImage[pixel.RGB888](8, 3)
+	img.FillSolidColor(pixel.NewRGB888(0, 0, 0))
+	x, y := font.Draw(img, testFont, 0, 2, "AVx", color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	if x != 8 || y != 2 {
+		t.Errorf("Draw: expected pen at 8, 2, got %d, %d", x, y)
+	}
+
+	// The 'A' is fully opaque.
+	if c := img.Get(0, 0); c != pixel.NewRGB888(255, 255, 255) {
+		t.Errorf("expected opaque pixel, got %v", c)
+	}
+	// The 'V' starts at x=2 due to kerning, and has alpha 2/3 and 1/3.
+	if c := img.Get(2, 0); c != pixel.NewRGB888(170, 170, 170) {
+		t.Errorf("expected 2/3 blended pixel, got %v", c)
+	}
+	if c := img.Get(3, 0); c != pixel.NewRGB888(85, 85, 85) {
+		t.Errorf("expected 1/3 blended pixel, got %v", c)
+	}
+	// The 'x' falls back to '?' at x=5, and is clipped at x=7.
+	if c := img.Get(5, 0); c != pixel.NewRGB888(255, 255, 255) {
+		t.Errorf("expected fallback glyph pixel, got %v", c)
+	}
+	// The last row is below the baseline and must be left alone.
+	for x := 0; x < 8; x++ {
+		if c := img.Get(x, 2); c != pixel.NewRGB888(0, 0, 0) {
+			t.Errorf("expected untouched pixel at %d, 2, got %v", x, c)
+		}
+	}
+}
+
+func TestBlendMonochrome(t *testing.T) {
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	if c := font.Blend(pixel.Monochrome(false), white, 200); c != true {
+		t.Errorf("expected mostly opaque pixel to be set")
+	}
+	if c := font.Blend(pixel.Monochrome(false), white, 50); c != false {
+		t.Errorf("expected mostly transparent pixel to be left alone")
+	}
+}
@@ -18,8 +18,15 @@ type Device struct {
 	buf     []byte
 	Address uint8
 	intPin  machine.Pin
+
+	// Touches of the previous ReadTouchPoints call.
+	touches    [maxTouches]touch.TrackedPoint
+	numTouches int
 }
 
+// maxTouches is the number of touches the controller can track.
+const maxTouches = 2
+
 // New returns FT6336 device for the provided I2C bus using default address.
 func New(i2c drivers.I2C, intPin machine.Pin) *Device {
 	return &Device{
@@ -59,6 +66,13 @@ func (d *Device) SetPeriodActive(v uint8) {
 	d.write1Byte(RegPeriodActive, v)
 }
 
+// GetGestureID gets the gesture detected by the controller. Most firmware
+// versions don't detect any gestures and always return 0, use the gesture
+// package instead.
+func (d *Device) GetGestureID() uint8 {
+	return d.read8bit(RegGestID)
+}
+
 // GetPeriodActive gets report rate in Active mode.
 func (d *Device) GetPeriodActive() uint8 {
 	return d.read8bit(RegPeriodActive)
@@ -85,10 +99,71 @@ func (d *Device) ReadTouchPoint() touch.Point {
 		z = 0
 	}
 
-	//Scale X&Y to 16 bit for consistency across touch drivers
+	return d.point(d.buf[1:], z)
+}
+
+// ReadTouchPoints reads up to two touches from the device, implementing
+// touch.MultiPointer. X and Y are scaled like ReadTouchPoint.
+//
+// The controller reports event flags of its own, but these are unreliable when
+// polling, so the events are derived from the touch IDs of the previous call
+// instead. Lifted touches are reported first.
+func (d *Device) ReadTouchPoints(points []touch.TrackedPoint) int {
+	d.Read()
+	count := int(d.buf[0] & 0x0F)
+	if count > maxTouches {
+		// Not a valid number of touches, as read right after power on.
+		count = 0
+	}
+
+	var current [maxTouches]touch.TrackedPoint
+	for i := 0; i < count; i++ {
+		data := d.buf[1+i*6:]
+		current[i] = touch.TrackedPoint{
+			Point: d.point(data, 0xFFFFF),
+			ID:    data[2] >> 4,
+			Event: touch.EventDown,
+		}
+		for _, previous := range d.touches[:d.numTouches] {
+			if previous.ID == current[i].ID {
+				current[i].Event = touch.EventMove
+			}
+		}
+	}
+
+	n := 0
+	for _, previous := range d.touches[:d.numTouches] {
+		lifted := true
+		for _, p := range current[:count] {
+			if p.ID == previous.ID {
+				lifted = false
+			}
+		}
+		if lifted && n < len(points) {
+			previous.Event = touch.EventUp
+			previous.Z = 0
+			points[n] = previous
+			n++
+		}
+	}
+	for _, p := range current[:count] {
+		if n < len(points) {
+			points[n] = p
+			n++
+		}
+	}
+
+	d.touches = current
+	d.numTouches = count
+	return n
+}
+
+// point returns the touch point stored in the 4 position registers in data,
+// scaled to 16 bit for consistency across touch drivers.
+func (d *Device) point(data []byte, z int) touch.Point {
 	return touch.Point{
-		X: (int(d.buf[1]&0x0F)<<8 + int(d.buf[2])) * ((1 << 16) / 320),
-		Y: (int(d.buf[3]&0x0F)<<8 + int(d.buf[4])) * ((1 << 16) / 270),
+		X: (int(data[0]&0x0F)<<8 + int(data[1])) * ((1 << 16) / 320),
+		Y: (int(data[2]&0x0F)<<8 + int(data[3])) * ((1 << 16) / 270),
 		Z: z,
 	}
 }
@@ -43,6 +43,7 @@ package ft6336
 const (
 	Address = 0x38
 
+	RegGestID       = 0x01
 	RegPeriodActive = 0x88
 	RegGMode        = 0xA4
 	RegFirmid       = 0xA6
@@ -11,6 +11,7 @@ import (
 	"errors"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/pixel"
 )
 
 // Rotation controls the rotation used by the display.
@@ -36,13 +37,19 @@ type Device struct {
 	isBGR           bool
 	vSyncLines      int16
 	orientation     Orientation
+	rotation        drivers.Rotation
 	batchLength     int16
 	batchData       []uint8
+	busy            bool // an asynchronous transfer is in progress
 }
 
 // Config is the configuration for the display
 type Config struct {
+	// Deprecated: use Rotation. HORIZONTAL is the same as Rotation0, and
+	// VERTICAL the same as Rotation270. Orientation is only used when
+	// Rotation is Rotation0.
 	Orientation  Orientation
+	Rotation     drivers.Rotation
 	RowOffset    int16
 	ColumnOffset int16
 	FrameRate    FrameRate
@@ -97,7 +104,7 @@ func (d *Device) SetDeviceOrientation() {
 
 // setWindow prepares the screen to be modified at a given rectangle
 func (d *Device) setWindow(x, y, w, h int16) {
-	if d.orientation == HORIZONTAL {
+	if d.rotation%2 == 0 {
 		x += d.columnOffset
 		y += d.rowOffset
 	} else {
@@ -113,7 +120,8 @@ func (d *Device) setWindow(x, y, w, h int16) {
 
 // FillScreen fills the screen with a given color
 func (d *Device) FillScreen(c color.RGBA) {
-	d.FillRectangle(0, 0, d.height, d.width, c)
+	w, h := d.Size()
+	d.FillRectangle(0, 0, w, h, c)
 }
 
 // FillRectangle fills a rectangle at a given coordinates with a color
@@ -151,6 +159,61 @@ func (d *Device) Display() error {
 	return nil
 }
 
+// DrawRGBBitmap8 copies an RGB bitmap to the internal buffer at given coordinates
+func (d *Device) DrawRGBBitmap8(x, y int16, data []uint8, w, h int16) error {
+	k, i := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= k || (x+w) > k || y >= i || (y+h) > i {
+		return errors.New("rectangle coordinates outside display area")
+	}
+	d.setWindow(x, y, w, h)
+	d.Tx(data, false)
+	return nil
+}
+
+// DrawBitmap copies the bitmap to the internal buffer on the screen at the
+// given coordinates. It returns once the image data has been sent completely.
+func (d *Device) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB565BE]) error {
+	width, height := bitmap.Size()
+	return d.DrawRGBBitmap8(x, y, bitmap.RawBuffer(), int16(width), int16(height))
+}
+
+// StartDrawBitmap starts sending the bitmap to the screen at the given
+// coordinates. If the SPI bus supports asynchronous transfers (see
+// drivers.SPIAsync) it returns before the transfer has finished, and the bitmap
+// must not be modified until Wait returns. Otherwise it behaves like
+// DrawBitmap.
+func (d *Device) StartDrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB565BE]) error {
+	width, height := bitmap.Size()
+	w, h := int16(width), int16(height)
+	k, i := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= k || (x+w) > k || y >= i || (y+h) > i {
+		return errors.New("rectangle coordinates outside display area")
+	}
+	d.setWindow(x, y, w, h)
+	if bus, ok := d.bus.(drivers.SPIAsync); ok {
+		d.dcPin.High()
+		if err := bus.StartTx(bitmap.RawBuffer(), nil); err != nil {
+			return err
+		}
+		d.busy = true
+		return nil
+	}
+	d.Tx(bitmap.RawBuffer(), false)
+	return nil
+}
+
+// Wait blocks until the transfer started by StartDrawBitmap has finished. It
+// returns immediately if no transfer is in progress.
+func (d *Device) Wait() error {
+	if !d.busy {
+		return nil
+	}
+	d.busy = false
+	return d.bus.(drivers.SPIAsync).Wait()
+}
+
 // FillRectangleWithBuffer fills buffer with a rectangle at a given coordinates.
 func (d *Device) FillRectangleWithBuffer(x, y, width, height int16, buffer []color.RGBA) error {
 	h, w := d.Size()
@@ -226,12 +289,14 @@ func (d *Device) Data(data uint8) {
 
 // Tx sends data to the display
 func (d *Device) Tx(data []byte, isCommand bool) {
+	d.Wait()
 	d.dcPin.Set(!isCommand)
 	d.bus.Tx(data, nil)
 }
 
 // Rx reads data from the display
 func (d *Device) Rx(command uint8, data []byte) {
+	d.Wait()
 	d.dcPin.Low()
 	d.csPin.Low()
 	d.bus.Transfer(command)
@@ -242,9 +307,43 @@ func (d *Device) Rx(command uint8, data []byte) {
 	d.csPin.High()
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.height, d.width
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the device (clock-wise). Rotation0 is
+// the orientation of the HORIZONTAL setting, and Rotation270 the orientation of
+// the VERTICAL setting.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	var madctl uint8
+	switch d.rotation % 4 {
+	case drivers.Rotation0:
+		madctl = MADCTL_MX | MADCTL_MY
+	case drivers.Rotation90:
+		madctl = MADCTL_MY | MADCTL_MV
+	case drivers.Rotation180:
+		madctl = 0
+	case drivers.Rotation270:
+		madctl = MADCTL_MX | MADCTL_MV
+	}
+	if d.rotation.IsMirrored() {
+		// Mirror the x axis of the rotated display.
+		if d.rotation%2 == 1 {
+			madctl ^= MADCTL_MY
+		} else {
+			madctl ^= MADCTL_MX
+		}
+	}
+	d.Command(MADCTR)
+	d.Data(madctl | MADCTL_BGR)
+	return nil
 }
 
 // EnableBacklight enables or disables the backlight
@@ -256,6 +355,22 @@ func (d *Device) EnableBacklight(enable bool) {
 	}
 }
 
+// Set the sleep mode for this LCD panel. When sleeping, the panel uses a lot
+// less power. The LCD won't display an image anymore, but the memory contents
+// will be kept.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.Command(DISPOFF)
+		d.Command(SLPIN)
+		time.Sleep(5 * time.Millisecond) // 5ms required by the datasheet
+	} else {
+		d.Command(SLPOUT)
+		time.Sleep(120 * time.Millisecond) // the panel must be awake before DISPON
+		d.Command(DISPON)
+	}
+	return nil
+}
+
 // InvertColors inverts the colors of the screen
 func (d *Device) InvertColors(invert bool) {
 	if invert {
@@ -270,6 +385,34 @@ func (d *Device) IsBGR(bgr bool) {
 	d.isBGR = bgr
 }
 
+// EnableTEOutput enables the TE ("tearing effect") line.
+// The TE line goes high when the screen is not currently being updated and can
+// be used to start drawing. When used correctly, it can avoid tearing entirely.
+func (d *Device) EnableTEOutput(on bool) {
+	if on {
+		d.Command(TEON)
+		d.Data(0x00) // M=0 (V-blanking only, no H-blanking)
+	} else {
+		d.Command(TEOFF)
+	}
+}
+
+// SetTEScanLine sets the scanline at which the TE line goes high, instead of
+// at the start of the vertical blanking period. The TE output must also be
+// enabled using EnableTEOutput.
+func (d *Device) SetTEScanLine(line uint16) {
+	d.Command(STTRSCL)
+	d.Tx([]uint8{uint8(line >> 8), uint8(line)}, false)
+}
+
+// GetScanLine reads the scanline that the display is currently refreshing.
+func (d *Device) GetScanLine() uint16 {
+	var data [3]uint8
+	d.Rx(GTSCL, data[:])
+	// The first byte is a dummy byte.
+	return uint16(data[1]&0x03)<<8 | uint16(data[2])
+}
+
 // SetScrollArea sets an area to scroll with fixed top and bottom parts of the display.
 func (d *Device) SetScrollArea(topFixedArea, bottomFixedArea int16) {
 	d.Command(VSCRDEF)
@@ -382,8 +525,11 @@ func (d *Device) Configure(cfg Config) {
 	d.Data(0x00)
 	d.Data(0x20)
 
-	d.Command(MADCTR)
-	d.Data(0x08) //Set as vertical screen
+	rotation := cfg.Rotation
+	if rotation == drivers.Rotation0 && cfg.Orientation == VERTICAL {
+		rotation = drivers.Rotation270
+	}
+	d.SetRotation(rotation)
 
 	d.Command(COLMOD)
 	d.Data(0x05)
@@ -8,6 +8,8 @@ import (
 	"io"
 	"machine"
 	"time"
+
+	"tinygo.org/x/drivers/textdisplay"
 )
 
 const (
@@ -231,6 +233,29 @@ func (d *Device) CreateCharacter(cgramAddr uint8, data []byte) {
 	}
 }
 
+// WriteAt shows the text at the given position directly on the display, without
+// using the internal buffer. It implements textdisplay.Device.
+func (d *Device) WriteAt(column, row int16, text []byte) {
+	d.SetCursor(uint8(column), uint8(row))
+	for _, c := range text {
+		d.sendData(c)
+		d.cursor.x++
+	}
+}
+
+// CreateGlyph defines a custom character for the character code 0-7. The data
+// contains 8 rows of 5 pixels. It implements textdisplay.Device.
+func (d *Device) CreateGlyph(code byte, data []byte) error {
+	if code > 7 {
+		return textdisplay.ErrGlyphCode
+	}
+	if len(data) != 8 {
+		return textdisplay.ErrGlyphSize
+	}
+	d.CreateCharacter(code<<3, data)
+	return nil
+}
+
 // busy returns true when hd447890 is busy
 // or after the timeout specified
 func (d *Device) busy(longDelay bool) bool {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/textdisplay"
 )
 
 // Device wraps an I2C connection to a HD44780 I2C LCD with related data.
@@ -159,6 +160,34 @@ func (d *Device) CreateCharacter(cgramAddr uint8, data []byte) {
 	d.SetCursor(d.cursor.x, d.cursor.y)
 }
 
+// Size returns the size of the display in characters.
+func (d *Device) Size() (columns, rows int16) {
+	return int16(d.width), int16(d.height)
+}
+
+// WriteAt shows the text at the given position, without interpreting control
+// characters. It implements textdisplay.Device.
+func (d *Device) WriteAt(column, row int16, text []byte) {
+	d.SetCursor(uint8(column), uint8(row))
+	for _, chr := range text {
+		d.sendData(chr)
+		d.cursor.x++
+	}
+}
+
+// CreateGlyph defines a custom character for the character code 0-7. The data
+// contains 8 rows of 5 pixels. It implements textdisplay.Device.
+func (d *Device) CreateGlyph(code byte, data []byte) error {
+	if code > 7 {
+		return textdisplay.ErrGlyphCode
+	}
+	if len(data) != 8 {
+		return textdisplay.ErrGlyphSize
+	}
+	d.CreateCharacter(code, data)
+	return nil
+}
+
 // DisplayOn turns on/off the display.
 func (d *Device) DisplayOn(option bool) {
 	if option {
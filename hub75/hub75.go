@@ -2,9 +2,19 @@
 //
 // Guide: https://cdn-learn.adafruit.com/downloads/pdf/32x16-32x32-rgb-led-matrix.pdf
 // This driver was inspired by https://github.com/2dom/PxMatrix
+//
+// Colors are shown using binary code modulation (BCM): each bit of the color
+// depth is stored in a separate bit plane, and every bit plane is shown twice
+// as long as the previous one. The display must be refreshed continuously by
+// calling Display (or Refresh) in a loop.
+//
+// Multiple panels can be chained, both horizontally and vertically. All panels
+// are connected in a single chain, which is mapped to the screen by a
+// configurable coordinate mapper.
 package hub75 // import "tinygo.org/x/drivers/hub75"
 
 import (
+	"errors"
 	"image/color"
 	"machine"
 	"time"
@@ -12,13 +22,61 @@ import (
 	"tinygo.org/x/drivers"
 )
 
+var errRowPattern = errors.New("hub75: row pattern needs an E pin")
+
+// Mapper converts screen coordinates to coordinates in the chain of panels. The
+// chain is as wide as all panels together and as high as a single panel. The
+// first panel in the chain is the one connected to the controller.
+type Mapper func(x, y int16) (chainX, chainY int16)
+
 type Config struct {
-	Width      int16
-	Height     int16
+	// Size of a single panel. The default is 64x32.
+	Width  int16
+	Height int16
+
+	// Number of bits per color channel, from 1 to 8. Every bit adds a bit
+	// plane, and doubles the time needed to show a frame. The default is 8.
 	ColorDepth uint16
+
+	// Number of rows that are multiplexed, selected using the A-E pins: 8, 16
+	// or 32 for 1/8, 1/16 and 1/32 scan panels. The default is 16. A row
+	// pattern of 32 needs an E pin (see NewWithE).
 	RowPattern int16
+
 	Brightness uint8
+
+	// Shift in the next bit plane while the current one is shown. This
+	// increases the refresh rate, but the least significant bit planes are
+	// shown for at least the time it takes to send a bit plane which reduces
+	// the color accuracy.
 	FastUpdate bool
+
+	// Number of chained panels horizontally and vertically. The default is a
+	// single panel.
+	ChainX int16
+	ChainY int16
+
+	// Every other row of panels runs in the opposite direction and is mounted
+	// upside down, which keeps the cables between panels short. Without it, all
+	// rows of panels run from left to right, starting at the top left.
+	Serpentine bool
+
+	// Custom coordinate mapper for other panel arrangements. When set,
+	// Serpentine is ignored.
+	Mapper Mapper
+
+	// Draw into a separate back buffer that is shown on the next call to
+	// Display or Swap. This avoids showing partially drawn frames, at the cost
+	// of twice the memory.
+	DoubleBuffer bool
+
+	// Time the least significant bit plane is shown at full brightness. The
+	// default is 1µs.
+	BitTime time.Duration
+
+	// Rotation of the whole screen, done in software before the coordinates
+	// are mapped to the chain.
+	Rotation drivers.Rotation
 }
 
 type Device struct {
@@ -27,37 +85,53 @@ type Device struct {
 	b                 machine.Pin
 	c                 machine.Pin
 	d                 machine.Pin
+	e                 machine.Pin
 	oe                machine.Pin
 	lat               machine.Pin
-	width             int16
-	height            int16
+	width             int16 // width of the screen
+	height            int16 // height of the screen
+	rotation          drivers.Rotation
+	panelWidth        int16 // width of a single panel
+	panelHeight       int16 // height of a single panel
+	chainX            int16
+	chainY            int16
+	chainWidth        int16 // width of the whole chain
+	serpentine        bool
+	mapper            Mapper
 	brightness        uint8
 	fastUpdate        bool
 	colorDepth        uint16
-	colorStep         uint16
-	colorHalfStep     uint16
-	colorThirdStep    uint16
-	colorTwoThirdStep uint16
+	bitTime           time.Duration
 	rowPattern        int16
 	rowsPerBuffer     int16
-	panelWidth        int16
+	panels            int16
 	panelWidthBytes   int16
-	pixelCounter      uint32
-	lineCounter       uint32
-	patternColorBytes uint8
-	rowSetsPerBuffer  uint8
+	patternColorBytes uint16
+	rowSetsPerBuffer  uint16
 	sendBufferSize    uint16
 	rowOffset         []uint32
-	buffer            [][]uint8 // [ColorDepth][(width * height * 3(rgb)) / 8]uint8
-	displayColor      uint16
+	front             [][]uint8 // [ColorDepth][(chainWidth * panelHeight * 3(rgb)) / 8]uint8
+	back              [][]uint8 // same as front, unless double buffered
+	doubleBuffer      bool
+	dirty             bool // back buffer was modified since the last swap
+	swapPending       bool // swap buffers at the start of the next frame
 }
 
 // New returns a new HUB75 driver. Pass in a fully configured SPI bus.
 func New(b drivers.SPI, latPin, oePin, aPin, bPin, cPin, dPin machine.Pin) Device {
+	return NewWithE(b, latPin, oePin, aPin, bPin, cPin, dPin, machine.NoPin)
+}
+
+// NewWithE returns a new HUB75 driver for panels that use the E pin, like 1/32
+// scan panels. Pass in a fully configured SPI bus.
+func NewWithE(b drivers.SPI, latPin, oePin, aPin, bPin, cPin, dPin, ePin machine.Pin) Device {
 	aPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	bPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	cPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	dPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	if ePin != machine.NoPin {
+		ePin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	}
 	oePin.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	latPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
 
@@ -67,25 +141,26 @@ func New(b drivers.SPI, latPin, oePin, aPin, bPin, cPin, dPin machine.Pin) Devic
 		b:   bPin,
 		c:   cPin,
 		d:   dPin,
+		e:   ePin,
 		oe:  oePin,
 		lat: latPin,
 	}
 }
 
 // Configure sets up the device.
-func (d *Device) Configure(cfg Config) {
+func (d *Device) Configure(cfg Config) error {
 	if cfg.Width != 0 {
-		d.width = cfg.Width
+		d.panelWidth = cfg.Width
 	} else {
-		d.width = 64
+		d.panelWidth = 64
 	}
 	if cfg.Height != 0 {
-		d.height = cfg.Height
+		d.panelHeight = cfg.Height
 	} else {
-		d.height = 32
+		d.panelHeight = 32
 	}
 	if cfg.ColorDepth != 0 {
-		d.colorDepth = cfg.ColorDepth
+		d.colorDepth = min(cfg.ColorDepth, 8)
 	} else {
 		d.colorDepth = 8
 	}
@@ -94,127 +169,223 @@ func (d *Device) Configure(cfg Config) {
 	} else {
 		d.rowPattern = 16
 	}
+	if d.rowPattern > 16 && d.e == machine.NoPin {
+		return errRowPattern
+	}
 	if cfg.Brightness != 0 {
 		d.brightness = cfg.Brightness
 	} else {
 		d.brightness = 255
 	}
-
-	d.fastUpdate = cfg.FastUpdate
-	d.rowsPerBuffer = d.height / 2
-	d.panelWidth = 1
-	d.panelWidthBytes = (d.width / d.panelWidth) / 8
-	d.rowOffset = make([]uint32, d.height)
-	d.patternColorBytes = uint8((d.height / d.rowPattern) * (d.width / 8))
-	d.rowSetsPerBuffer = uint8(d.rowsPerBuffer / d.rowPattern)
-	d.sendBufferSize = uint16(d.patternColorBytes) * 3
-	d.colorStep = 256 / d.colorDepth
-	d.colorHalfStep = d.colorStep / 2
-	d.colorThirdStep = d.colorStep / 3
-	d.colorTwoThirdStep = 2 * d.colorThirdStep
-	d.buffer = make([][]uint8, d.colorDepth)
-	for i := range d.buffer {
-		d.buffer[i] = make([]uint8, (d.width*d.height*3)/8)
+	if cfg.BitTime != 0 {
+		d.bitTime = cfg.BitTime
+	} else {
+		d.bitTime = time.Microsecond
 	}
+	d.chainX = max(cfg.ChainX, 1)
+	d.chainY = max(cfg.ChainY, 1)
+	d.serpentine = cfg.Serpentine
+	d.mapper = cfg.Mapper
+	d.fastUpdate = cfg.FastUpdate
+	d.rotation = cfg.Rotation % 8
+
+	d.width = d.panelWidth * d.chainX
+	d.height = d.panelHeight * d.chainY
+	d.panels = d.chainX * d.chainY
+	d.chainWidth = d.panelWidth * d.panels
+
+	d.rowsPerBuffer = d.panelHeight / 2
+	d.panelWidthBytes = d.panelWidth / 8
+	d.rowOffset = make([]uint32, d.panelHeight)
+	d.patternColorBytes = uint16(d.panelHeight/d.rowPattern) * uint16(d.chainWidth/8)
+	d.rowSetsPerBuffer = uint16(d.rowsPerBuffer / d.rowPattern)
+	d.sendBufferSize = d.patternColorBytes * 3
 
-	d.colorHalfStep = d.colorStep / 2
-	d.colorThirdStep = d.colorStep / 3
-	d.colorTwoThirdStep = 2 * d.colorThirdStep
+	bufferSize := int(d.chainWidth) * int(d.panelHeight) * 3 / 8
+	d.front = make([][]uint8, d.colorDepth)
+	for i := range d.front {
+		d.front[i] = make([]uint8, bufferSize)
+	}
+	d.back = d.front
+	d.doubleBuffer = cfg.DoubleBuffer
+	if d.doubleBuffer {
+		d.back = make([][]uint8, d.colorDepth)
+		for i := range d.back {
+			d.back[i] = make([]uint8, bufferSize)
+		}
+	}
 
 	d.a.Low()
 	d.b.Low()
 	d.c.Low()
 	d.d.Low()
+	if d.e != machine.NoPin {
+		d.e.Low()
+	}
 	d.oe.High()
 
-	var i uint32
-	for i = 0; i < uint32(d.height); i++ {
+	for i := uint32(0); i < uint32(d.panelHeight); i++ {
 		d.rowOffset[i] = (i%uint32(d.rowPattern))*uint32(d.sendBufferSize) + uint32(d.sendBufferSize) - 1
 	}
+	return nil
 }
 
 // SetPixel modifies the internal buffer in a single pixel.
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
+		return
+	}
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
+	x, y = d.chainXY(x, y)
 	d.fillMatrixBuffer(x, y, c.R, c.G, c.B)
 }
 
-// fillMatrixBuffer modifies a pixel in the internal buffer given position and RGB values
+// chainXY converts screen coordinates to coordinates in the chain.
+func (d *Device) chainXY(x, y int16) (int16, int16) {
+	if d.mapper != nil {
+		return d.mapper(x, y)
+	}
+	panelX, panelY := x/d.panelWidth, y/d.panelHeight
+	x, y = x%d.panelWidth, y%d.panelHeight
+	if d.serpentine && panelY%2 == 1 {
+		panelX = d.chainX - 1 - panelX
+		x = d.panelWidth - 1 - x
+		y = d.panelHeight - 1 - y
+	}
+	panel := panelY*d.chainX + panelX
+	return panel*d.panelWidth + x, y
+}
+
+// fillMatrixBuffer modifies a pixel in the back buffer given the position in
+// the chain and RGB values.
 func (d *Device) fillMatrixBuffer(x int16, y int16, r uint8, g uint8, b uint8) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	if x < 0 || x >= d.chainWidth || y < 0 || y >= d.panelHeight {
 		return
 	}
-	x = d.width - 1 - x
+	x = d.chainWidth - 1 - x
 
 	var offsetR uint32
 	var offsetG uint32
 	var offsetB uint32
 
-	vertIndexInBuffer := uint8((int32(y) % int32(d.rowsPerBuffer)) / int32(d.rowPattern))
-	whichBuffer := uint8(y / d.rowsPerBuffer)
+	vertIndexInBuffer := uint32((int32(y) % int32(d.rowsPerBuffer)) / int32(d.rowPattern))
+	whichBuffer := uint32(y / d.rowsPerBuffer)
 	xByte := x / 8
-	whichPanel := uint8(xByte / d.panelWidthBytes)
-	inRowByteOffset := uint8(xByte % d.panelWidthBytes)
+	whichPanel := uint32(xByte / d.panelWidthBytes)
+	inRowByteOffset := uint32(xByte % d.panelWidthBytes)
 
-	offsetR = d.rowOffset[y] - uint32(inRowByteOffset) - uint32(d.panelWidthBytes)*
-		(uint32(d.rowSetsPerBuffer)*(uint32(d.panelWidth)*uint32(whichBuffer)+uint32(whichPanel))+uint32(vertIndexInBuffer))
+	offsetR = d.rowOffset[y] - inRowByteOffset - uint32(d.panelWidthBytes)*
+		(uint32(d.rowSetsPerBuffer)*(uint32(d.panels)*whichBuffer+whichPanel)+vertIndexInBuffer)
 	offsetG = offsetR - uint32(d.patternColorBytes)
 	offsetB = offsetG - uint32(d.patternColorBytes)
 
 	bitSelect := uint8(x % 8)
 
-	for c := uint16(0); c < d.colorDepth; c++ {
-		colorTresh := uint8(c*d.colorStep + d.colorHalfStep)
-		if r > colorTresh {
-			d.buffer[c][offsetR] |= 1 << bitSelect
+	// Bit plane 0 holds the least significant bit that is shown.
+	shift := 8 - d.colorDepth
+	for plane := uint16(0); plane < d.colorDepth; plane++ {
+		buf := d.back[plane]
+		bit := plane + shift
+		if (r>>bit)&1 != 0 {
+			buf[offsetR] |= 1 << bitSelect
 		} else {
-			d.buffer[c][offsetR] &^= 1 << bitSelect
+			buf[offsetR] &^= 1 << bitSelect
 		}
-		if g > colorTresh {
-			d.buffer[(c+d.colorThirdStep)%d.colorDepth][offsetG] |= 1 << bitSelect
+		if (g>>bit)&1 != 0 {
+			buf[offsetG] |= 1 << bitSelect
 		} else {
-			d.buffer[(c+d.colorThirdStep)%d.colorDepth][offsetG] &^= 1 << bitSelect
+			buf[offsetG] &^= 1 << bitSelect
 		}
-		if b > colorTresh {
-			d.buffer[(c+d.colorTwoThirdStep)%d.colorDepth][offsetB] |= 1 << bitSelect
+		if (b>>bit)&1 != 0 {
+			buf[offsetB] |= 1 << bitSelect
 		} else {
-			d.buffer[(c+d.colorTwoThirdStep)%d.colorDepth][offsetB] &^= 1 << bitSelect
+			buf[offsetB] &^= 1 << bitSelect
 		}
 	}
+	d.dirty = true
 }
 
-// Display sends the buffer (if any) to the screen.
+// Display shows the frame that was drawn since the last call (see Swap), and
+// refreshes the display once (see Refresh). It must be called continuously to
+// keep showing an image.
 func (d *Device) Display() error {
-	rp := uint16(d.rowPattern)
-	for i := uint16(0); i < rp; i++ {
-		// FAST UPDATES (only if brightness = 255)
-		if d.fastUpdate && d.brightness == 255 {
-			d.setMux((i + rp - 1) % rp)
-			d.lat.High()
-			d.oe.Low()
-			d.lat.Low()
-			time.Sleep(1 * time.Microsecond)
-			d.bus.Tx(d.buffer[d.displayColor][i*d.sendBufferSize:(i+1)*d.sendBufferSize], nil)
-			time.Sleep(10 * time.Microsecond)
-			d.oe.High()
-
-		} else { // NO FAST UPDATES
-			d.setMux(i)
-			d.bus.Tx(d.buffer[d.displayColor][i*d.sendBufferSize:(i+1)*d.sendBufferSize], nil)
-			d.latch((255 * uint16(d.brightness)) / 255)
+	d.Swap()
+	d.Refresh()
+	return nil
+}
+
+// Swap shows the back buffer, starting with the next frame that is refreshed.
+// The contents of the back buffer are kept, so that drawing can continue where
+// it left off. Swap doesn't do anything when double buffering is disabled.
+//
+// Swap can be used to show a new frame while the display is refreshed from a
+// separate goroutine using Refresh.
+func (d *Device) Swap() {
+	if d.dirty && d.doubleBuffer {
+		d.swapPending = true
+	}
+	d.dirty = false
+}
+
+// Refresh shows a single frame: all rows with all bit planes. Before the frame
+// starts, the back buffer is swapped in if requested by Swap.
+func (d *Device) Refresh() {
+	if d.swapPending {
+		d.front, d.back = d.back, d.front
+		for i := range d.back {
+			copy(d.back[i], d.front[i])
 		}
+		d.swapPending = false
 	}
-	d.displayColor++
-	if d.displayColor >= d.colorDepth {
-		d.displayColor = 0
+
+	rows := uint16(d.rowPattern)
+	steps := rows * d.colorDepth
+	if !d.fastUpdate {
+		for step := uint16(0); step < steps; step++ {
+			row, plane := step/d.colorDepth, step%d.colorDepth
+			d.bus.Tx(d.rowData(row, plane), nil)
+			d.setMux(row)
+			d.latch(d.showTime(plane))
+		}
+		return
 	}
-	return nil
+
+	// Send the next bit plane while the current one is shown.
+	d.bus.Tx(d.rowData(0, 0), nil)
+	for step := uint16(0); step < steps; step++ {
+		row, plane := step/d.colorDepth, step%d.colorDepth
+		d.oe.High()
+		d.setMux(row)
+		d.lat.High()
+		d.lat.Low()
+		d.oe.Low()
+		start := time.Now()
+		if next := step + 1; next < steps {
+			d.bus.Tx(d.rowData(next/d.colorDepth, next%d.colorDepth), nil)
+		}
+		if wait := d.showTime(plane) - time.Since(start); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	d.oe.High()
+}
+
+// rowData returns the data to shift out for a row and bit plane.
+func (d *Device) rowData(row, plane uint16) []uint8 {
+	return d.front[plane][row*d.sendBufferSize : (row+1)*d.sendBufferSize]
+}
+
+// showTime returns how long a bit plane is shown at the current brightness.
+func (d *Device) showTime(plane uint16) time.Duration {
+	return (d.bitTime << plane) * time.Duration(d.brightness) / 255
 }
 
-func (d *Device) latch(showTime uint16) {
+func (d *Device) latch(showTime time.Duration) {
 	d.lat.High()
 	d.lat.Low()
 	d.oe.Low()
-	time.Sleep(time.Duration(showTime) * time.Microsecond)
+	time.Sleep(showTime)
 	d.oe.High()
 }
 
@@ -239,6 +410,14 @@ func (d *Device) setMux(value uint16) {
 	} else {
 		d.d.Low()
 	}
+	if d.e == machine.NoPin {
+		return
+	}
+	if (value & 0x10) == 0x10 {
+		d.e.High()
+	} else {
+		d.e.Low()
+	}
 }
 
 // FlushDisplay flushes the display
@@ -256,15 +435,28 @@ func (d *Device) SetBrightness(brightness uint8) {
 
 // ClearDisplay erases the internal buffer
 func (d *Device) ClearDisplay() {
-	bufferSize := (d.width * d.height * 3) / 8
-	for c := uint16(0); c < d.colorDepth; c++ {
-		for j := int16(0); j < bufferSize; j++ {
-			d.buffer[c][j] = 0
+	for _, plane := range d.back {
+		for i := range plane {
+			plane[i] = 0
 		}
 	}
+	d.dirty = true
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the currently configured rotation.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the screen (clock-wise). The panel keeps
+// being refreshed from the same buffers, so the frame that is shown doesn't
+// turn until it is drawn again.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
@@ -0,0 +1,168 @@
+// Package idle dims and then sleeps a display after a period of inactivity, and
+// wakes it up again on input.
+//
+// A Manager doesn't read any input itself: call Activity from the input
+// handling code (for example on every touch or button press), and call Update
+// regularly (for example from the main loop) to dim or sleep the display once
+// the configured time has passed.
+package idle // import "tinygo.org/x/drivers/idle"
+
+import (
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// Backlight is a backlight with adjustable brightness, such as
+// backlight.Device.
+type Backlight interface {
+	// SetBrightness sets the brightness, from 0 (off) to 255 (fully on).
+	SetBrightness(brightness uint8)
+}
+
+// Config is the configuration of a Manager.
+type Config struct {
+	// Brightness of the backlight while active. The default is 255.
+	Brightness uint8
+
+	// Brightness of the backlight while dimmed. The default is a quarter of
+	// Brightness.
+	DimBrightness uint8
+
+	// Time without activity after which the backlight is dimmed. Zero
+	// disables dimming.
+	DimAfter time.Duration
+
+	// Time without activity after which the backlight is turned off and the
+	// display is put to sleep. Zero disables sleeping.
+	SleepAfter time.Duration
+}
+
+// State is the power state of the display.
+type State uint8
+
+const (
+	Active State = iota
+	Dimmed
+	Asleep
+)
+
+// String returns a human-readable name of the state.
+func (s State) String() string {
+	switch s {
+	case Active:
+		return "active"
+	case Dimmed:
+		return "dimmed"
+	case Asleep:
+		return "asleep"
+	}
+	return "unknown"
+}
+
+// Manager dims and sleeps a display after a period of inactivity.
+type Manager struct {
+	display      drivers.Sleeper
+	backlight    Backlight
+	config       Config
+	state        State
+	lastActivity time.Time
+	now          func() time.Time // replaced in tests
+}
+
+// New returns a new Manager for the given display and backlight, either of
+// which may be nil. The display is assumed to be awake, and the backlight is
+// set to the active brightness.
+func New(display drivers.Sleeper, backlight Backlight, config Config) *Manager {
+	if config.Brightness == 0 {
+		config.Brightness = 255
+	}
+	if config.DimBrightness == 0 {
+		config.DimBrightness = config.Brightness / 4
+	}
+	m := &Manager{
+		display:   display,
+		backlight: backlight,
+		config:    config,
+		now:       time.Now,
+	}
+	m.lastActivity = m.now()
+	m.setBrightness(config.Brightness)
+	return m
+}
+
+// State returns the current power state of the display.
+func (m *Manager) State() State {
+	return m.state
+}
+
+// SetBrightness changes the brightness of the backlight while active. The
+// brightness while dimmed is not changed.
+func (m *Manager) SetBrightness(brightness uint8) {
+	m.config.Brightness = brightness
+	if m.state == Active {
+		m.setBrightness(brightness)
+	}
+}
+
+// Activity records user input, which restarts the inactivity timers and wakes
+// up the display if needed. It returns whether the display was asleep, in
+// which case the input that woke it up should usually be ignored: the user
+// couldn't see what they were touching.
+func (m *Manager) Activity() (wasAsleep bool, err error) {
+	wasAsleep = m.state == Asleep
+	return wasAsleep, m.Wake()
+}
+
+// Wake wakes up the display, restores the brightness and restarts the
+// inactivity timers.
+func (m *Manager) Wake() error {
+	m.lastActivity = m.now()
+	if m.state == Asleep && m.display != nil {
+		if err := m.display.Sleep(false); err != nil {
+			return err
+		}
+	}
+	if m.state != Active {
+		m.state = Active
+		m.setBrightness(m.config.Brightness)
+	}
+	return nil
+}
+
+// Sleep turns off the backlight and puts the display to sleep right away. The
+// next activity wakes it up again.
+func (m *Manager) Sleep() error {
+	if m.state == Asleep {
+		return nil
+	}
+	m.setBrightness(0)
+	if m.display != nil {
+		if err := m.display.Sleep(true); err != nil {
+			return err
+		}
+	}
+	m.state = Asleep
+	return nil
+}
+
+// Update dims or sleeps the display when there has been no activity for the
+// configured time. It should be called regularly.
+func (m *Manager) Update() error {
+	idle := m.now().Sub(m.lastActivity)
+	if m.config.SleepAfter > 0 && idle >= m.config.SleepAfter {
+		return m.Sleep()
+	}
+	if m.config.DimAfter > 0 && idle >= m.config.DimAfter && m.state == Active {
+		m.state = Dimmed
+		m.setBrightness(m.config.DimBrightness)
+	}
+	return nil
+}
+
+// setBrightness sets the brightness of the backlight, if there is one.
+func (m *Manager) setBrightness(brightness uint8) {
+	if m.backlight != nil {
+		m.backlight.SetBrightness(brightness)
+	}
+}
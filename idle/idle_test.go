@@ -0,0 +1,134 @@
+package idle
+
+import (
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/internal/fakeclock"
+)
+
+type fakeDisplay struct {
+	sleeping bool
+	calls    int
+}
+
+func (d *fakeDisplay) Sleep(sleepEnabled bool) error {
+	d.sleeping = sleepEnabled
+	d.calls++
+	return nil
+}
+
+type fakeBacklight struct {
+	brightness uint8
+}
+
+func (b *fakeBacklight) SetBrightness(brightness uint8) {
+	b.brightness = brightness
+}
+
+// newManager returns a manager that uses the returned fake clock.
+func newManager(display *fakeDisplay, backlight Backlight, config Config) (*Manager, *fakeclock.Clock) {
+	clock := fakeclock.New()
+	m := New(display, backlight, config)
+	m.now = clock.Now
+	m.lastActivity = clock.Now()
+	return m, clock
+}
+
+func TestDimAndSleep(t *testing.T) {
+	display := &fakeDisplay{}
+	backlight := &fakeBacklight{}
+	m, clock := newManager(display, backlight, Config{
+		Brightness: 200,
+		DimAfter:   10 * time.Second,
+		SleepAfter: 30 * time.Second,
+	})
+	if backlight.brightness != 200 {
+		t.Errorf("expected brightness 200, got %d", backlight.brightness)
+	}
+
+	steps := []struct {
+		advance    time.Duration
+		state      State
+		brightness uint8
+		sleeping   bool
+	}{
+		{5 * time.Second, Active, 200, false},
+		{5 * time.Second, Dimmed, 50, false},
+		{19 * time.Second, Dimmed, 50, false},
+		{1 * time.Second, Asleep, 0, true},
+		{time.Minute, Asleep, 0, true},
+	}
+	for i, step := range steps {
+		clock.Advance(step.advance)
+		if err := m.Update(); err != nil {
+			t.Fatalf("step %d: Update failed: %v", i, err)
+		}
+		if m.State() != step.state || backlight.brightness != step.brightness || display.sleeping != step.sleeping {
+			t.Errorf("step %d: unexpected state %s, brightness %d, sleeping %v", i, m.State(), backlight.brightness, display.sleeping)
+		}
+	}
+	if display.calls != 1 {
+		t.Errorf("expected display to be put to sleep once, got %d calls", display.calls)
+	}
+}
+
+func TestActivity(t *testing.T) {
+	display := &fakeDisplay{}
+	backlight := &fakeBacklight{}
+	m, clock := newManager(display, backlight, Config{
+		DimAfter:   10 * time.Second,
+		SleepAfter: 30 * time.Second,
+	})
+
+	// Activity while dimmed restores the brightness without waking anything.
+	clock.Advance(15 * time.Second)
+	m.Update()
+	wasAsleep, err := m.Activity()
+	if err != nil || wasAsleep {
+		t.Errorf("unexpected result of Activity while dimmed: %v, %v", wasAsleep, err)
+	}
+	if m.State() != Active || backlight.brightness != 255 {
+		t.Errorf("expected active state, got %s with brightness %d", m.State(), backlight.brightness)
+	}
+
+	// The timers restart on activity.
+	clock.Advance(15 * time.Second)
+	m.Update()
+	if m.State() != Dimmed {
+		t.Errorf("expected dimmed state, got %s", m.State())
+	}
+
+	// Activity while asleep wakes up the display.
+	clock.Advance(20 * time.Second)
+	m.Update()
+	wasAsleep, err = m.Activity()
+	if err != nil || !wasAsleep {
+		t.Errorf("unexpected result of Activity while asleep: %v, %v", wasAsleep, err)
+	}
+	if m.State() != Active || backlight.brightness != 255 || display.sleeping {
+		t.Errorf("expected display to be awake, got %s with brightness %d", m.State(), backlight.brightness)
+	}
+	m.Update()
+	if m.State() != Active {
+		t.Errorf("expected active state after waking up, got %s", m.State())
+	}
+}
+
+func TestSleepWithoutTimers(t *testing.T) {
+	display := &fakeDisplay{}
+	m, clock := newManager(display, nil, Config{})
+
+	clock.Advance(time.Hour)
+	m.Update()
+	if m.State() != Active {
+		t.Errorf("expected active state without timers, got %s", m.State())
+	}
+
+	if err := m.Sleep(); err != nil || !display.sleeping {
+		t.Errorf("expected display to sleep: %v", err)
+	}
+	if err := m.Wake(); err != nil || display.sleeping || m.State() != Active {
+		t.Errorf("expected display to wake up: %v", err)
+	}
+}
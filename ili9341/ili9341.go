@@ -26,6 +26,8 @@ type Device struct {
 	x0, x1 int16 // cached address window; prevents useless/expensive
 	y0, y1 int16 // syscalls to PASET and CASET
 
+	busy bool // an asynchronous transfer is in progress
+
 	dc  machine.Pin
 	cs  machine.Pin
 	rst machine.Pin
@@ -176,6 +178,34 @@ func (d *Device) EnableTEOutput(on bool) {
 	}
 }
 
+// SetTEScanLine sets the scanline at which the TE line goes high, instead of
+// at the start of the vertical blanking period. The TE output must also be
+// enabled using EnableTEOutput.
+func (d *Device) SetTEScanLine(line uint16) {
+	cmdBuf[0] = uint8(line >> 8)
+	cmdBuf[1] = uint8(line)
+	d.sendCommand(STE, cmdBuf[:2])
+}
+
+// GetScanLine reads the scanline that the display is currently refreshing. It
+// returns 0 if the display is connected through a bus that can't be read from,
+// such as the parallel bus.
+func (d *Device) GetScanLine() uint16 {
+	driver, ok := d.driver.(readDriver)
+	if !ok {
+		return 0
+	}
+	d.Wait()
+	d.startWrite()
+	d.dc.Low()
+	d.driver.write8(GSCAN)
+	d.dc.High()
+	// The first byte is a dummy byte.
+	driver.read8sl(cmdBuf[:3])
+	d.endWrite()
+	return uint16(cmdBuf[1]&0x03)<<8 | uint16(cmdBuf[2])
+}
+
 // DrawRGBBitmap copies an RGB bitmap to the internal buffer at given coordinates
 //
 // Deprecated: use DrawBitmap instead.
@@ -215,6 +245,50 @@ func (d *Device) DrawBitmap(x, y int16, bitmap Image) error {
 	return d.DrawRGBBitmap8(x, y, bitmap.RawBuffer(), int16(width), int16(height))
 }
 
+// StartDrawBitmap starts sending the bitmap to the screen at the given
+// coordinates. If the SPI bus supports asynchronous transfers (see
+// drivers.SPIAsync) it returns before the transfer has finished, and the bitmap
+// must not be modified until Wait returns. Otherwise it behaves like
+// DrawBitmap.
+func (d *Device) StartDrawBitmap(x, y int16, bitmap Image) error {
+	width, height := bitmap.Size()
+	w, h := int16(width), int16(height)
+	k, i := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= k || (x+w) > k || y >= i || (y+h) > i {
+		return errors.New("rectangle coordinates outside display area")
+	}
+	d.setWindow(x, y, w, h)
+	d.startWrite()
+	if driver, ok := d.driver.(asyncDriver); ok {
+		// Keep the chip select pin low until the transfer has finished.
+		started, err := driver.startWrite8sl(bitmap.RawBuffer())
+		if err != nil {
+			d.endWrite()
+			return err
+		}
+		if started {
+			d.busy = true
+			return nil
+		}
+	}
+	d.driver.write8sl(bitmap.RawBuffer())
+	d.endWrite()
+	return nil
+}
+
+// Wait blocks until the transfer started by StartDrawBitmap has finished. It
+// returns immediately if no transfer is in progress.
+func (d *Device) Wait() error {
+	if !d.busy {
+		return nil
+	}
+	d.busy = false
+	err := d.driver.(asyncDriver).wait()
+	d.endWrite()
+	return err
+}
+
 // FillRectangle fills a rectangle at given coordinates with a color
 func (d *Device) FillRectangle(x, y, width, height int16, c color.RGBA) error {
 	k, i := d.Size()
@@ -265,7 +339,7 @@ func (d *Device) DrawFastHLine(x0, x1, y int16, c color.RGBA) error {
 
 // FillScreen fills the screen with a given color
 func (d *Device) FillScreen(c color.RGBA) {
-	if d.rotation == Rotation0 || d.rotation == Rotation180 {
+	if d.rotation%2 == 0 {
 		d.FillRectangle(0, 0, d.width, d.height, c)
 	} else {
 		d.FillRectangle(0, 0, d.height, d.width, c)
@@ -324,11 +398,11 @@ func (d *Device) SetRotation(rotation drivers.Rotation) error {
 	case Rotation180Mirror:
 		madctl = MADCTL_MX | MADCTL_MY | MADCTL_BGR | MADCTL_ML
 	case Rotation270Mirror:
-		madctl = MADCTL_MX | MADCTL_MY | MADCTL_MV | MADCTL_BGR | MADCTL_ML
+		madctl = MADCTL_MX | MADCTL_MV | MADCTL_BGR | MADCTL_ML
 	}
 	cmdBuf[0] = madctl
 	d.sendCommand(MADCTL, cmdBuf[:1])
-	d.rotation = rotation
+	d.rotation = rotation % 8
 	return nil
 }
 
@@ -402,6 +476,7 @@ func (d *Device) endWrite() {
 }
 
 func (d *Device) sendCommand(cmd byte, data []byte) {
+	d.Wait()
 	d.startWrite()
 	d.dc.Low()
 	d.driver.write8(cmd)
@@ -422,6 +497,21 @@ type driver interface {
 	write16sl(data []uint16)
 }
 
+// asyncDriver is implemented by drivers that may be able to send data in the
+// background, for example using DMA.
+type asyncDriver interface {
+	// startWrite8sl starts sending b and reports whether the transfer was
+	// started. If it wasn't, b must be sent using write8sl instead.
+	startWrite8sl(b []byte) (bool, error)
+	wait() error
+}
+
+// readDriver is implemented by drivers that can read data back from the
+// display.
+type readDriver interface {
+	read8sl(b []byte)
+}
+
 func delay(m int) {
 	t := time.Now().UnixNano() + int64(time.Duration(m*1000)*time.Microsecond)
 	for time.Now().UnixNano() < t {
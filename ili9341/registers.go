@@ -46,6 +46,8 @@ const (
 	MADCTL   = 0x36 ///< Memory Access Control
 	VSCRSADD = 0x37 ///< Vertical Scrolling Start Address
 	PIXFMT   = 0x3A ///< COLMOD: Pixel Format Set
+	STE      = 0x44 ///< Set Tear Scanline
+	GSCAN    = 0x45 ///< Get Scanline
 
 	FRMCTR1 = 0xB1 ///< Frame Rate Control (In Normal Mode/Full Colors)
 	FRMCTR2 = 0xB2 ///< Frame Rate Control (In Idle Mode/8 colors)
@@ -71,3 +71,21 @@ func (pd *spiDriver) write16sl(data []uint16) {
 		pd.bus.Tx(buf[:2], nil)
 	}
 }
+
+func (pd *spiDriver) read8sl(b []byte) {
+	for i := range b {
+		b[i], _ = pd.bus.Transfer(0xFF)
+	}
+}
+
+func (pd *spiDriver) startWrite8sl(b []byte) (bool, error) {
+	bus, ok := pd.bus.(drivers.SPIAsync)
+	if !ok {
+		return false, nil
+	}
+	return true, bus.StartTx(b, nil)
+}
+
+func (pd *spiDriver) wait() error {
+	return pd.bus.(drivers.SPIAsync).Wait()
+}
@@ -0,0 +1,257 @@
+// Package input turns the state of buttons, keypads and touch keys into
+// debounced input events.
+//
+// A Manager reads one or more sources, such as GPIO pins, a keypad or a touch
+// key controller, and numbers their keys consecutively in the order the sources
+// were added. Call Update regularly, for example every 5 to 10 milliseconds,
+// and the manager reports press, release, long press, repeat and chord events
+// to a handler function, or through a channel with Chan.
+package input // import "tinygo.org/x/drivers/input"
+
+import (
+	"errors"
+	"time"
+)
+
+var errTooManyKeys = errors.New("input: too many keys, at most 64 are supported")
+
+// MaxKeys is the maximum number of keys of a Manager.
+const MaxKeys = 64
+
+// EventType is the kind of an input event.
+type EventType uint8
+
+const (
+	// Press is sent when a key is pressed.
+	Press EventType = iota + 1
+
+	// Release is sent when a key is released.
+	Release
+
+	// LongPress is sent once when a key is held for Config.LongPress.
+	LongPress
+
+	// Repeat is sent repeatedly while a key is held, starting after
+	// Config.RepeatDelay.
+	Repeat
+
+	// Chord is sent when a key is pressed while other keys are held, after
+	// the Press event of that key.
+	Chord
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case Press:
+		return "press"
+	case Release:
+		return "release"
+	case LongPress:
+		return "long press"
+	case Repeat:
+		return "repeat"
+	case Chord:
+		return "chord"
+	}
+	return "unknown"
+}
+
+// Event is an input event.
+type Event struct {
+	Type EventType
+
+	// Key is the number of the key that changed.
+	Key int
+
+	// Keys is the set of keys that are pressed after the event, with a bit
+	// set for every pressed key. Use it to check which keys form a chord.
+	Keys uint64
+
+	// Duration is how long the key was held, for Release, LongPress and
+	// Repeat events.
+	Duration time.Duration
+}
+
+// Config is the configuration of a Manager.
+type Config struct {
+	// Time that a key must be stable before a change is accepted. The default
+	// is 20ms.
+	Debounce time.Duration
+
+	// Time that a key must be held for a LongPress event. The default is
+	// 500ms, use a negative value to disable long presses.
+	LongPress time.Duration
+
+	// Time that a key must be held before it repeats, and the time between
+	// repeats. Repeats are disabled by default, the default interval is
+	// 100ms.
+	RepeatDelay    time.Duration
+	RepeatInterval time.Duration
+}
+
+// keyState is the debounce and timing state of a single key.
+type keyState struct {
+	changed    time.Time // when the raw state last changed
+	pressed    time.Time // when the key was pressed
+	nextRepeat time.Time
+	longSent   bool
+}
+
+// source is a source with its first key number.
+type source struct {
+	source Source
+	first  int
+	keys   int
+}
+
+// Manager reads input sources and reports events.
+type Manager struct {
+	config  Config
+	handler func(Event)
+	now     func() time.Time
+	sources []source
+	keys    []keyState
+	raw     uint64 // last raw state
+	stable  uint64 // debounced state
+}
+
+// New returns a manager that sends events to handler. The handler is called
+// from Update.
+func New(config Config, handler func(Event)) *Manager {
+	if config.Debounce == 0 {
+		config.Debounce = 20 * time.Millisecond
+	}
+	if config.LongPress == 0 {
+		config.LongPress = 500 * time.Millisecond
+	}
+	if config.RepeatInterval == 0 {
+		config.RepeatInterval = 100 * time.Millisecond
+	}
+	return &Manager{
+		config:  config,
+		handler: handler,
+		now:     time.Now,
+	}
+}
+
+// Chan returns a handler that sends events to ch. Events are dropped when the
+// channel is full, so that Update never blocks.
+func Chan(ch chan<- Event) func(Event) {
+	return func(e Event) {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Add adds a source with the given number of keys, and returns the number of
+// its first key.
+func (m *Manager) Add(src Source, keys int) (int, error) {
+	first := len(m.keys)
+	if keys < 0 || first+keys > MaxKeys || keys > 32 {
+		return 0, errTooManyKeys
+	}
+	m.sources = append(m.sources, source{source: src, first: first, keys: keys})
+	m.keys = append(m.keys, make([]keyState, keys)...)
+	return first, nil
+}
+
+// Pressed returns whether a key is pressed, after debouncing.
+func (m *Manager) Pressed(key int) bool {
+	return key >= 0 && key < len(m.keys) && m.stable&(1<<key) != 0
+}
+
+// Keys returns the set of pressed keys, after debouncing.
+func (m *Manager) Keys() uint64 {
+	return m.stable
+}
+
+// Update reads all sources and sends the resulting events. When a source
+// fails, its keys keep their previous state and the first error is returned
+// after reading the other sources.
+func (m *Manager) Update() error {
+	now := m.now()
+
+	var firstErr error
+	raw := m.raw
+	for _, s := range m.sources {
+		state, err := s.source.ReadKeys()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		mask := uint64(1)<<s.keys - 1
+		raw = raw&^(mask<<s.first) | (uint64(state)&mask)<<s.first
+	}
+
+	for key := range m.keys {
+		bit := uint64(1) << key
+		k := &m.keys[key]
+		if (raw^m.raw)&bit != 0 {
+			k.changed = now
+		}
+		if (raw^m.stable)&bit != 0 && now.Sub(k.changed) >= m.config.Debounce {
+			m.stable ^= bit
+			m.change(key, now)
+			continue
+		}
+		if m.stable&bit != 0 {
+			m.held(key, now)
+		}
+	}
+	m.raw = raw
+	return firstErr
+}
+
+// Run calls Update every interval, forever. It is meant to be run in a
+// goroutine, with events sent to a channel.
+func (m *Manager) Run(interval time.Duration) {
+	for {
+		m.Update()
+		time.Sleep(interval)
+	}
+}
+
+// change sends the events for a key that was just pressed or released.
+func (m *Manager) change(key int, now time.Time) {
+	k := &m.keys[key]
+	if m.stable&(1<<key) == 0 {
+		m.send(Event{Type: Release, Key: key, Keys: m.stable, Duration: now.Sub(k.pressed)})
+		return
+	}
+	k.pressed = now
+	k.longSent = false
+	k.nextRepeat = now.Add(m.config.RepeatDelay)
+	m.send(Event{Type: Press, Key: key, Keys: m.stable})
+	if m.stable&^(1<<key) != 0 {
+		m.send(Event{Type: Chord, Key: key, Keys: m.stable})
+	}
+}
+
+// held sends the events for a key that is held.
+func (m *Manager) held(key int, now time.Time) {
+	k := &m.keys[key]
+	duration := now.Sub(k.pressed)
+	if m.config.LongPress > 0 && !k.longSent && duration >= m.config.LongPress {
+		k.longSent = true
+		m.send(Event{Type: LongPress, Key: key, Keys: m.stable, Duration: duration})
+	}
+	if m.config.RepeatDelay > 0 && !now.Before(k.nextRepeat) {
+		k.nextRepeat = k.nextRepeat.Add(m.config.RepeatInterval)
+		if k.nextRepeat.Before(now) {
+			// Updates are too slow, don't try to catch up.
+			k.nextRepeat = now.Add(m.config.RepeatInterval)
+		}
+		m.send(Event{Type: Repeat, Key: key, Keys: m.stable, Duration: duration})
+	}
+}
+
+func (m *Manager) send(e Event) {
+	if m.handler != nil {
+		m.handler(e)
+	}
+}
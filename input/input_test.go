@@ -0,0 +1,186 @@
+package input
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/internal/fakeclock"
+)
+
+type fakePin bool
+
+func (p *fakePin) Get() bool {
+	return bool(*p)
+}
+
+// newManager returns a manager that uses the returned fake clock, and records
+// its events.
+func newManager(config Config) (*Manager, *[]Event, *fakeclock.Clock) {
+	clock := fakeclock.New()
+	events := &[]Event{}
+	m := New(config, func(e Event) { *events = append(*events, e) })
+	m.now = clock.Now
+	return m, events, clock
+}
+
+func TestDebounce(t *testing.T) {
+	m, events, clock := newManager(Config{LongPress: -1})
+	var pins [2]fakePin
+	m.Add(Pins(false, &pins[0], &pins[1]), 2)
+
+	steps := []struct {
+		pin    bool
+		events []Event
+	}{
+		{true, nil},
+		{false, nil}, // bounce
+		{true, nil},
+		{true, nil},
+		{true, []Event{{Type: Press, Key: 1, Keys: 2}}},
+		{true, nil},
+		{false, nil},
+		{false, nil},
+		{false, []Event{{Type: Release, Key: 1, Duration: 40 * time.Millisecond}}},
+	}
+	for i, step := range steps {
+		pins[1] = fakePin(step.pin)
+		*events = nil
+		if err := m.Update(); err != nil {
+			t.Fatal(err)
+		}
+		if !equal(*events, step.events) {
+			t.Errorf("step %d: expected events %v, got %v", i, step.events, *events)
+		}
+		clock.Advance(10 * time.Millisecond)
+	}
+}
+
+func TestLongPressAndRepeat(t *testing.T) {
+	m, events, clock := newManager(Config{
+		Debounce:       time.Millisecond,
+		LongPress:      300 * time.Millisecond,
+		RepeatDelay:    200 * time.Millisecond,
+		RepeatInterval: 50 * time.Millisecond,
+	})
+	pin := fakePin(false)
+	m.Add(Pins(true, &pin), 1)
+
+	m.Update()
+	clock.Advance(10 * time.Millisecond)
+	m.Update()
+	if len(*events) != 1 || (*events)[0].Type != Press {
+		t.Fatalf("expected press, got %v", *events)
+	}
+	for i := 0; i < 35; i++ {
+		clock.Advance(10 * time.Millisecond)
+		m.Update()
+	}
+
+	counts := map[EventType]int{}
+	for _, e := range *events {
+		counts[e.Type]++
+	}
+	if counts[LongPress] != 1 {
+		t.Errorf("expected one long press, got %d", counts[LongPress])
+	}
+	// Held for 350ms: repeats at 200, 250, 300 and 350ms.
+	if counts[Repeat] != 4 {
+		t.Errorf("expected 4 repeats, got %d", counts[Repeat])
+	}
+}
+
+func TestChord(t *testing.T) {
+	m, events, clock := newManager(Config{Debounce: time.Millisecond})
+	var pins [3]fakePin
+	m.Add(Pins(false, &pins[0], &pins[1]), 2)
+	first, _ := m.Add(Pins(false, &pins[2]), 1)
+	if first != 2 {
+		t.Errorf("expected first key of second source to be 2, got %d", first)
+	}
+
+	pins[0] = true
+	m.Update()
+	clock.Advance(5 * time.Millisecond)
+	m.Update()
+	pins[2] = true
+	m.Update()
+	clock.Advance(5 * time.Millisecond)
+	*events = nil
+	m.Update()
+
+	expected := []Event{
+		{Type: Press, Key: 2, Keys: 0b101},
+		{Type: Chord, Key: 2, Keys: 0b101},
+	}
+	if !equal(*events, expected) {
+		t.Errorf("expected %v, got %v", expected, *events)
+	}
+	if !m.Pressed(0) || m.Pressed(1) || m.Keys() != 0b101 {
+		t.Errorf("unexpected key state %b", m.Keys())
+	}
+}
+
+func TestSourceError(t *testing.T) {
+	m, _, clock := newManager(Config{Debounce: time.Millisecond})
+	errRead := errors.New("read failed")
+	fail := false
+	m.Add(SourceFunc(func() (uint32, error) {
+		if fail {
+			return 0, errRead
+		}
+		return 1, nil
+	}), 1)
+
+	m.Update()
+	clock.Advance(5 * time.Millisecond)
+	m.Update()
+	fail = true
+	clock.Advance(5 * time.Millisecond)
+	if err := m.Update(); err != errRead {
+		t.Errorf("expected read error, got %v", err)
+	}
+	if !m.Pressed(0) {
+		t.Error("key was released by a failed read")
+	}
+}
+
+func TestSources(t *testing.T) {
+	keypad := Keypad(keypadFunc(func() uint8 { return 5 }))
+	if keys, _ := keypad.ReadKeys(); keys != 1<<5 {
+		t.Errorf("expected key 5 of keypad, got %b", keys)
+	}
+	keypad = Keypad(keypadFunc(func() uint8 { return 255 }))
+	if keys, _ := keypad.ReadKeys(); keys != 0 {
+		t.Errorf("expected no keys of keypad, got %b", keys)
+	}
+
+	seesaw := Seesaw(gpioFunc(func() (uint32, error) { return 0xFFFF_FFFF &^ (1 << 24), nil }), true, 18, 24)
+	if keys, _ := seesaw.ReadKeys(); keys != 0b10 {
+		t.Errorf("expected second seesaw key, got %b", keys)
+	}
+}
+
+type keypadFunc func() uint8
+
+func (f keypadFunc) GetKey() uint8 {
+	return f()
+}
+
+type gpioFunc func() (uint32, error)
+
+func (f gpioFunc) ReadGPIO() (uint32, error) {
+	return f()
+}
+
+func equal(a, b []Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package input
+
+import "tinygo.org/x/drivers/mcp23017"
+
+// Source is a set of up to 32 keys that are read at once.
+type Source interface {
+	// ReadKeys returns the raw state of the keys, with a bit set for every
+	// key that is pressed.
+	ReadKeys() (uint32, error)
+}
+
+// SourceFunc is a function that implements Source.
+type SourceFunc func() (uint32, error)
+
+// ReadKeys calls the function.
+func (f SourceFunc) ReadKeys() (uint32, error) {
+	return f()
+}
+
+// Pin is a digital input, such as a machine.Pin configured as input.
+type Pin interface {
+	Get() bool
+}
+
+// Pins returns a source for buttons connected to pins, one key per pin. With
+// activeLow, a button is pressed when its pin is low, which is the case for
+// buttons to ground with a pull-up resistor.
+func Pins(activeLow bool, pins ...Pin) Source {
+	return SourceFunc(func() (uint32, error) {
+		var keys uint32
+		for i, pin := range pins {
+			if pin.Get() != activeLow {
+				keys |= 1 << i
+			}
+		}
+		return keys, nil
+	})
+}
+
+// Keypad returns a source for a keypad that reports a single key at a time,
+// such as a keypad4x4.Device. Keys are numbered like the keypad numbers them,
+// so add the source with 16 keys for a 4x4 keypad.
+func Keypad(keypad interface{ GetKey() uint8 }) Source {
+	return SourceFunc(func() (uint32, error) {
+		key := keypad.GetKey()
+		if key >= 32 {
+			// No key pressed.
+			return 0, nil
+		}
+		return 1 << key, nil
+	})
+}
+
+// TouchKeys returns a source for a touch key controller that returns a bitmask
+// with a bit cleared for every touched key, such as a ttp229.Device.
+func TouchKeys(keys interface{ ReadKeys() uint16 }) Source {
+	return SourceFunc(func() (uint32, error) {
+		return uint32(^keys.ReadKeys()), nil
+	})
+}
+
+// Capacitive returns a source for the first n pins of a capacitive touch
+// array, such as a capacitive.Array. The array is updated on every read.
+func Capacitive(array interface {
+	Update()
+	Touching(index int) bool
+}, n int) Source {
+	return SourceFunc(func() (uint32, error) {
+		array.Update()
+		var keys uint32
+		for i := 0; i < n; i++ {
+			if array.Touching(i) {
+				keys |= 1 << i
+			}
+		}
+		return keys, nil
+	})
+}
+
+// Expander returns a source for buttons connected to the 16 pins of an
+// mcp23017.Device, configured as inputs. With activeLow, a button is pressed
+// when its pin is low.
+func Expander(expander interface {
+	GetPins() (mcp23017.Pins, error)
+}, activeLow bool) Source {
+	return SourceFunc(func() (uint32, error) {
+		pins, err := expander.GetPins()
+		if err != nil {
+			return 0, err
+		}
+		if activeLow {
+			pins = ^pins
+		}
+		return uint32(pins), nil
+	})
+}
+
+// Seesaw returns a source for buttons connected to the GPIO pins of a seesaw
+// device, configured as inputs with seesaw.Device.ConfigureInputs. The pins
+// are given by number, one key per pin. With activeLow, a button is pressed
+// when its pin is low.
+func Seesaw(device interface{ ReadGPIO() (uint32, error) }, activeLow bool, pins ...uint8) Source {
+	return SourceFunc(func() (uint32, error) {
+		gpio, err := device.ReadGPIO()
+		if err != nil {
+			return 0, err
+		}
+		if activeLow {
+			gpio = ^gpio
+		}
+		var keys uint32
+		for i, pin := range pins {
+			if gpio&(1<<pin) != 0 {
+				keys |= 1 << i
+			}
+		}
+		return keys, nil
+	})
+}
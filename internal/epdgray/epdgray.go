@@ -0,0 +1,85 @@
+// Package epdgray contains the parts of 4-level grayscale support that are
+// shared by the e-paper drivers.
+package epdgray // import "tinygo.org/x/drivers/internal/epdgray"
+
+import (
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// Look up tables for 4-level grayscale on the UC8151/IL0398 family of
+// controllers, which select a waveform by the bits of the old and new image.
+// Each of the four LUTs (WW, BW, WB and BB) drives the pixels to a different
+// gray level.
+// Derived from https://github.com/waveshare/e-Paper/blob/master/RaspberryPi_JetsonNano/c/lib/e-Paper/EPD_4in2.c
+var LUTVCOM = [44]uint8{
+	0x00, 0x0A, 0x00, 0x00, 0x00, 0x01,
+	0x60, 0x14, 0x14, 0x00, 0x00, 0x01,
+	0x00, 0x14, 0x00, 0x00, 0x00, 0x01,
+	0x00, 0x13, 0x0A, 0x01, 0x00, 0x01,
+}
+
+// White.
+var LUTWW = [42]uint8{
+	0x40, 0x0A, 0x00, 0x00, 0x00, 0x01,
+	0x90, 0x14, 0x14, 0x00, 0x00, 0x01,
+	0x10, 0x14, 0x0A, 0x00, 0x00, 0x01,
+	0xA0, 0x13, 0x01, 0x00, 0x00, 0x01,
+}
+
+// Dark gray.
+var LUTBW = [42]uint8{
+	0x40, 0x0A, 0x00, 0x00, 0x00, 0x01,
+	0x90, 0x14, 0x14, 0x00, 0x00, 0x01,
+	0x00, 0x14, 0x0A, 0x00, 0x00, 0x01,
+	0x99, 0x0C, 0x01, 0x03, 0x04, 0x01,
+}
+
+// Light gray.
+var LUTWB = [42]uint8{
+	0x40, 0x0A, 0x00, 0x00, 0x00, 0x01,
+	0x90, 0x14, 0x14, 0x00, 0x00, 0x01,
+	0x00, 0x14, 0x0A, 0x00, 0x00, 0x01,
+	0x99, 0x0B, 0x04, 0x04, 0x01, 0x01,
+}
+
+// Black.
+var LUTBB = [42]uint8{
+	0x80, 0x0A, 0x00, 0x00, 0x00, 0x01,
+	0x90, 0x14, 0x14, 0x00, 0x00, 0x01,
+	0x20, 0x14, 0x0A, 0x00, 0x00, 0x01,
+	0x50, 0x13, 0x01, 0x00, 0x00, 0x01,
+}
+
+// PackRow packs row y of the display into buf, with one bit per pixel and the
+// leftmost pixel in the most significant bit. The bit of a pixel is set when
+// bit n of levels is set, where n is the gray level of the pixel. For example,
+// levels 0b1100 sets the bits of the two lightest levels.
+//
+// The image is drawn with the given rotation (see drivers.Rotation.Transform),
+// on a display of width by height pixels without rotation, and must have the
+// size of the rotated display. Pixels outside the
+// image, including the padding at the end of the row, are white (level 3).
+func PackRow(buf []uint8, img pixel.Image[pixel.Gray2], rotation drivers.Rotation, width, height, y int16, levels uint8) {
+	// Transform maps image coordinates to display coordinates, so it's used
+	// with the inverse rotation. Mirrored rotations are their own inverse.
+	inverse := rotation % 8
+	if !inverse.IsMirrored() {
+		inverse = (4 - inverse) % 4
+	}
+	imgWidth, imgHeight := rotation.Size(width, height)
+	for i := range buf {
+		var b uint8
+		for bit := int16(0); bit < 8; bit++ {
+			level := pixel.Gray2(3)
+			ix, iy := inverse.Transform(int16(i)*8+bit, y, imgWidth, imgHeight)
+			if ix >= 0 && iy >= 0 && ix < imgWidth && iy < imgHeight {
+				level = img.Get(int(ix), int(iy))
+			}
+			if levels>>level&1 != 0 {
+				b |= 0x80 >> bit
+			}
+		}
+		buf[i] = b
+	}
+}
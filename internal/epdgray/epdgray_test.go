@@ -0,0 +1,57 @@
+package epdgray
+
+import (
+	"testing"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// TestPackRow checks that every pixel of the image ends up where SetPixel of
+// the drivers puts it.
+func TestPackRow(t *testing.T) {
+	const width, height = 12, 8
+	buf := make([]uint8, 2)
+	for rotation := drivers.Rotation(0); rotation < 8; rotation++ {
+		w, h := rotation.Size(width, height)
+		img := pixel.NewImage[pixel.Gray2](int(w), int(h))
+		for iy := int16(0); iy < h; iy++ {
+			for ix := int16(0); ix < w; ix++ {
+				img.FillSolidColor(3)
+				img.Set(int(ix), int(iy), 0)
+				wantX, wantY := rotation.Transform(ix, iy, width, height)
+				for y := int16(0); y < height; y++ {
+					PackRow(buf, img, rotation, width, height, y, 0b0001)
+					for x := int16(0); x < 16; x++ {
+						set := buf[x/8]&(0x80>>(x%8)) != 0
+						if set != (x == wantX && y == wantY) {
+							t.Fatalf("rotation %d: pixel %d,%d: bit %d,%d is %v", rotation, ix, iy, x, y, set)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPackRowLevels(t *testing.T) {
+	img := pixel.NewImage[pixel.Gray2](4, 1)
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, pixel.Gray2(x))
+	}
+	buf := make([]uint8, 1)
+	for _, tc := range []struct {
+		levels uint8
+		want   uint8
+	}{
+		{0b0001, 0b1000_0000},
+		{0b0101, 0b1010_0000},
+		{0b1100, 0b0011_1111}, // padding is white
+		{0b1111, 0b1111_1111},
+	} {
+		PackRow(buf, img, drivers.Rotation0, 4, 1, 0, tc.levels)
+		if buf[0] != tc.want {
+			t.Errorf("levels %04b: got %08b, want %08b", tc.levels, buf[0], tc.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Package fakeclock provides a manual clock for the tests of packages that
+// read the current time through a replaceable now function.
+package fakeclock
+
+import "time"
+
+// Clock is a clock that only moves when it is advanced.
+type Clock struct {
+	now time.Time
+}
+
+// New returns a clock that starts at a fixed time.
+func New() *Clock {
+	return &Clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// Now returns the current time of the clock. It can be used as the now
+// function of the package under test.
+func (c *Clock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
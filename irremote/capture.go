@@ -0,0 +1,69 @@
+package irremote
+
+import "time"
+
+// Size of the buffer for captured pulses, which is large enough for most
+// remotes with unknown protocols.
+const maxCapturePulses = 128
+
+// Time without pulses after which a code is complete. It is longer than the
+// longest space of the supported protocols (the 4.5ms leader space of NEC and
+// Samsung), and shorter than the time between repeated codes, which is only
+// about 6ms for 20-bit Sony codes.
+const frameGap = 5 * time.Millisecond
+
+// capture splits received pulses into codes. The last complete code is kept
+// until it is read, so that a repeated code which follows it closely doesn't
+// overwrite it.
+type capture struct {
+	pulses    []time.Duration // pulses of the code being received
+	numPulses int             // number of pulses, more than fit on overflow
+	code      []time.Duration // last complete code
+	numCode   int             // number of pulses in code, 0 once it is read
+}
+
+// init allocates the buffers for codes of up to size pulses, and drops the
+// received pulses.
+func (c *capture) init(size int) {
+	if len(c.pulses) != size {
+		c.pulses = make([]time.Duration, size)
+		c.code = make([]time.Duration, size)
+	}
+	c.numPulses = 0
+	c.numCode = 0
+}
+
+// edge handles a pin change, after a pulse with duration d.
+func (c *capture) edge(d time.Duration) {
+	if d >= frameGap {
+		// Start of a new code, with the start of a mark.
+		c.finish()
+		return
+	}
+	if c.numPulses < len(c.pulses) {
+		c.pulses[c.numPulses] = d
+	}
+	c.numPulses++
+}
+
+// finish completes the code being received. It is dropped when it didn't fit,
+// or when the previous code wasn't read yet.
+func (c *capture) finish() {
+	if c.numCode == 0 && c.numPulses <= len(c.pulses) {
+		c.pulses, c.code = c.code, c.pulses
+		c.numCode = c.numPulses
+	}
+	c.numPulses = 0
+}
+
+// read copies the last complete code to buf, and returns the number of pulses.
+// When idle is set, no pulses were received for frameGap, so the code being
+// received is complete too.
+func (c *capture) read(buf []time.Duration, idle bool) int {
+	if c.numCode == 0 && idle {
+		c.finish()
+	}
+	n := copy(buf, c.code[:c.numCode])
+	c.numCode = 0
+	return n
+}
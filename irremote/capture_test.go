@@ -0,0 +1,74 @@
+package irremote
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptureRepeats(t *testing.T) {
+	// Sony remotes send a code at least 3 times, starting every 45ms, which
+	// leaves only about 6ms between 20-bit codes.
+	for _, bits := range []uint8{12, 15, 20} {
+		want := Data{Protocol: ProtocolSony, Address: 0x1a, Command: 0x15, Bits: bits}
+		var pulses [MaxPulses]time.Duration
+		n := encodeSony(want, pulses[:])
+		code := distort(pulses[:n], 100*us)
+		gap := 45 * time.Millisecond
+		for _, p := range code {
+			gap -= p
+		}
+
+		var c capture
+		c.init(maxCapturePulses)
+		buf := make([]time.Duration, maxCapturePulses)
+		c.edge(time.Second)
+		for i := 0; i < 3; i++ {
+			if i > 0 {
+				c.edge(gap)
+			}
+			for _, p := range code {
+				c.edge(p)
+			}
+			// The previous code is complete once the next one starts.
+			n := c.read(buf, false)
+			if i == 0 {
+				if n != 0 {
+					t.Errorf("%d bits: got %d pulses before the code was complete", bits, n)
+				}
+				continue
+			}
+			if got, ok := Decode(buf[:n], Protocols...); !ok || got.Address != want.Address || got.Command != want.Command || got.Bits != bits {
+				t.Errorf("%d bits: repeat %d decoded as %+v, %v from %d pulses", bits, i, got, ok, n)
+			}
+		}
+
+		// The last code is complete when the IR is idle.
+		n = c.read(buf, true)
+		if got, ok := Decode(buf[:n], Protocols...); !ok || got.Command != want.Command {
+			t.Errorf("%d bits: last code decoded as %+v, %v from %d pulses", bits, got, ok, n)
+		}
+		if n := c.read(buf, true); n != 0 {
+			t.Errorf("%d bits: got %d pulses after reading all codes", bits, n)
+		}
+	}
+}
+
+func TestCaptureOverflow(t *testing.T) {
+	var c capture
+	c.init(4)
+	buf := make([]time.Duration, 4)
+	c.edge(time.Second)
+	for i := 0; i < 5; i++ {
+		c.edge(time.Millisecond)
+	}
+	if n := c.read(buf, true); n != 0 {
+		t.Errorf("got %d pulses of a code that didn't fit", n)
+	}
+	c.edge(time.Second)
+	for i := 0; i < 3; i++ {
+		c.edge(time.Millisecond)
+	}
+	if n := c.read(buf, true); n != 3 {
+		t.Errorf("got %d pulses, expected 3", n)
+	}
+}
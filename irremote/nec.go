@@ -0,0 +1,142 @@
+package irremote
+
+import "time"
+
+// NEC and Samsung codes have a leader, followed by 32 bits with the least
+// significant bit first. Every bit is a 562.5µs mark followed by a space
+// whose duration is the value of the bit, and the code ends with a stop mark.
+// The protocols differ only in the leader and how the bytes are used.
+// https://www.sbprojects.net/knowledge/ir/nec.php
+// https://www.sbprojects.net/knowledge/ir/samsung.php
+
+const (
+	necLeadMark    = 9000 * time.Microsecond
+	necLeadSpace   = 4500 * time.Microsecond
+	necRepeatSpace = 2250 * time.Microsecond
+
+	samsungLeadMark  = 4500 * time.Microsecond
+	samsungLeadSpace = 4500 * time.Microsecond
+
+	pulseDistanceMark  = 562500 * time.Nanosecond
+	pulseDistanceZero  = 562500 * time.Nanosecond
+	pulseDistanceOne   = 1687500 * time.Nanosecond
+	pulseDistanceCount = 2 + 32*2 + 1
+)
+
+func decodeNEC(pulses []time.Duration) (Data, bool) {
+	if len(pulses) == 3 && near(pulses[0], necLeadMark) && near(pulses[1], necRepeatSpace) && near(pulses[2], pulseDistanceMark) {
+		// The address and command of a repeat code are those of the
+		// previous code, which isn't known here.
+		return Data{Protocol: ProtocolNEC, Flags: DataFlagIsRepeat}, true
+	}
+	code, ok := decodePulseDistance(pulses, necLeadMark, necLeadSpace)
+	if !ok {
+		return Data{}, false
+	}
+	return necData(code)
+}
+
+// necData returns the address and command of an NEC code, or false when the
+// inverse of the command doesn't match.
+func necData(code uint32) (Data, bool) {
+	// Decode cmd and inverse cmd and perform validation check
+	cmd := uint8((code & 0x00ff0000) >> 16)
+	invCmd := uint8((code & 0xff000000) >> 24)
+	if cmd != ^invCmd {
+		// Validation failure. cmd and inverse cmd do not match
+		return Data{}, false
+	}
+	data := Data{Code: code, Command: uint16(cmd), Protocol: ProtocolNEC}
+	addrLow := uint8(code & 0xff)
+	addrHigh := uint8((code & 0xff00) >> 8)
+	if addrHigh == ^addrLow {
+		// addrHigh is inverse of addrLow. This is not a valid 16-bit address in extended NEC coding
+		// since it is indistinguishable from 8-bit address with inverse validation. Use the 8-bit address
+		data.Address = uint16(addrLow)
+	} else {
+		// 16-bit extended NEC address
+		data.Address = (uint16(addrHigh) << 8) | uint16(addrLow)
+	}
+	return data, true
+}
+
+func encodeNEC(data Data, pulses []time.Duration) int {
+	if data.Flags&DataFlagIsRepeat != 0 {
+		pulses[0] = necLeadMark
+		pulses[1] = necRepeatSpace
+		pulses[2] = pulseDistanceMark
+		return 3
+	}
+	address := uint32(data.Address)
+	if address <= 0xff {
+		address |= uint32(^uint8(address)) << 8
+	}
+	cmd := uint8(data.Command)
+	code := address | uint32(cmd)<<16 | uint32(^cmd)<<24
+	return encodePulseDistance(code, necLeadMark, necLeadSpace, pulses)
+}
+
+func decodeSamsung(pulses []time.Duration) (Data, bool) {
+	code, ok := decodePulseDistance(pulses, samsungLeadMark, samsungLeadSpace)
+	if !ok {
+		return Data{}, false
+	}
+	cmd := uint8(code >> 16)
+	if cmd != ^uint8(code>>24) {
+		return Data{}, false
+	}
+	data := Data{Code: code, Command: uint16(cmd), Protocol: ProtocolSamsung}
+	data.Address = uint16(code)
+	if uint8(code) == uint8(code>>8) {
+		// Most remotes send an 8-bit address twice.
+		data.Address = uint16(uint8(code))
+	}
+	return data, true
+}
+
+func encodeSamsung(data Data, pulses []time.Duration) int {
+	address := uint32(data.Address)
+	if address <= 0xff {
+		address |= address << 8
+	}
+	cmd := uint8(data.Command)
+	code := address | uint32(cmd)<<16 | uint32(^cmd)<<24
+	return encodePulseDistance(code, samsungLeadMark, samsungLeadSpace, pulses)
+}
+
+func decodePulseDistance(pulses []time.Duration, leadMark, leadSpace time.Duration) (uint32, bool) {
+	if len(pulses) != pulseDistanceCount || !near(pulses[0], leadMark) || !near(pulses[1], leadSpace) {
+		return 0, false
+	}
+	var code uint32
+	for i := 0; i < 32; i++ {
+		mark, space := pulses[2+i*2], pulses[3+i*2]
+		if !near(mark, pulseDistanceMark) {
+			return 0, false
+		}
+		switch {
+		case near(space, pulseDistanceOne):
+			code |= 1 << i
+		case !near(space, pulseDistanceZero):
+			return 0, false
+		}
+	}
+	if !near(pulses[len(pulses)-1], pulseDistanceMark) {
+		return 0, false
+	}
+	return code, true
+}
+
+func encodePulseDistance(code uint32, leadMark, leadSpace time.Duration, pulses []time.Duration) int {
+	pulses[0] = leadMark
+	pulses[1] = leadSpace
+	for i := 0; i < 32; i++ {
+		pulses[2+i*2] = pulseDistanceMark
+		pulses[3+i*2] = pulseDistanceZero
+		if code&(1<<i) != 0 {
+			pulses[3+i*2] = pulseDistanceOne
+		}
+	}
+	pulses[pulseDistanceCount-1] = pulseDistanceMark
+	return pulseDistanceCount
+}
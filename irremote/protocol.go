@@ -0,0 +1,147 @@
+package irremote
+
+import "time"
+
+// Data encapsulates the data received by the ReceiverDevice.
+type Data struct {
+	// Code is the raw IR data received.
+	Code uint32
+	// Address is the decoded address from the IR data received.
+	Address uint16
+	// Command is the decoded command from the IR data recieved
+	Command uint16
+	// Flags provides additional information about the IR data received. See DataFlags
+	Flags DataFlags
+	// Protocol is the protocol of the IR data. The built-in decoder of the
+	// ReceiverDevice only decodes NEC.
+	Protocol Protocol
+	// Bits is the number of bits of the code, for protocols with codes of
+	// different lengths (Sony). When sending, 0 selects the shortest code that
+	// fits the address.
+	Bits uint8
+}
+
+// DataFlags provides bitwise flags representing various information about recieved IR data.
+type DataFlags uint16
+
+// Valid values for DataFlags
+const (
+	// DataFlagIsRepeat set indicates that the IR data is a repeat commmand
+	DataFlagIsRepeat DataFlags = 1 << iota
+	// DataFlagToggle is the toggle bit of RC5 and RC6, which changes on every
+	// key press but not while a key is held.
+	DataFlagToggle
+)
+
+// CommandHandler defines the callback function used to provide IR data received by the ReceiverDevice.
+type CommandHandler func(data Data)
+
+// Protocol is an IR remote control protocol.
+type Protocol uint8
+
+// Supported protocols.
+const (
+	ProtocolNEC Protocol = iota
+	ProtocolSamsung
+	ProtocolSony
+	ProtocolRC5
+	ProtocolRC6
+)
+
+// Protocols is the list of all supported protocols, to decode pulses of an
+// unknown remote.
+var Protocols = []Protocol{ProtocolNEC, ProtocolSamsung, ProtocolSony, ProtocolRC5, ProtocolRC6}
+
+// String returns the name of the protocol.
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolNEC:
+		return "NEC"
+	case ProtocolSamsung:
+		return "Samsung"
+	case ProtocolSony:
+		return "Sony"
+	case ProtocolRC5:
+		return "RC5"
+	case ProtocolRC6:
+		return "RC6"
+	}
+	return "unknown"
+}
+
+// Carrier returns the carrier frequency of the protocol in Hz.
+func (p Protocol) Carrier() uint32 {
+	switch p {
+	case ProtocolSony:
+		return 40000
+	case ProtocolRC5, ProtocolRC6:
+		return 36000
+	}
+	return 38000
+}
+
+// MaxPulses is the maximum number of pulses of a code of the supported
+// protocols. NEC and Samsung codes are the longest, with a leader, 32 bits and
+// a stop mark.
+const MaxPulses = 2 + 32*2 + 1
+
+// Decode decodes a code from pulses. The pulses alternate between mark (IR
+// on) and space (IR off), starting with a mark, and contain a single code
+// without the space after it.
+func (p Protocol) Decode(pulses []time.Duration) (Data, bool) {
+	switch p {
+	case ProtocolNEC:
+		return decodeNEC(pulses)
+	case ProtocolSamsung:
+		return decodeSamsung(pulses)
+	case ProtocolSony:
+		return decodeSony(pulses)
+	case ProtocolRC5:
+		return decodeRC5(pulses)
+	case ProtocolRC6:
+		return decodeRC6(pulses)
+	}
+	return Data{}, false
+}
+
+// Encode encodes data as pulses, starting with a mark, and returns the number
+// of pulses. The data is encoded with the protocol p, regardless of
+// data.Protocol. The pulses must have room for MaxPulses pulses.
+func (p Protocol) Encode(data Data, pulses []time.Duration) int {
+	switch p {
+	case ProtocolNEC:
+		return encodeNEC(data, pulses)
+	case ProtocolSamsung:
+		return encodeSamsung(data, pulses)
+	case ProtocolSony:
+		return encodeSony(data, pulses)
+	case ProtocolRC5:
+		return encodeRC5(data, pulses)
+	case ProtocolRC6:
+		return encodeRC6(data, pulses)
+	}
+	return 0
+}
+
+// Decode decodes pulses with the first of the protocols that recognizes them.
+func Decode(pulses []time.Duration, protocols ...Protocol) (Data, bool) {
+	for _, p := range protocols {
+		if data, ok := p.Decode(pulses); ok {
+			return data, true
+		}
+	}
+	return Data{}, false
+}
+
+// near returns whether a pulse duration is close enough to the expected
+// duration. IR receivers stretch marks and shorten spaces, so the tolerance is
+// quite large.
+func near(d, expected time.Duration) bool {
+	return d > expected*6/10 && d < expected*14/10
+}
+
+// units returns the number of time units of a pulse, rounded to the nearest
+// unit.
+func units(d, unit time.Duration) int {
+	return int((d + unit/2) / unit)
+}
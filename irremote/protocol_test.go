@@ -0,0 +1,102 @@
+package irremote
+
+import (
+	"testing"
+	"time"
+)
+
+const us = time.Microsecond
+
+// distort makes pulses look like the output of an IR receiver, which
+// lengthens marks and shortens spaces.
+func distort(pulses []time.Duration, d time.Duration) []time.Duration {
+	out := make([]time.Duration, len(pulses))
+	for i, p := range pulses {
+		if i%2 == 0 {
+			out[i] = p + d
+		} else {
+			out[i] = p - d
+		}
+	}
+	return out
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []Data{
+		{Protocol: ProtocolNEC, Address: 0x04, Command: 0x08},
+		{Protocol: ProtocolNEC, Address: 0x1234, Command: 0xff},
+		{Protocol: ProtocolNEC, Flags: DataFlagIsRepeat},
+		{Protocol: ProtocolSamsung, Address: 0x07, Command: 0x02},
+		{Protocol: ProtocolSamsung, Address: 0x0e07, Command: 0x99},
+		{Protocol: ProtocolSony, Address: 0x01, Command: 0x15, Bits: 12},
+		{Protocol: ProtocolSony, Address: 0x01, Command: 0x15, Bits: 15},
+		{Protocol: ProtocolSony, Address: 0x1a4, Command: 0x7f, Bits: 20},
+		{Protocol: ProtocolRC5, Address: 0x00, Command: 0x0c},
+		{Protocol: ProtocolRC5, Address: 0x1f, Command: 0x7f, Flags: DataFlagToggle},
+		{Protocol: ProtocolRC5, Address: 0x05, Command: 0x35},
+		{Protocol: ProtocolRC6, Address: 0x00, Command: 0x0c},
+		{Protocol: ProtocolRC6, Address: 0xff, Command: 0x80, Flags: DataFlagToggle},
+		{Protocol: ProtocolRC6, Address: 0x04, Command: 0x01},
+	}
+	for _, want := range tests {
+		var pulses [MaxPulses]time.Duration
+		n := want.Protocol.Encode(want, pulses[:])
+		for _, d := range []time.Duration{0, 100 * us, -100 * us} {
+			got, ok := Decode(distort(pulses[:n], d), Protocols...)
+			if !ok {
+				t.Errorf("%v: not decoded with distortion %v: %v", want, d, pulses[:n])
+				continue
+			}
+			got.Code = 0
+			if got != want {
+				t.Errorf("expected %+v, got %+v with distortion %v", want, got, d)
+			}
+		}
+	}
+}
+
+func TestDecodeRecorded(t *testing.T) {
+	// Sony TV power: command 21, address 1.
+	sony := []time.Duration{2450 * us,
+		550 * us, 1250 * us, 550 * us, 650 * us, 550 * us, 1250 * us, 550 * us, 650 * us,
+		550 * us, 1250 * us, 550 * us, 650 * us, 550 * us, 650 * us,
+		550 * us, 1250 * us, 550 * us, 650 * us, 550 * us, 650 * us, 550 * us, 650 * us, 550 * us, 650 * us,
+	}
+	data, ok := Decode(sony, Protocols...)
+	if !ok || data.Protocol != ProtocolSony || data.Address != 1 || data.Command != 21 || data.Bits != 12 {
+		t.Errorf("unexpected result for Sony code: %+v, %v", data, ok)
+	}
+
+	// RC5 command 12 for address 0, toggle bit set: bits 11 1 00000 001100.
+	rc5 := []time.Duration{
+		889 * us, 889 * us, 889 * us, 889 * us, 1778 * us, 889 * us, 889 * us, 889 * us, 889 * us,
+		889 * us, 889 * us, 889 * us, 889 * us, 889 * us, 889 * us, 889 * us, 889 * us, 1778 * us,
+		889 * us, 889 * us, 1778 * us, 889 * us, 889 * us,
+	}
+	data, ok = Decode(rc5, Protocols...)
+	if !ok || data.Protocol != ProtocolRC5 || data.Address != 0 || data.Command != 12 || data.Flags != DataFlagToggle {
+		t.Errorf("unexpected result for RC5 code: %+v, %v", data, ok)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	var pulses [MaxPulses]time.Duration
+	n := ProtocolNEC.Encode(Data{Address: 1, Command: 2}, pulses[:])
+
+	// The inverse of the command doesn't match.
+	pulses[2+16*2+1] = pulseDistanceOne
+	if data, ok := Decode(pulses[:n], Protocols...); ok {
+		t.Errorf("decoded invalid NEC code as %+v", data)
+	}
+
+	// Truncated codes.
+	for _, p := range Protocols {
+		n := p.Encode(Data{Address: 1, Command: 2}, pulses[:])
+		if data, ok := Decode(pulses[:n-3], Protocols...); ok {
+			t.Errorf("decoded truncated %v code as %+v", p, data)
+		}
+	}
+	if _, ok := Decode(nil, Protocols...); ok {
+		t.Error("decoded empty pulses")
+	}
+}
@@ -0,0 +1,183 @@
+package irremote
+
+import "time"
+
+// RC5 and RC6 use Manchester coding, where every bit is a mark and a space of
+// equal duration, in an order that depends on the value of the bit.
+//
+// RC5 codes have 14 bits of 1.778ms, with the most significant bit first: a
+// start bit that is always 1, a second start bit that is the inverse of the
+// 7th command bit, a toggle bit, 5 address bits and 6 command bits. A 1 is a
+// space followed by a mark.
+// https://www.sbprojects.net/knowledge/ir/rc5.php
+//
+// RC6 mode 0 codes have a leader of a 2.666ms mark and a 889µs space,
+// followed by a start bit that is always 1, 3 mode bits, a toggle bit of
+// double duration, 8 address bits and 8 command bits. Bits are 889µs, and a 1
+// is a mark followed by a space.
+// https://www.sbprojects.net/knowledge/ir/rc6.php
+
+const (
+	rc5Unit  = 889 * time.Microsecond // half a bit
+	rc5Units = 14 * 2
+
+	rc6Unit      = 444 * time.Microsecond // half a bit
+	rc6LeadMark  = 6 * rc6Unit
+	rc6LeadSpace = 2 * rc6Unit
+	rc6Units     = 2 + 3*2 + 4 + 16*2
+)
+
+func decodeRC5(pulses []time.Duration) (Data, bool) {
+	// The first half of the first start bit is a space, which isn't part of
+	// the pulses.
+	var levels [rc5Units]bool
+	n := expand(pulses, rc5Unit, 2, levels[:], 1)
+	if n < rc5Units-1 {
+		// The last half can be a space, which isn't part of the pulses
+		// either.
+		return Data{}, false
+	}
+	var code uint32
+	for i := 0; i < 14; i++ {
+		first, second := levels[i*2], levels[i*2+1]
+		if first == second {
+			return Data{}, false
+		}
+		code = code<<1 | uint32(b2u(second))
+	}
+	if code&(1<<13) == 0 {
+		return Data{}, false
+	}
+	data := Data{
+		Code:     code,
+		Address:  uint16(code>>6) & 0x1f,
+		Command:  uint16(code&0x3f) | uint16(^code>>12&1)<<6,
+		Protocol: ProtocolRC5,
+	}
+	if code&(1<<11) != 0 {
+		data.Flags |= DataFlagToggle
+	}
+	return data, true
+}
+
+func encodeRC5(data Data, pulses []time.Duration) int {
+	code := uint32(1)<<13 | uint32(^data.Command>>6&1)<<12 | uint32(data.Address&0x1f)<<6 | uint32(data.Command&0x3f)
+	if data.Flags&DataFlagToggle != 0 {
+		code |= 1 << 11
+	}
+	var levels [rc5Units]bool
+	for i := 0; i < 14; i++ {
+		one := code&(1<<(13-i)) != 0
+		levels[i*2] = !one
+		levels[i*2+1] = one
+	}
+	// Skip the space of the first start bit.
+	return compress(levels[1:], rc5Unit, pulses, 0)
+}
+
+func decodeRC6(pulses []time.Duration) (Data, bool) {
+	if len(pulses) < 2 || !near(pulses[0], rc6LeadMark) || !near(pulses[1], rc6LeadSpace) {
+		return Data{}, false
+	}
+	var levels [rc6Units]bool
+	n := expand(pulses[2:], rc6Unit, 3, levels[:], 0)
+	if n < rc6Units-1 {
+		return Data{}, false
+	}
+
+	// Start bit, mode bits and toggle bit.
+	if !levels[0] || levels[1] {
+		return Data{}, false
+	}
+	for i := 2; i < 8; i += 2 {
+		if levels[i] || !levels[i+1] {
+			// Only mode 0 is supported.
+			return Data{}, false
+		}
+	}
+	if levels[8] != levels[9] || levels[10] != levels[11] || levels[9] == levels[10] {
+		return Data{}, false
+	}
+
+	var code uint32
+	for i := 12; i < rc6Units; i += 2 {
+		if levels[i] == levels[i+1] {
+			return Data{}, false
+		}
+		code = code<<1 | uint32(b2u(levels[i]))
+	}
+	data := Data{
+		Code:     code,
+		Address:  uint16(code >> 8),
+		Command:  uint16(code & 0xff),
+		Protocol: ProtocolRC6,
+	}
+	if levels[8] {
+		data.Flags |= DataFlagToggle
+	}
+	return data, true
+}
+
+func encodeRC6(data Data, pulses []time.Duration) int {
+	var levels [rc6Units]bool
+	levels[0] = true // start bit
+	for i := 2; i < 8; i += 2 {
+		levels[i+1] = true // mode 0
+	}
+	toggle := data.Flags&DataFlagToggle != 0
+	levels[8], levels[9] = toggle, toggle
+	levels[10], levels[11] = !toggle, !toggle
+	code := uint16(data.Address&0xff)<<8 | data.Command&0xff
+	for i := 0; i < 16; i++ {
+		one := code&(1<<(15-i)) != 0
+		levels[12+i*2] = one
+		levels[13+i*2] = !one
+	}
+	pulses[0] = rc6LeadMark
+	pulses[1] = rc6LeadSpace
+	return compress(levels[:], rc6Unit, pulses, 2)
+}
+
+// expand converts pulses to levels of one unit each, starting at levels[n],
+// where true is a mark. It returns the number of levels, or -1 when a pulse is
+// not 1 to maxUnits units long or there are too many levels.
+func expand(pulses []time.Duration, unit time.Duration, maxUnits int, levels []bool, n int) int {
+	for i, d := range pulses {
+		count := units(d, unit)
+		if count < 1 || count > maxUnits || n+count > len(levels) {
+			return -1
+		}
+		for ; count > 0; count-- {
+			levels[n] = i%2 == 0
+			n++
+		}
+	}
+	return n
+}
+
+// compress converts levels of one unit each to pulses, starting at pulses[n],
+// and returns the number of pulses. The levels must start with a mark, and a
+// trailing space is left out.
+func compress(levels []bool, unit time.Duration, pulses []time.Duration, n int) int {
+	count := 0
+	for i, level := range levels {
+		count++
+		if i+1 < len(levels) && levels[i+1] == level {
+			continue
+		}
+		if !level && i+1 == len(levels) {
+			break
+		}
+		pulses[n] = time.Duration(count) * unit
+		n++
+		count = 0
+	}
+	return n
+}
+
+func b2u(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -1,7 +1,10 @@
+//go:build tinygo
+
 package irremote // import "tinygo.org/x/drivers/irremote"
 
 import (
 	"machine"
+	"runtime/interrupt"
 	"time"
 )
 
@@ -10,30 +13,6 @@ import (
 // https://techdocs.altium.com/display/FPGA/NEC+Infrared+Transmission+Protocol
 // https://simple-circuit.com/arduino-nec-remote-control-decoder/
 
-// Data encapsulates the data received by the ReceiverDevice.
-type Data struct {
-	// Code is the raw IR data received.
-	Code uint32
-	// Address is the decoded address from the IR data received.
-	Address uint16
-	// Command is the decoded command from the IR data recieved
-	Command uint16
-	// Flags provides additional information about the IR data received. See DataFlags
-	Flags DataFlags
-}
-
-// DataFlags provides bitwise flags representing various information about recieved IR data.
-type DataFlags uint16
-
-// Valid values for DataFlags
-const (
-	// DataFlagIsRepeat set indicates that the IR data is a repeat commmand
-	DataFlagIsRepeat DataFlags = 1 << iota
-)
-
-// CommandHandler defines the callback function used to provide IR data received by the ReceiverDevice.
-type CommandHandler func(data Data)
-
 // nec_ir_state represents the various internal states used to decode the NEC IR protocol commands
 type nec_ir_state uint8
 
@@ -55,6 +34,7 @@ type ReceiverDevice struct {
 	data     Data           // decoded data for client
 	lastTime time.Time      // used to track states
 	bitIndex int            // tracks which bit (0-31) of necCode is being read
+	capture  capture        // pulses captured by StartCapture
 }
 
 // NewReceiver returns a new IR receiver device
@@ -81,6 +61,40 @@ func (ir *ReceiverDevice) SetCommandHandler(ch CommandHandler) {
 	}
 }
 
+// StartCapture starts capturing the pulses of received codes, instead of
+// decoding NEC codes with the command handler. Read the pulses with
+// ReadPulses, and decode them with Decode. Call SetCommandHandler to stop
+// capturing.
+func (ir *ReceiverDevice) StartCapture() {
+	ir.ch = nil
+	ir.capture.init(maxCapturePulses)
+	ir.pin.SetInterrupt(machine.PinFalling|machine.PinRising, ir.capturePinChange)
+}
+
+// ReadPulses copies the pulses of a received code to buf, and returns the
+// number of pulses. It returns 0 when no complete code was received since the
+// previous call, or when the code was too long. The pulses alternate between
+// mark (IR on) and space (IR off), starting with a mark.
+//
+// A code is complete when no pulses were received for 5ms, or when the next
+// code starts. Only one complete code is kept, so call ReadPulses often enough
+// to not miss codes, for example every 10ms. Remotes repeat codes while a key
+// is held, so missing a repeat is usually fine.
+func (ir *ReceiverDevice) ReadPulses(buf []time.Duration) int {
+	mask := interrupt.Disable()
+	n := ir.capture.read(buf, time.Since(ir.lastTime) >= frameGap)
+	interrupt.Restore(mask)
+	return n
+}
+
+// Internal pin change interrupt handler for StartCapture
+func (ir *ReceiverDevice) capturePinChange(pin machine.Pin) {
+	now := time.Now()
+	duration := now.Sub(ir.lastTime)
+	ir.lastTime = now
+	ir.capture.edge(duration)
+}
+
 // Internal helper function to reset state machine on protocol failure
 func (ir *ReceiverDevice) resetStateMachine() {
 	ir.data = Data{}
@@ -200,26 +214,12 @@ const (
 )
 
 func (ir *ReceiverDevice) decode() irDecodeError {
-	// Decode cmd and inverse cmd and perform validation check
-	cmd := uint8((ir.data.Code & 0x00ff0000) >> 16)
-	invCmd := uint8((ir.data.Code & 0xff000000) >> 24)
-	if cmd != ^invCmd {
+	data, ok := necData(ir.data.Code)
+	if !ok {
 		// Validation failure. cmd and inverse cmd do not match
 		return irDecodeErrorInverseCheckFail
 	}
-	// cmd validation pass, decode address
-	ir.data.Command = uint16(cmd)
-	addrLow := uint8(ir.data.Code & 0xff)
-	addrHigh := uint8((ir.data.Code & 0xff00) >> 8)
-	if addrHigh == ^addrLow {
-		// addrHigh is inverse of addrLow. This is not a valid 16-bit address in extended NEC coding
-		// since it is indistinguishable from 8-bit address with inverse validation. Use the 8-bit address
-		ir.data.Address = uint16(addrLow)
-	} else {
-		// 16-bit extended NEC address
-		ir.data.Address = (uint16(addrHigh) << 8) | uint16(addrLow)
-	}
-	// Clear repeat flag
-	ir.data.Flags &^= DataFlagIsRepeat
+	// Flags are cleared, which clears the repeat flag
+	ir.data = data
 	return irDecodeErrorNone
 }
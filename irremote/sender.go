@@ -0,0 +1,105 @@
+//go:build tinygo
+
+package irremote
+
+import (
+	"machine"
+	"time"
+)
+
+// PWM is the interface necessary for generating the IR carrier.
+type PWM interface {
+	Configure(config machine.PWMConfig) error
+	Channel(pin machine.Pin) (channel uint8, err error)
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
+// SenderDevice is the device for sending IR commands, through an IR LED
+// driven by a PWM output.
+type SenderDevice struct {
+	pwm       PWM
+	pin       machine.Pin
+	channel   uint8
+	frequency uint32
+	pulses    [MaxPulses]time.Duration
+}
+
+// NewSender returns a new IR sender device on the given pin. Please check the
+// chip documentation which pins can be controlled by the given PWM.
+func NewSender(pwm PWM, pin machine.Pin) SenderDevice {
+	return SenderDevice{pwm: pwm, pin: pin}
+}
+
+// Configure configures the PWM output for the IR LED, which is off
+// afterwards.
+func (s *SenderDevice) Configure() error {
+	err := s.setFrequency(38000)
+	if err != nil {
+		return err
+	}
+	s.channel, err = s.pwm.Channel(s.pin)
+	if err != nil {
+		return err
+	}
+	s.pwm.Set(s.channel, 0)
+	return nil
+}
+
+// Send sends an IR command using the protocol and flags of data. For RC5 and
+// RC6, set DataFlagToggle on every other key press. For NEC, a repeat code is
+// sent when DataFlagIsRepeat is set.
+//
+// Send blocks while sending, which takes up to 80ms. Remotes repeat codes
+// while a key is held, with at least 40ms between codes.
+func (s *SenderDevice) Send(data Data) error {
+	n := data.Protocol.Encode(data, s.pulses[:])
+	return s.SendPulses(s.pulses[:n], data.Protocol.Carrier())
+}
+
+// SendPulses sends raw pulses, for example captured with
+// ReceiverDevice.ReadPulses, with the given carrier frequency in Hz. The
+// pulses alternate between mark (IR on) and space (IR off), starting with a
+// mark.
+func (s *SenderDevice) SendPulses(pulses []time.Duration, frequency uint32) error {
+	err := s.setFrequency(frequency)
+	if err != nil {
+		return err
+	}
+	// A duty cycle of 1/3 is common for IR LEDs, and keeps them from
+	// overheating.
+	on := s.pwm.Top() / 3
+
+	// Wait for the end of every pulse from the start of the code, so that
+	// delays don't add up.
+	end := time.Now()
+	for i, d := range pulses {
+		if i%2 == 0 {
+			s.pwm.Set(s.channel, on)
+		} else {
+			s.pwm.Set(s.channel, 0)
+		}
+		end = end.Add(d)
+		for time.Now().Before(end) {
+		}
+	}
+	s.pwm.Set(s.channel, 0)
+	return nil
+}
+
+func (s *SenderDevice) setFrequency(frequency uint32) error {
+	if frequency == 0 {
+		frequency = 38000
+	}
+	if frequency == s.frequency {
+		return nil
+	}
+	err := s.pwm.Configure(machine.PWMConfig{
+		Period: 1e9 / uint64(frequency),
+	})
+	if err != nil {
+		return err
+	}
+	s.frequency = frequency
+	return nil
+}
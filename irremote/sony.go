@@ -0,0 +1,66 @@
+package irremote
+
+import "time"
+
+// Sony SIRC codes have a 2.4ms leader mark, followed by 12, 15 or 20 bits with
+// the least significant bit first. Every bit is a 600µs space followed by a
+// mark whose duration is the value of the bit. The 7 lowest bits are the
+// command, the others the address.
+// https://www.sbprojects.net/knowledge/ir/sirc.php
+
+const (
+	sonyLeadMark = 2400 * time.Microsecond
+	sonySpace    = 600 * time.Microsecond
+	sonyZero     = 600 * time.Microsecond
+	sonyOne      = 1200 * time.Microsecond
+)
+
+func decodeSony(pulses []time.Duration) (Data, bool) {
+	bits := len(pulses) / 2
+	if len(pulses)%2 != 1 || (bits != 12 && bits != 15 && bits != 20) || !near(pulses[0], sonyLeadMark) {
+		return Data{}, false
+	}
+	var code uint32
+	for i := 0; i < bits; i++ {
+		space, mark := pulses[1+i*2], pulses[2+i*2]
+		if !near(space, sonySpace) {
+			return Data{}, false
+		}
+		switch {
+		case near(mark, sonyOne):
+			code |= 1 << i
+		case !near(mark, sonyZero):
+			return Data{}, false
+		}
+	}
+	return Data{
+		Code:     code,
+		Address:  uint16(code >> 7),
+		Command:  uint16(code & 0x7f),
+		Protocol: ProtocolSony,
+		Bits:     uint8(bits),
+	}, true
+}
+
+func encodeSony(data Data, pulses []time.Duration) int {
+	bits := int(data.Bits)
+	switch {
+	case bits == 12 || bits == 15 || bits == 20:
+	case data.Address <= 0x1f:
+		bits = 12
+	case data.Address <= 0xff:
+		bits = 15
+	default:
+		bits = 20
+	}
+	code := uint32(data.Command&0x7f) | uint32(data.Address)<<7
+	pulses[0] = sonyLeadMark
+	for i := 0; i < bits; i++ {
+		pulses[1+i*2] = sonySpace
+		pulses[2+i*2] = sonyZero
+		if code&(1<<i) != 0 {
+			pulses[2+i*2] = sonyOne
+		}
+	}
+	return 1 + bits*2
+}
@@ -0,0 +1,204 @@
+package is31fl3731
+
+import (
+	"fmt"
+	"time"
+
+	"tinygo.org/x/drivers/internal/legacy"
+)
+
+// AutoPlay is the configuration of the auto frame play mode, in which the chip
+// plays an animation of up to 8 frames by itself.
+type AutoPlay struct {
+	// First frame to play (0-7).
+	StartFrame uint8
+
+	// Number of frames to play (1-8). Zero plays all 8 frames.
+	Frames uint8
+
+	// Number of times to play the animation (1-7). Zero loops endlessly.
+	Loops uint8
+
+	// Time each frame is shown, from 11ms to 704ms in steps of 11ms. The
+	// default is 99ms.
+	FrameDelay time.Duration
+}
+
+// Breath is the configuration of the breathing effect, which fades the LEDs in
+// and out.
+type Breath struct {
+	// Fade in and fade out time, from 26ms to 3.3s. The chip only supports
+	// 26ms times a power of two, other values are rounded up.
+	FadeIn  time.Duration
+	FadeOut time.Duration
+
+	// Time the LEDs stay off between fading out and fading in again, from 3.5ms
+	// to 448ms. The chip only supports 3.5ms times a power of two, other values
+	// are rounded up.
+	Extinguish time.Duration
+}
+
+// Audio is the configuration of the audio input, used by audio sync and the
+// audio frame play mode.
+type Audio struct {
+	// Enable automatic gain control.
+	AGC bool
+
+	// Use the fast AGC mode instead of the slow mode.
+	FastAGC bool
+
+	// Gain of the audio input in dB, from 0dB to 21dB in steps of 3dB.
+	Gain uint8
+
+	// Time between audio samples, from 46µs to 11.8ms in steps of 46µs. The
+	// default is 11.8ms.
+	SampleRate time.Duration
+}
+
+// UploadFrame writes the PWM values [0-255] of all LEDs of the frame. There
+// are 144 LEDs per frame, in the same order as DrawPixelIndex.
+func (d *Device) UploadFrame(frame uint8, pwm []uint8) (err error) {
+	if frame > FRAME_7 {
+		return fmt.Errorf("frame %d is out of valid range [0-7]", frame)
+	}
+	if len(pwm) > LED_COUNT {
+		return fmt.Errorf("frame data is too long: %d values, maximum is %d", len(pwm), LED_COUNT)
+	}
+
+	err = d.selectCommand(frame)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(pwm); i += 24 {
+		end := min(i+24, len(pwm))
+		err = legacy.WriteRegister(d.bus, d.Address, LED_PWM_OFFSET+uint8(i), pwm[i:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartAutoPlay plays the frames as an animation, see AutoPlay.
+func (d *Device) StartAutoPlay(cfg AutoPlay) (err error) {
+	if cfg.StartFrame > FRAME_7 || cfg.Frames > 8 || cfg.Loops > 7 {
+		return fmt.Errorf("invalid auto play configuration")
+	}
+
+	delay := cfg.FrameDelay
+	if delay == 0 {
+		delay = 99 * time.Millisecond
+	}
+	steps := (delay + 11*time.Millisecond/2) / (11 * time.Millisecond)
+	steps = max(1, min(steps, 64))
+
+	err = d.writeFunctionRegister(SET_AUTOPLAY_CONTROL_1, []byte{cfg.Loops<<4 | cfg.Frames&0x07})
+	if err != nil {
+		return err
+	}
+
+	err = d.writeFunctionRegister(SET_AUTOPLAY_CONTROL_2, []byte{uint8(steps) & 0x3F})
+	if err != nil {
+		return err
+	}
+
+	return d.writeFunctionRegister(SET_DISPLAY_MODE, []byte{DISPLAY_MODE_AUTO_PLAY | cfg.StartFrame})
+}
+
+// StartAudioPlay plays the frames starting at startFrame depending on the audio
+// level: the louder the audio input, the higher the frame. See ConfigureAudio.
+func (d *Device) StartAudioPlay(startFrame uint8) (err error) {
+	if startFrame > FRAME_7 {
+		return fmt.Errorf("frame %d is out of valid range [0-7]", startFrame)
+	}
+
+	return d.writeFunctionRegister(SET_DISPLAY_MODE, []byte{DISPLAY_MODE_AUDIO_PLAY | startFrame})
+}
+
+// StopAutoPlay switches back to picture mode, in which the active frame is
+// shown (see SetActiveFrame).
+func (d *Device) StopAutoPlay() (err error) {
+	return d.writeFunctionRegister(SET_DISPLAY_MODE, []byte{DISPLAY_MODE_PICTURE})
+}
+
+// AutoPlayState returns the frame that is currently shown in auto play mode,
+// and whether all loops of the animation have finished.
+func (d *Device) AutoPlayState() (frame uint8, finished bool, err error) {
+	err = d.selectCommand(FUNCTION)
+	if err != nil {
+		return 0, false, err
+	}
+
+	data := []byte{0}
+	err = legacy.ReadRegister(d.bus, d.Address, FRAME_STATE, data)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return data[0] & 0x07, data[0]&FRAME_STATE_INT != 0, nil
+}
+
+// EnableBreath enables the breathing effect, see Breath.
+func (d *Device) EnableBreath(cfg Breath) (err error) {
+	fadeIn := powerOfTwoSteps(cfg.FadeIn, 26*time.Millisecond)
+	fadeOut := powerOfTwoSteps(cfg.FadeOut, 26*time.Millisecond)
+	extinguish := powerOfTwoSteps(cfg.Extinguish, 3500*time.Microsecond)
+
+	err = d.writeFunctionRegister(SET_BREATH_CONTROL_1, []byte{fadeOut<<4 | fadeIn})
+	if err != nil {
+		return err
+	}
+
+	return d.writeFunctionRegister(SET_BREATH_CONTROL_2, []byte{BREATH_ENABLE | extinguish})
+}
+
+// DisableBreath disables the breathing effect.
+func (d *Device) DisableBreath() (err error) {
+	return d.writeFunctionRegister(SET_BREATH_CONTROL_2, []byte{0})
+}
+
+// SetAudioSync enables or disables audio sync, which modulates the intensity of
+// all LEDs with the audio input.
+func (d *Device) SetAudioSync(enabled bool) (err error) {
+	if enabled {
+		return d.writeFunctionRegister(SET_AUDIOSYNC, []byte{AUDIOSYNC_ON})
+	}
+
+	return d.writeFunctionRegister(SET_AUDIOSYNC, []byte{AUDIOSYNC_OFF})
+}
+
+// ConfigureAudio configures the audio input, see Audio.
+func (d *Device) ConfigureAudio(cfg Audio) (err error) {
+	agc := min(cfg.Gain/3, 7)
+	if cfg.AGC {
+		agc |= AGC_ENABLE
+	}
+	if cfg.FastAGC {
+		agc |= AGC_FAST_MODE
+	}
+
+	err = d.writeFunctionRegister(SET_AGC_CONTROL, []byte{agc})
+	if err != nil {
+		return err
+	}
+
+	// A value of 0 means 256 steps.
+	steps := (cfg.SampleRate + 46*time.Microsecond/2) / (46 * time.Microsecond)
+	if steps <= 0 || steps >= 256 {
+		steps = 0
+	}
+
+	return d.writeFunctionRegister(SET_AUDIO_ADC_RATE, []byte{uint8(steps)})
+}
+
+// powerOfTwoSteps returns the smallest exponent (0-7) for which unit*2^exponent
+// is at least t.
+func powerOfTwoSteps(t, unit time.Duration) uint8 {
+	exponent := uint8(0)
+	for exponent < 7 && unit<<exponent < t {
+		exponent++
+	}
+	return exponent
+}
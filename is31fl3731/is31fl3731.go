@@ -17,6 +17,7 @@ package is31fl3731
 
 import (
 	"fmt"
+	"image/color"
 	"time"
 
 	"tinygo.org/x/drivers"
@@ -31,6 +32,11 @@ type Device struct {
 	// Currently selected command register (one of the frame registers or the
 	// function register)
 	selectedCommand uint8
+
+	// Framebuffer for drivers.Displayer, and the frame it is written to
+	buffer    [LED_COUNT]uint8
+	drawFrame uint8
+	rotation  drivers.Rotation
 }
 
 // Configure chip for operating as a LED matrix display
@@ -49,8 +55,8 @@ func (d *Device) Configure() (err error) {
 		return fmt.Errorf("failed to wake up: %w", err)
 	}
 
-	// Set display to a picture mode ("auto frame play mode" and "audio frame play
-	// mode" are not supported in this version of the driver)
+	// Set display to a picture mode (see StartAutoPlay and StartAudioPlay for the
+	// other modes)
 	err = d.writeFunctionRegister(SET_DISPLAY_MODE, []byte{DISPLAY_MODE_PICTURE})
 	if err != nil {
 		return fmt.Errorf("failed to switch to a picture move: %w", err)
@@ -197,6 +203,75 @@ func (d *Device) DrawPixelXY(frame, x, y, value uint8) (err error) {
 	return d.setPixelPWD(frame, 16*x+y, value)
 }
 
+// Size returns the size of the raw 16x9 LED matrix, in the same layout as
+// DrawPixelXY: 9 columns of 16 LEDs. The size takes rotation into account.
+func (d *Device) Size() (x, y int16) {
+	return d.rotation.Size(9, 16)
+}
+
+// SetPixel modifies the internal buffer in a single pixel. The brightness of
+// the color is used as the PWM value of the LED.
+func (d *Device) SetPixel(x, y int16, c color.RGBA) {
+	x, y, ok := d.position(x, y, 9, 16)
+	if !ok {
+		return
+	}
+	d.buffer[16*x+y] = brightness(c)
+}
+
+// Rotation returns the currently configured rotation.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the framebuffer (clock-wise), as used by
+// SetPixel and Size. DrawPixelXY and DrawPixelIndex address the LEDs directly
+// and ignore it.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
+}
+
+// position converts a position on the rotated display to a position on the
+// display without rotation, which has the given size.
+func (d *Device) position(x, y, width, height int16) (int16, int16, bool) {
+	w, h := d.rotation.Size(width, height)
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return 0, 0, false
+	}
+	x, y = d.rotation.Transform(x, y, width, height)
+	return x, y, true
+}
+
+// Display writes the internal buffer to the draw frame (see SetDrawFrame). The
+// frame is only visible when it is the active frame (see SetActiveFrame), or
+// when it is part of an animation.
+func (d *Device) Display() error {
+	return d.UploadFrame(d.drawFrame, d.buffer[:])
+}
+
+// SetDrawFrame sets the frame that Display writes to. The default is frame 0.
+// Drawing to a frame that isn't visible and then making it the active frame
+// avoids flickering, and can be used to prepare the frames of an animation.
+func (d *Device) SetDrawFrame(frame uint8) (err error) {
+	if frame > FRAME_7 {
+		return fmt.Errorf("frame %d is out of valid range [0-7]", frame)
+	}
+
+	d.drawFrame = frame
+	return nil
+}
+
+// ClearBuffer clears the internal buffer.
+func (d *Device) ClearBuffer() {
+	d.buffer = [LED_COUNT]uint8{}
+}
+
+// brightness converts a color to a PWM value, using the luminance of the color.
+func brightness(c color.RGBA) uint8 {
+	return uint8((uint32(c.R)*77 + uint32(c.G)*150 + uint32(c.B)*29) >> 8)
+}
+
 // New creates a raw driver w/o any preset board layout.
 // Addresses:
 // - 0x74 (AD pin connected to GND)
@@ -2,6 +2,7 @@ package is31fl3731
 
 import (
 	"fmt"
+	"image/color"
 
 	"tinygo.org/x/drivers"
 	"tinygo.org/x/drivers/internal/legacy"
@@ -73,22 +74,38 @@ func (d *DeviceAdafruitCharlieWing15x7) enableLEDs() (err error) {
 // DrawPixelXY draws a single pixel on the selected frame by its XY coordinates
 // with provided PWM value [0-255]
 func (d *DeviceAdafruitCharlieWing15x7) DrawPixelXY(frame, x, y, value uint8) (err error) {
-	var index uint8
-
 	if x >= 15 {
 		return fmt.Errorf("invalid value: X is out of range [0, 15]")
 	} else if y >= 7 {
 		return fmt.Errorf("invalid value: Y is out of range [0, 7]")
 	}
 
+	return d.setPixelPWD(frame, charlieWingIndex(x, y), value)
+}
+
+// charlieWingIndex returns the LED index of a pixel on the CharlieWing.
+func charlieWingIndex(x, y uint8) uint8 {
 	// Board is one pixel shorter (7 vs 8 supported pixels)
 	if x < 8 {
-		index = 16*x + y + 1
-	} else {
-		index = 16*(16-x) - y - 1 - 1
+		return 16*x + y + 1
 	}
+	return 16*(16-x) - y - 1 - 1
+}
 
-	return d.setPixelPWD(frame, index, value)
+// Size returns the size of the CharlieWing: 15x7 pixels, or 7x15 pixels when
+// rotated by 90 or 270 degrees.
+func (d *DeviceAdafruitCharlieWing15x7) Size() (x, y int16) {
+	return d.rotation.Size(15, 7)
+}
+
+// SetPixel modifies the internal buffer in a single pixel. The brightness of
+// the color is used as the PWM value of the LED.
+func (d *DeviceAdafruitCharlieWing15x7) SetPixel(x, y int16, c color.RGBA) {
+	x, y, ok := d.position(x, y, 15, 7)
+	if !ok {
+		return
+	}
+	d.buffer[charlieWingIndex(uint8(x), uint8(y))] = brightness(c)
 }
 
 // NewAdafruitCharlieWing15x7 creates a new driver with Adafruit 15x7
@@ -0,0 +1,21 @@
+package is31fl3731
+
+import (
+	"testing"
+
+	"tinygo.org/x/drivers/tester"
+)
+
+func TestRotation(t *testing.T) {
+	d := New(nil, 0x74)
+	tester.TestRotation(t, &d, func(x, y int16) bool {
+		return d.buffer[16*x+y] != 0
+	})
+}
+
+func TestRotationCharlieWing(t *testing.T) {
+	d := NewAdafruitCharlieWing15x7(nil, 0x74)
+	tester.TestRotation(t, &d, func(x, y int16) bool {
+		return d.buffer[charlieWingIndex(uint8(x), uint8(y))] != 0
+	})
+}
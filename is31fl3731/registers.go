@@ -29,13 +29,33 @@ const (
 	FUNCTION uint8 = 0x0B
 
 	// Configuration:
-	SET_DISPLAY_MODE uint8 = 0x00
-	SET_ACTIVE_FRAME uint8 = 0x01
-	SET_AUDIOSYNC    uint8 = 0x06
-	SET_SHUTDOWN     uint8 = 0x0A
+	SET_DISPLAY_MODE       uint8 = 0x00
+	SET_ACTIVE_FRAME       uint8 = 0x01
+	SET_AUTOPLAY_CONTROL_1 uint8 = 0x02
+	SET_AUTOPLAY_CONTROL_2 uint8 = 0x03
+	SET_DISPLAY_OPTION     uint8 = 0x05
+	SET_AUDIOSYNC          uint8 = 0x06
+	FRAME_STATE            uint8 = 0x07
+	SET_BREATH_CONTROL_1   uint8 = 0x08
+	SET_BREATH_CONTROL_2   uint8 = 0x09
+	SET_SHUTDOWN           uint8 = 0x0A
+	SET_AGC_CONTROL        uint8 = 0x0B
+	SET_AUDIO_ADC_RATE     uint8 = 0x0C
 
 	// Configuration: display mode
-	DISPLAY_MODE_PICTURE uint8 = 0x00
+	DISPLAY_MODE_PICTURE    uint8 = 0x00
+	DISPLAY_MODE_AUTO_PLAY  uint8 = 0x08
+	DISPLAY_MODE_AUDIO_PLAY uint8 = 0x10
+
+	// Configuration: breath control 2
+	BREATH_ENABLE uint8 = 0x10
+
+	// Configuration: AGC control
+	AGC_ENABLE    uint8 = 0x08
+	AGC_FAST_MODE uint8 = 0x10
+
+	// Configuration: frame state
+	FRAME_STATE_INT uint8 = 0x10
 
 	// Configuration: audiosync (enable audio signal to modulate the intensity of
 	// the matrix)
@@ -48,5 +68,9 @@ const (
 
 	// Frame LEDs
 	LED_CONTROL_OFFSET uint8 = 0x00 // to on/off each LED
+	LED_BLINK_OFFSET   uint8 = 0x12 // to enable blinking for each LED
 	LED_PWM_OFFSET     uint8 = 0x24 // to set PWM (0-255) for each LED
+
+	// Number of LEDs (and PWM registers) per frame
+	LED_COUNT = 144
 )
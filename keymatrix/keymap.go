@@ -0,0 +1,117 @@
+package keymatrix
+
+// Code is the code of a key in a keymap. Its meaning is up to the application,
+// for example a character or a HID usage ID. Codes from 0xFE00 and up are
+// reserved for layer keys.
+type Code uint16
+
+const (
+	// None is a key without code.
+	None Code = 0
+
+	// Transparent is a key that has the code of the first active layer below
+	// it.
+	Transparent Code = 0xFFFF
+
+	codeMomentary Code = 0xFE00 // + layer
+	codeToggle    Code = 0xFF00 // + layer
+	codeLayerMask Code = 0x00FF
+)
+
+// MaxLayers is the maximum number of layers of a keymap.
+const MaxLayers = 32
+
+// Momentary returns the code of a key that activates a layer while it is held.
+func Momentary(layer int) Code {
+	return codeMomentary + Code(layer)&codeLayerMask
+}
+
+// Toggle returns the code of a key that switches a layer on or off.
+func Toggle(layer int) Code {
+	return codeToggle + Code(layer)&codeLayerMask
+}
+
+// Keymap maps the keys of a matrix to codes, with multiple layers. Layer 0 is
+// always active. The codes of a key are looked up in the active layers from
+// the highest to the lowest, skipping transparent keys.
+type Keymap struct {
+	columns int
+	layers  [][]Code
+	active  uint32 // layers switched on by toggle keys or SetLayer
+	held    [MaxLayers]uint8
+}
+
+// NewKeymap returns a keymap for a matrix with the given number of columns.
+// Every layer has a code for every key, row by row. Layers that are shorter
+// than the matrix are transparent for the missing keys.
+func NewKeymap(columns int, layers ...[]Code) *Keymap {
+	if len(layers) > MaxLayers {
+		layers = layers[:MaxLayers]
+	}
+	return &Keymap{
+		columns: columns,
+		layers:  layers,
+		active:  1,
+	}
+}
+
+// Lookup returns the code of a key in the active layers.
+func (k *Keymap) Lookup(row, column int) Code {
+	i := row*k.columns + column
+	layers := k.Layers()
+	for layer := len(k.layers) - 1; layer >= 0; layer-- {
+		if layers&(1<<layer) == 0 || i >= len(k.layers[layer]) {
+			continue
+		}
+		if code := k.layers[layer][i]; code != Transparent {
+			return code
+		}
+	}
+	return None
+}
+
+// Layers returns the active layers, with a bit set for every active layer.
+func (k *Keymap) Layers() uint32 {
+	layers := k.active | 1
+	for layer, count := range k.held {
+		if count > 0 {
+			layers |= 1 << layer
+		}
+	}
+	return layers
+}
+
+// SetLayer switches a layer on or off, like a toggle key.
+func (k *Keymap) SetLayer(layer int, active bool) {
+	if layer <= 0 || layer >= MaxLayers {
+		return
+	}
+	if active {
+		k.active |= 1 << layer
+	} else {
+		k.active &^= 1 << layer
+	}
+}
+
+// handle updates the layers for a layer key, and returns whether the code was
+// a layer key.
+func (k *Keymap) handle(code Code, pressed bool) bool {
+	layer := int(code & codeLayerMask)
+	switch code &^ codeLayerMask {
+	case codeMomentary:
+		if layer < MaxLayers {
+			if pressed {
+				k.held[layer]++
+			} else if k.held[layer] > 0 {
+				k.held[layer]--
+			}
+		}
+		return true
+	case codeToggle:
+		if pressed && layer < MaxLayers {
+			k.SetLayer(layer, k.active&(1<<layer) == 0)
+		}
+		return true
+	}
+	return false
+}
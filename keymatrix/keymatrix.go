@@ -0,0 +1,221 @@
+// Package keymatrix scans keyboard matrices of any size, such as keypads and
+// macro pads.
+//
+// The keys of a matrix are wired between a row line and a column line. The
+// scanner selects every row in turn by driving it low, and reads which columns
+// are pulled low by pressed keys. The rows can be driven through GPIO pins, an
+// mcp23017 I/O expander or a shift register, and the columns are read through
+// GPIO pins or an mcp23017.
+//
+// With a diode in series with every key, any combination of keys can be
+// detected. Without diodes, three pressed keys on the corners of a rectangle
+// make the fourth corner appear pressed as well. The scanner detects this and
+// ignores the affected rows until the ambiguity is gone.
+package keymatrix // import "tinygo.org/x/drivers/keymatrix"
+
+import (
+	"errors"
+	"math/bits"
+	"time"
+)
+
+var errSize = errors.New("keymatrix: invalid matrix size, at most 32 columns are supported")
+
+// Rows drives the rows of a matrix.
+type Rows interface {
+	// Select drives the given row active and all other rows inactive. A
+	// negative row makes all rows inactive.
+	Select(row int) error
+}
+
+// Columns reads the columns of a matrix.
+type Columns interface {
+	// Read returns a bit set for every column that is connected to the
+	// selected row by a pressed key.
+	Read() (uint32, error)
+}
+
+// Config is the configuration of a Scanner.
+type Config struct {
+	// Size of the matrix.
+	Rows, Columns int
+
+	// Diodes must be set when every key has a diode, which allows any number
+	// of keys to be pressed at the same time. Without diodes, ghost keys are
+	// detected and ignored.
+	Diodes bool
+
+	// Time to wait after selecting a row before reading the columns. The
+	// default of 0 doesn't wait, which is fine for GPIO pins with short
+	// wires.
+	Settle time.Duration
+
+	// Number of scans a key must be in a new state before the change is
+	// accepted. The default is 3, which is good for scanning every 5ms.
+	Debounce int
+
+	// Keymap translates key positions to codes. It is optional, without it
+	// all events have code None.
+	Keymap *Keymap
+}
+
+// Event is a change of a key.
+type Event struct {
+	Row, Column int
+	Pressed     bool
+
+	// Code of the key in the keymap. It is the same for the release as for
+	// the press of a key, even when the layers changed in between.
+	Code Code
+}
+
+// Scanner scans a key matrix.
+type Scanner struct {
+	rows     Rows
+	columns  Columns
+	config   Config
+	handler  func(Event)
+	raw      []uint32 // state of the last scan
+	state    []uint32 // debounced state
+	counts   []uint8  // debounce count per key
+	codes    []Code   // codes of the pressed keys
+	ghosting bool
+}
+
+// New returns a scanner for a matrix, which sends events to handler. The row
+// and column lines must be configured already.
+func New(rows Rows, columns Columns, config Config, handler func(Event)) (*Scanner, error) {
+	if config.Rows <= 0 || config.Columns <= 0 || config.Columns > 32 {
+		return nil, errSize
+	}
+	if config.Debounce <= 0 {
+		config.Debounce = 3
+	}
+	keys := config.Rows * config.Columns
+	return &Scanner{
+		rows:    rows,
+		columns: columns,
+		config:  config,
+		handler: handler,
+		raw:     make([]uint32, config.Rows),
+		state:   make([]uint32, config.Rows),
+		counts:  make([]uint8, keys),
+		codes:   make([]Code, keys),
+	}, nil
+}
+
+// Scan scans the matrix once and sends the events for the keys that changed.
+// Call it regularly, for example every 5ms.
+func (s *Scanner) Scan() error {
+	columnMask := uint32(1)<<s.config.Columns - 1
+	for row := range s.raw {
+		err := s.rows.Select(row)
+		if err != nil {
+			return err
+		}
+		if s.config.Settle > 0 {
+			time.Sleep(s.config.Settle)
+		}
+		columns, err := s.columns.Read()
+		if err != nil {
+			return err
+		}
+		s.raw[row] = columns & columnMask
+	}
+	err := s.rows.Select(-1)
+	if err != nil {
+		return err
+	}
+
+	s.ghosting = false
+	for row, columns := range s.raw {
+		if !s.config.Diodes && s.hasGhost(row) {
+			// Keep the state of the row until the keys can be told apart.
+			s.ghosting = true
+			continue
+		}
+		for column := 0; column < s.config.Columns; column++ {
+			bit := uint32(1) << column
+			i := row*s.config.Columns + column
+			if (columns^s.state[row])&bit == 0 {
+				s.counts[i] = 0
+				continue
+			}
+			s.counts[i]++
+			if int(s.counts[i]) >= s.config.Debounce {
+				s.counts[i] = 0
+				s.state[row] ^= bit
+				s.change(row, column, columns&bit != 0)
+			}
+		}
+	}
+	return nil
+}
+
+// hasGhost returns whether the keys pressed in a row form a rectangle with the
+// keys of another row. One of the four keys could then be a ghost key, caused
+// by the three others.
+func (s *Scanner) hasGhost(row int) bool {
+	columns := s.raw[row]
+	if bits.OnesCount32(columns) < 2 {
+		return false
+	}
+	for other, otherColumns := range s.raw {
+		if other != row && bits.OnesCount32(columns&otherColumns) >= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// change handles a key that was pressed or released.
+func (s *Scanner) change(row, column int, pressed bool) {
+	i := row*s.config.Columns + column
+	code := s.codes[i]
+	if pressed {
+		code = None
+		if s.config.Keymap != nil {
+			code = s.config.Keymap.Lookup(row, column)
+		}
+		s.codes[i] = code
+	}
+	if s.config.Keymap != nil && s.config.Keymap.handle(code, pressed) {
+		// Layer keys are handled by the keymap.
+		return
+	}
+	if s.handler != nil {
+		s.handler(Event{Row: row, Column: column, Pressed: pressed, Code: code})
+	}
+}
+
+// Pressed returns whether a key is pressed, after debouncing.
+func (s *Scanner) Pressed(row, column int) bool {
+	if row < 0 || row >= s.config.Rows || column < 0 || column >= s.config.Columns {
+		return false
+	}
+	return s.state[row]&(1<<column) != 0
+}
+
+// Ghosting returns whether ghost keys were detected in the last scan, in which
+// case some keys were ignored.
+func (s *Scanner) Ghosting() bool {
+	return s.ghosting
+}
+
+// ReadKeys scans the matrix and returns the debounced state of the first 32
+// keys, row by row, so that the scanner can be used as an input.Source.
+func (s *Scanner) ReadKeys() (uint32, error) {
+	err := s.Scan()
+	if err != nil {
+		return 0, err
+	}
+	var keys uint32
+	for row := 0; row < s.config.Rows; row++ {
+		shift := row * s.config.Columns
+		if shift >= 32 {
+			break
+		}
+		keys |= s.state[row] << shift
+	}
+	return keys, nil
+}
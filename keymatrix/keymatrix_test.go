@@ -0,0 +1,243 @@
+package keymatrix
+
+import (
+	"testing"
+
+	"tinygo.org/x/drivers/mcp23017"
+)
+
+// fakeMatrix simulates a matrix with or without diodes.
+type fakeMatrix struct {
+	pressed  [][]bool
+	diodes   bool
+	selected int
+}
+
+func newFakeMatrix(rows, columns int, diodes bool) *fakeMatrix {
+	m := &fakeMatrix{pressed: make([][]bool, rows), diodes: diodes}
+	for i := range m.pressed {
+		m.pressed[i] = make([]bool, columns)
+	}
+	return m
+}
+
+func (m *fakeMatrix) Select(row int) error {
+	m.selected = row
+	return nil
+}
+
+// Read returns the columns connected to the selected row. Without diodes,
+// current can also flow backwards through pressed keys via other rows.
+func (m *fakeMatrix) Read() (uint32, error) {
+	if m.selected < 0 {
+		return 0, nil
+	}
+	var columns uint32
+	rows := []int{m.selected}
+	visited := map[int]bool{m.selected: true}
+	for len(rows) > 0 {
+		row := rows[0]
+		rows = rows[1:]
+		for column, pressed := range m.pressed[row] {
+			if !pressed || columns&(1<<column) != 0 {
+				continue
+			}
+			columns |= 1 << column
+			if m.diodes {
+				continue
+			}
+			for other := range m.pressed {
+				if m.pressed[other][column] && !visited[other] {
+					visited[other] = true
+					rows = append(rows, other)
+				}
+			}
+		}
+	}
+	return columns, nil
+}
+
+func newScanner(t *testing.T, m *fakeMatrix, keymap *Keymap) (*Scanner, *[]Event) {
+	events := &[]Event{}
+	s, err := New(m, m, Config{
+		Rows:     len(m.pressed),
+		Columns:  len(m.pressed[0]),
+		Diodes:   m.diodes,
+		Debounce: 2,
+		Keymap:   keymap,
+	}, func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, events
+}
+
+// scan scans the matrix until the debounce count is reached, and returns the
+// events.
+func scan(t *testing.T, s *Scanner, events *[]Event) []Event {
+	*events = nil
+	for i := 0; i < 2; i++ {
+		if err := s.Scan(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return *events
+}
+
+func TestDebounce(t *testing.T) {
+	m := newFakeMatrix(2, 3, true)
+	s, events := newScanner(t, m, nil)
+
+	m.pressed[1][2] = true
+	s.Scan()
+	m.pressed[1][2] = false
+	s.Scan()
+	m.pressed[1][2] = true
+	s.Scan()
+	if len(*events) != 0 {
+		t.Errorf("unexpected events while bouncing: %v", *events)
+	}
+	s.Scan()
+	if len(*events) != 1 || (*events)[0] != (Event{Row: 1, Column: 2, Pressed: true}) {
+		t.Errorf("expected press of 1,2, got %v", *events)
+	}
+	if !s.Pressed(1, 2) || s.Pressed(0, 2) {
+		t.Error("unexpected key state")
+	}
+	if keys, _ := s.ReadKeys(); keys != 1<<5 {
+		t.Errorf("expected key 5 in input state, got %b", keys)
+	}
+}
+
+func TestGhosting(t *testing.T) {
+	for _, diodes := range []bool{false, true} {
+		m := newFakeMatrix(2, 2, diodes)
+		s, events := newScanner(t, m, nil)
+
+		m.pressed[0][0] = true
+		m.pressed[0][1] = true
+		if e := scan(t, s, events); len(e) != 2 {
+			t.Errorf("diodes %v: expected two presses, got %v", diodes, e)
+		}
+
+		// The third key causes a ghost key without diodes.
+		m.pressed[1][0] = true
+		e := scan(t, s, events)
+		if diodes {
+			if len(e) != 1 || e[0] != (Event{Row: 1, Column: 0, Pressed: true}) || s.Ghosting() {
+				t.Errorf("diodes: expected press of 1,0, got %v", e)
+			}
+			continue
+		}
+		if len(e) != 0 || !s.Ghosting() {
+			t.Errorf("expected ghosting to be detected, got %v", e)
+		}
+
+		// The third key is detected once the ambiguity is gone.
+		m.pressed[0][1] = false
+		e = scan(t, s, events)
+		expected := []Event{{Row: 0, Column: 1}, {Row: 1, Column: 0, Pressed: true}}
+		if len(e) != 2 || e[0] != expected[0] || e[1] != expected[1] || s.Ghosting() {
+			t.Errorf("expected %v, got %v", expected, e)
+		}
+	}
+}
+
+func TestKeymap(t *testing.T) {
+	const T = Transparent
+	keymap := NewKeymap(2,
+		[]Code{'a', 'b', 'c', Momentary(1)},
+		[]Code{'A', T, Toggle(2), T},
+		[]Code{'1', '2'},
+	)
+	m := newFakeMatrix(2, 2, true)
+	s, events := newScanner(t, m, keymap)
+
+	press := func(row, column int, pressed bool) []Event {
+		m.pressed[row][column] = pressed
+		return scan(t, s, events)
+	}
+	expect := func(e []Event, code Code, pressed bool) {
+		t.Helper()
+		if len(e) != 1 || e[0].Code != code || e[0].Pressed != pressed {
+			t.Errorf("expected code %c (pressed %v), got %v", rune(code), pressed, e)
+		}
+	}
+
+	expect(press(0, 0, true), 'a', true)
+	if e := press(1, 1, true); len(e) != 0 || keymap.Layers() != 0b11 {
+		t.Errorf("expected layer key to activate layer 1, got %v, layers %b", e, keymap.Layers())
+	}
+	// The release of a key has the code of its press.
+	expect(press(0, 0, false), 'a', false)
+	expect(press(0, 0, true), 'A', true)
+	expect(press(0, 1, true), 'b', true) // transparent
+
+	// Toggle layer 2 from layer 1.
+	press(1, 0, true)
+	press(1, 0, false)
+	press(1, 1, false)
+	if keymap.Layers() != 0b101 {
+		t.Errorf("expected layers 0 and 2, got %b", keymap.Layers())
+	}
+	press(0, 1, false)
+	expect(press(0, 1, true), '2', true)
+	expect(press(1, 0, true), 'c', true) // missing in layer 2
+
+	keymap.SetLayer(2, false)
+	expect(press(0, 1, false), '2', false)
+	expect(press(0, 1, true), 'b', true)
+}
+
+type fakePin struct {
+	value bool
+}
+
+func (p *fakePin) Set(value bool) {
+	p.value = value
+}
+
+func (p *fakePin) Get() bool {
+	return p.value
+}
+
+type fakeExpander struct {
+	pins mcp23017.Pins
+}
+
+func (e *fakeExpander) SetPins(pins, mask mcp23017.Pins) error {
+	e.pins = e.pins&^mask | pins&mask
+	return nil
+}
+
+func (e *fakeExpander) GetPins() (mcp23017.Pins, error) {
+	return e.pins, nil
+}
+
+func TestLines(t *testing.T) {
+	pins := []*fakePin{{}, {}, {}}
+	rows := PinRows(pins...)
+	rows.Select(1)
+	if !pins[0].value || pins[1].value || !pins[2].value {
+		t.Error("expected only row 1 to be low")
+	}
+	columns := PinColumns(pins...)
+	if c, _ := columns.Read(); c != 0b010 {
+		t.Errorf("expected column 1 to be active, got %b", c)
+	}
+
+	expander := &fakeExpander{pins: 0xFFFF}
+	rows = ExpanderRows(expander, 8, 9)
+	rows.Select(1)
+	if expander.pins != 0xFDFF {
+		t.Errorf("expected only pin 9 to be low, got %x", expander.pins)
+	}
+	columns = ExpanderColumns(expander, 0, 9, 10)
+	if c, _ := columns.Read(); c != 0b010 {
+		t.Errorf("expected column 1 to be active, got %b", c)
+	}
+	rows.Select(-1)
+	if expander.pins != 0xFFFF {
+		t.Errorf("expected all pins to be high, got %x", expander.pins)
+	}
+}
@@ -0,0 +1,127 @@
+package keymatrix
+
+import "tinygo.org/x/drivers/mcp23017"
+
+// Output is a digital output, such as a machine.Pin configured as output or a
+// shiftregister.ShiftPin.
+type Output interface {
+	Set(value bool)
+}
+
+// Input is a digital input, such as a machine.Pin configured as input with a
+// pull-up resistor.
+type Input interface {
+	Get() bool
+}
+
+type pinRows[P Output] []P
+
+// PinRows returns rows driven by output pins. The selected row is driven low,
+// the other rows high.
+func PinRows[P Output](pins ...P) Rows {
+	return pinRows[P](pins)
+}
+
+func (pins pinRows[P]) Select(row int) error {
+	for i, pin := range pins {
+		pin.Set(i != row)
+	}
+	return nil
+}
+
+type pinColumns[P Input] []P
+
+// PinColumns returns columns read through input pins with pull-up resistors.
+// A column is active when its pin is low.
+func PinColumns[P Input](pins ...P) Columns {
+	return pinColumns[P](pins)
+}
+
+func (pins pinColumns[P]) Read() (uint32, error) {
+	var columns uint32
+	for i, pin := range pins {
+		if !pin.Get() {
+			columns |= 1 << i
+		}
+	}
+	return columns, nil
+}
+
+type shiftRegisterRows struct {
+	register interface{ WriteMask(mask uint32) }
+	rows     int
+}
+
+// ShiftRegisterRows returns rows driven by the outputs of a shift register,
+// such as a shiftregister.Device, starting at its first output. The selected
+// row is driven low, the other outputs high.
+func ShiftRegisterRows(register interface{ WriteMask(mask uint32) }, rows int) Rows {
+	return &shiftRegisterRows{register: register, rows: rows}
+}
+
+func (r *shiftRegisterRows) Select(row int) error {
+	mask := ^uint32(0)
+	if row >= 0 && row < r.rows {
+		mask &^= 1 << row
+	}
+	r.register.WriteMask(mask)
+	return nil
+}
+
+type expanderRows struct {
+	expander interface {
+		SetPins(pins, mask mcp23017.Pins) error
+	}
+	pins []int
+	mask mcp23017.Pins
+}
+
+// ExpanderRows returns rows driven by pins of an mcp23017.Device, configured
+// as outputs. The selected row is driven low, the other rows high.
+func ExpanderRows(expander interface {
+	SetPins(pins, mask mcp23017.Pins) error
+}, pins ...int) Rows {
+	r := &expanderRows{expander: expander, pins: pins}
+	for _, pin := range pins {
+		r.mask.High(pin)
+	}
+	return r
+}
+
+func (r *expanderRows) Select(row int) error {
+	pins := r.mask
+	if row >= 0 && row < len(r.pins) {
+		pins.Low(r.pins[row])
+	}
+	return r.expander.SetPins(pins, r.mask)
+}
+
+type expanderColumns struct {
+	expander interface {
+		GetPins() (mcp23017.Pins, error)
+	}
+	pins []int
+}
+
+// ExpanderColumns returns columns read through pins of an mcp23017.Device,
+// configured as inputs with pull-up resistors. A column is active when its pin
+// is low.
+func ExpanderColumns(expander interface {
+	GetPins() (mcp23017.Pins, error)
+}, pins ...int) Columns {
+	return &expanderColumns{expander: expander, pins: pins}
+}
+
+func (c *expanderColumns) Read() (uint32, error) {
+	pins, err := c.expander.GetPins()
+	if err != nil {
+		return 0, err
+	}
+	var columns uint32
+	for i, pin := range c.pins {
+		if !pins.Get(pin) {
+			columns |= 1 << i
+		}
+	}
+	return columns, nil
+}
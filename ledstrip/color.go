@@ -0,0 +1,55 @@
+package ledstrip
+
+import "image/color"
+
+// HSV returns the color with the given hue, saturation and value. The hue runs
+// from red (0) through green (85) and blue (170) back to red.
+func HSV(h, s, v uint8) color.RGBA {
+	// Split the hue in six sectors of 43 steps each.
+	sector := h / 43
+	rem := uint32(h-sector*43) * 6
+
+	p := uint8(uint32(v) * uint32(255-s) / 255)
+	q := uint8(uint32(v) * (255*255 - uint32(s)*rem) / (255 * 255))
+	t := uint8(uint32(v) * (255*255 - uint32(s)*(255-rem)) / (255 * 255))
+
+	switch sector {
+	case 0:
+		return color.RGBA{R: v, G: t, B: p, A: 255}
+	case 1:
+		return color.RGBA{R: q, G: v, B: p, A: 255}
+	case 2:
+		return color.RGBA{R: p, G: v, B: t, A: 255}
+	case 3:
+		return color.RGBA{R: p, G: q, B: v, A: 255}
+	case 4:
+		return color.RGBA{R: t, G: p, B: v, A: 255}
+	default:
+		return color.RGBA{R: v, G: p, B: q, A: 255}
+	}
+}
+
+// Scale returns the color with all channels scaled by f/255.
+func Scale(c color.RGBA, f uint8) color.RGBA {
+	scale := uint16(f) + 1
+	return color.RGBA{
+		R: uint8(uint16(c.R) * scale >> 8),
+		G: uint8(uint16(c.G) * scale >> 8),
+		B: uint8(uint16(c.B) * scale >> 8),
+		A: c.A,
+	}
+}
+
+// Blend returns the mix of two colors: a when amount is 0, b when amount is 255.
+func Blend(a, b color.RGBA, amount uint8) color.RGBA {
+	return color.RGBA{
+		R: blend(a.R, b.R, amount),
+		G: blend(a.G, b.G, amount),
+		B: blend(a.B, b.B, amount),
+		A: blend(a.A, b.A, amount),
+	}
+}
+
+func blend(a, b, amount uint8) uint8 {
+	return uint8((uint16(a)*uint16(255-amount) + uint16(b)*uint16(amount)) / 255)
+}
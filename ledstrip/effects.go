@@ -0,0 +1,243 @@
+package ledstrip
+
+import (
+	"image/color"
+	"time"
+)
+
+// Effect is an animation. Effects only depend on the time since they were
+// started, so they can be rendered at any frame rate and are easy to switch
+// between.
+type Effect interface {
+	// Render draws the effect into the LEDs at time t.
+	Render(leds []color.RGBA, t time.Duration)
+}
+
+// EffectFunc is an adapter to use an ordinary function as an Effect.
+type EffectFunc func(leds []color.RGBA, t time.Duration)
+
+// Render calls f(leds, t).
+func (f EffectFunc) Render(leds []color.RGBA, t time.Duration) {
+	f(leds, t)
+}
+
+// phase returns the position of t within a period, from 0 to 65535.
+func phase(t, period time.Duration) uint16 {
+	return uint16((t % period) * 65536 / period)
+}
+
+// triangle returns a value that goes from 0 to 255 and back to 0 during the
+// phase.
+func triangle(p uint16) uint8 {
+	if p < 0x8000 {
+		return uint8(p >> 7)
+	}
+	return uint8((0xffff - p) >> 7)
+}
+
+// Solid shows a single color on all LEDs.
+type Solid struct {
+	Color color.RGBA
+}
+
+// Render implements Effect.
+func (e Solid) Render(leds []color.RGBA, t time.Duration) {
+	for i := range leds {
+		leds[i] = e.Color
+	}
+}
+
+// Rainbow shows a moving rainbow.
+type Rainbow struct {
+	// Time for the rainbow to cycle through all colors. The default is 5s.
+	Period time.Duration
+
+	// Number of LEDs that show the whole rainbow. The default is the whole
+	// strip.
+	Length int
+
+	// Brightness of the colors. Zero means full brightness.
+	Value uint8
+}
+
+// Render implements Effect.
+func (e Rainbow) Render(leds []color.RGBA, t time.Duration) {
+	period := e.Period
+	if period <= 0 {
+		period = 5 * time.Second
+	}
+	length := e.Length
+	if length <= 0 {
+		length = len(leds)
+	}
+	value := e.Value
+	if value == 0 {
+		value = 255
+	}
+	start := phase(t, period) >> 8
+	for i := range leds {
+		hue := uint8(int(start) + i*256/length)
+		leds[i] = HSV(hue, 255, value)
+	}
+}
+
+// Breathe slowly fades all LEDs in and out.
+type Breathe struct {
+	Color color.RGBA
+
+	// Time for a single breath. The default is 4s.
+	Period time.Duration
+}
+
+// Render implements Effect.
+func (e Breathe) Render(leds []color.RGBA, t time.Duration) {
+	period := e.Period
+	if period <= 0 {
+		period = 4 * time.Second
+	}
+	// Square the brightness, so that the LEDs spend more time dimmed.
+	v := uint16(triangle(phase(t, period)))
+	c := Scale(e.Color, uint8(v*v/255))
+	for i := range leds {
+		leds[i] = c
+	}
+}
+
+// Chase shows lights that move along the strip, like theater marquee lights.
+type Chase struct {
+	Color, Background color.RGBA
+
+	// Distance between the lights. The default is 3.
+	Spacing int
+
+	// Time for the lights to move one step. The default is 100ms.
+	Step time.Duration
+}
+
+// Render implements Effect.
+func (e Chase) Render(leds []color.RGBA, t time.Duration) {
+	spacing := e.Spacing
+	if spacing <= 0 {
+		spacing = 3
+	}
+	step := e.Step
+	if step <= 0 {
+		step = 100 * time.Millisecond
+	}
+	offset := int(t / step % time.Duration(spacing))
+	for i := range leds {
+		if (i+spacing-offset)%spacing == 0 {
+			leds[i] = e.Color
+		} else {
+			leds[i] = e.Background
+		}
+	}
+}
+
+// Comet shows a light with a fading tail that moves along the strip and wraps
+// around at the end.
+type Comet struct {
+	Color color.RGBA
+
+	// Length of the tail in LEDs. The default is 8.
+	Tail int
+
+	// Time for the comet to travel along the whole strip. The default is 2s.
+	Period time.Duration
+}
+
+// Render implements Effect.
+func (e Comet) Render(leds []color.RGBA, t time.Duration) {
+	if len(leds) == 0 {
+		return
+	}
+	tail := e.Tail
+	if tail <= 0 {
+		tail = 8
+	}
+	period := e.Period
+	if period <= 0 {
+		period = 2 * time.Second
+	}
+	head := int(phase(t, period)) * len(leds) >> 16
+	for i := range leds {
+		distance := (head - i + len(leds)) % len(leds)
+		if distance < tail {
+			leds[i] = Scale(e.Color, uint8(255-distance*255/tail))
+		} else {
+			leds[i] = color.RGBA{}
+		}
+	}
+}
+
+// Wipe fills the strip with a color one LED at a time, and then starts again
+// from the background color.
+type Wipe struct {
+	Color, Background color.RGBA
+
+	// Time to fill the whole strip. The default is 2s.
+	Period time.Duration
+}
+
+// Render implements Effect.
+func (e Wipe) Render(leds []color.RGBA, t time.Duration) {
+	period := e.Period
+	if period <= 0 {
+		period = 2 * time.Second
+	}
+	filled := int(phase(t, period)) * (len(leds) + 1) >> 16
+	for i := range leds {
+		if i < filled {
+			leds[i] = e.Color
+		} else {
+			leds[i] = e.Background
+		}
+	}
+}
+
+// Twinkle makes random LEDs light up and fade out again.
+type Twinkle struct {
+	Color, Background color.RGBA
+
+	// Chance that an LED lights up during a period, out of 256. The default is
+	// 64.
+	Density uint8
+
+	// Time for a single twinkle. The default is 1s.
+	Period time.Duration
+}
+
+// Render implements Effect.
+func (e Twinkle) Render(leds []color.RGBA, t time.Duration) {
+	density := e.Density
+	if density == 0 {
+		density = 64
+	}
+	period := e.Period
+	if period <= 0 {
+		period = time.Second
+	}
+	for i := range leds {
+		// Every LED twinkles with its own offset in time, so that they don't
+		// all start at once.
+		offset := time.Duration(hash(uint32(i), 0)) % period
+		cycle := uint32((t + offset) / period)
+		if uint8(hash(uint32(i), cycle+1)) >= density {
+			leds[i] = e.Background
+			continue
+		}
+		amount := triangle(phase(t+offset, period))
+		leds[i] = Blend(e.Background, e.Color, amount)
+	}
+}
+
+// hash returns a pseudo-random number for the given values.
+func hash(a, b uint32) uint32 {
+	x := a*0x9e3779b1 ^ b*0x85ebca77
+	x ^= x >> 15
+	x *= 0x2c1b3c6d
+	x ^= x >> 12
+	x *= 0x297a2d39
+	x ^= x >> 15
+	return x
+}
@@ -0,0 +1,336 @@
+// Package ledstrip implements a framebuffer for addressable LED strips and
+// matrices, such as WS2812, SK6812 and APA102 LEDs.
+//
+// A Strip holds the colors of all LEDs in logical order. When it is shown, the
+// colors are mapped to the physical order of the LEDs (for example for
+// serpentine matrices or strips made of multiple segments), gamma corrected,
+// scaled by the global brightness and limited to a maximum estimated current.
+// The result is written to any driver with a WriteColors method.
+//
+// The package also contains a number of time-based effects (see Effect) that
+// can be rendered into the strip.
+package ledstrip // import "tinygo.org/x/drivers/ledstrip"
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"tinygo.org/x/drivers"
+)
+
+// Writer sends colors to the LEDs, in physical order. It is implemented by
+// ws2812.Device. For drivers with a different WriteColors signature, such as
+// apa102.Device, use a WriterFunc.
+type Writer interface {
+	WriteColors(buf []color.RGBA) error
+}
+
+// WriterFunc is an adapter to use an ordinary function as a Writer.
+type WriterFunc func(buf []color.RGBA) error
+
+// WriteColors calls f(buf).
+func (f WriterFunc) WriteColors(buf []color.RGBA) error {
+	return f(buf)
+}
+
+// Config is the configuration of a Strip.
+type Config struct {
+	// Gamma correction exponent. Typical values are 2.2 to 2.8. Zero disables
+	// gamma correction.
+	Gamma float32
+
+	// Maximum current in mA that the LEDs may draw. When the estimated current
+	// of a frame is higher, the whole frame is dimmed. Zero means no limit.
+	MaxCurrent int
+
+	// Current in mA of a single color channel of a single LED at full
+	// brightness. The default is 20mA, which is typical for WS2812 LEDs.
+	ChannelCurrent int
+
+	// Current in mA of a single LED that is off.
+	IdleCurrent int
+
+	// Use the alpha channel of the output for a separate white LED, as used by
+	// RGBW strips like the SK6812. The white part of each color is moved to the
+	// white LED. When disabled, the alpha channel of the output is always 0xff,
+	// which is full brightness for APA102 LEDs.
+	White bool
+}
+
+// Segment is a run of LEDs in a strip.
+type Segment struct {
+	// Index of the first physical LED of the segment.
+	Start int
+
+	// Number of LEDs in the segment.
+	Length int
+
+	// The segment runs backwards: the first logical LED of the segment is the
+	// last physical LED.
+	Reverse bool
+}
+
+// Matrix describes how the LEDs of a 2D matrix are wired.
+type Matrix struct {
+	// Size of the matrix in LEDs.
+	Width, Height int
+
+	// Every other row (or column when Vertical is set) runs in the opposite
+	// direction, which is how most LED matrices are wired.
+	Serpentine bool
+
+	// The LEDs are wired in columns instead of rows.
+	Vertical bool
+
+	// The first LED is on the right or at the bottom instead of the top left.
+	FlipX, FlipY bool
+}
+
+// Strip is a framebuffer for an LED strip or matrix. It implements
+// drivers.Displayer.
+type Strip struct {
+	writer     Writer
+	config     Config
+	leds       []color.RGBA // logical order
+	out        []color.RGBA // physical order
+	mapping    []uint16     // logical to physical index, nil for the identity mapping
+	width      int16
+	height     int16
+	rotation   drivers.Rotation
+	brightness uint8
+	current    int
+	gamma      [256]uint8
+}
+
+// New returns a new strip of n LEDs in a single run.
+func New(w Writer, n int, config Config) *Strip {
+	return newStrip(w, n, n, 1, nil, config)
+}
+
+// NewSegments returns a new strip made of the given segments. The logical LEDs
+// run through all segments in order. Physical LEDs that aren't part of a segment
+// are always off.
+func NewSegments(w Writer, segments []Segment, config Config) *Strip {
+	n, physical := 0, 0
+	for _, seg := range segments {
+		n += seg.Length
+		physical = max(physical, seg.Start+seg.Length)
+	}
+	mapping := make([]uint16, 0, n)
+	for _, seg := range segments {
+		for i := 0; i < seg.Length; i++ {
+			if seg.Reverse {
+				mapping = append(mapping, uint16(seg.Start+seg.Length-1-i))
+			} else {
+				mapping = append(mapping, uint16(seg.Start+i))
+			}
+		}
+	}
+	return newStrip(w, physical, n, 1, mapping, config)
+}
+
+// NewMatrix returns a new strip for a 2D matrix. The logical LEDs are in rows,
+// starting at the top left.
+func NewMatrix(w Writer, m Matrix, config Config) *Strip {
+	mapping := make([]uint16, m.Width*m.Height)
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			mapping[y*m.Width+x] = uint16(m.index(x, y))
+		}
+	}
+	return newStrip(w, len(mapping), m.Width, m.Height, mapping, config)
+}
+
+// index returns the physical index of the LED at the given position.
+func (m Matrix) index(x, y int) int {
+	if m.FlipX {
+		x = m.Width - 1 - x
+	}
+	if m.FlipY {
+		y = m.Height - 1 - y
+	}
+	run, pos, length := y, x, m.Width
+	if m.Vertical {
+		run, pos, length = x, y, m.Height
+	}
+	if m.Serpentine && run%2 == 1 {
+		pos = length - 1 - pos
+	}
+	return run*length + pos
+}
+
+func newStrip(w Writer, physical, width, height int, mapping []uint16, config Config) *Strip {
+	if config.ChannelCurrent == 0 {
+		config.ChannelCurrent = 20
+	}
+	s := &Strip{
+		writer:     w,
+		config:     config,
+		leds:       make([]color.RGBA, width*height),
+		out:        make([]color.RGBA, physical),
+		mapping:    mapping,
+		width:      int16(width),
+		height:     int16(height),
+		brightness: 255,
+	}
+	for i := range s.gamma {
+		if config.Gamma == 0 {
+			s.gamma[i] = uint8(i)
+		} else {
+			s.gamma[i] = uint8(math.Pow(float64(i)/255, float64(config.Gamma))*255 + 0.5)
+		}
+	}
+	return s
+}
+
+// Len returns the number of logical LEDs.
+func (s *Strip) Len() int {
+	return len(s.leds)
+}
+
+// Pixels returns the colors of all LEDs in logical order. Changes to the
+// returned slice are shown on the next call to Display.
+func (s *Strip) Pixels() []color.RGBA {
+	return s.leds
+}
+
+// Set changes the color of a single LED. Out of range indices are ignored.
+func (s *Strip) Set(i int, c color.RGBA) {
+	if i >= 0 && i < len(s.leds) {
+		s.leds[i] = c
+	}
+}
+
+// Fill sets all LEDs to the given color.
+func (s *Strip) Fill(c color.RGBA) {
+	for i := range s.leds {
+		s.leds[i] = c
+	}
+}
+
+// Clear turns off all LEDs.
+func (s *Strip) Clear() {
+	s.Fill(color.RGBA{})
+}
+
+// Render renders the effect into the strip, at the given time since the start
+// of the effect.
+func (s *Strip) Render(effect Effect, t time.Duration) {
+	effect.Render(s.leds, t)
+}
+
+// Size returns the size of the matrix, or the number of LEDs and 1 for a strip.
+// The size takes rotation into account.
+func (s *Strip) Size() (x, y int16) {
+	return s.rotation.Size(s.width, s.height)
+}
+
+// SetPixel changes the color of the LED at the given position of the matrix.
+func (s *Strip) SetPixel(x, y int16, c color.RGBA) {
+	w, h := s.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return
+	}
+	x, y = s.rotation.Transform(x, y, s.width, s.height)
+	s.leds[int(y)*int(s.width)+int(x)] = c
+}
+
+// Rotation returns the currently configured rotation.
+func (s *Strip) Rotation() drivers.Rotation {
+	return s.rotation
+}
+
+// SetRotation changes the rotation of the matrix (clock-wise), as seen by
+// SetPixel. It doesn't change the logical order of Pixels and effects.
+func (s *Strip) SetRotation(rotation drivers.Rotation) error {
+	s.rotation = rotation % 8
+	return nil
+}
+
+// SetBrightness changes the global brightness, from 0 (off) to 255 (full
+// brightness, the default). It is applied before gamma correction, so that
+// dimming looks linear.
+func (s *Strip) SetBrightness(brightness uint8) {
+	s.brightness = brightness
+}
+
+// Brightness returns the global brightness.
+func (s *Strip) Brightness() uint8 {
+	return s.brightness
+}
+
+// Current returns the estimated current in mA of the last frame that was
+// displayed, after limiting it to the maximum current.
+func (s *Strip) Current() int {
+	return s.current
+}
+
+// Display sends the colors to the LEDs.
+func (s *Strip) Display() error {
+	s.render()
+	return s.writer.WriteColors(s.out)
+}
+
+// render converts the logical colors to the output buffer.
+func (s *Strip) render() {
+	alpha := uint8(0xff)
+	if s.config.White {
+		alpha = 0
+	}
+	if s.mapping != nil {
+		for i := range s.out {
+			s.out[i] = color.RGBA{A: alpha}
+		}
+	}
+
+	var sum uint32
+	for i, c := range s.leds {
+		c = Scale(c, s.brightness)
+		c = color.RGBA{R: s.gamma[c.R], G: s.gamma[c.G], B: s.gamma[c.B], A: alpha}
+		if s.config.White {
+			w := min(c.R, c.G, c.B)
+			c = color.RGBA{R: c.R - w, G: c.G - w, B: c.B - w, A: w}
+		}
+		sum += uint32(c.R) + uint32(c.G) + uint32(c.B)
+		if s.config.White {
+			sum += uint32(c.A)
+		}
+		if s.mapping != nil {
+			s.out[s.mapping[i]] = c
+		} else {
+			s.out[i] = c
+		}
+	}
+
+	idle := s.config.IdleCurrent * len(s.out)
+	channels := int(sum) * s.config.ChannelCurrent / 255
+	s.current = idle + channels
+	if s.config.MaxCurrent <= 0 || s.current <= s.config.MaxCurrent {
+		return
+	}
+
+	// Dim all LEDs so that they stay within the current limit. When the idle
+	// current alone is over the limit, all LEDs are turned off.
+	available := s.config.MaxCurrent - idle
+	if channels == 0 {
+		return
+	}
+	factor := uint32(0)
+	if available > 0 {
+		factor = uint32(available * 256 / channels)
+	}
+	sum = 0
+	for i, c := range s.out {
+		c.R = uint8(uint32(c.R) * factor >> 8)
+		c.G = uint8(uint32(c.G) * factor >> 8)
+		c.B = uint8(uint32(c.B) * factor >> 8)
+		sum += uint32(c.R) + uint32(c.G) + uint32(c.B)
+		if s.config.White {
+			c.A = uint8(uint32(c.A) * factor >> 8)
+			sum += uint32(c.A)
+		}
+		s.out[i] = c
+	}
+	s.current = idle + int(sum)*s.config.ChannelCurrent/255
+}
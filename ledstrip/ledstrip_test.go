@@ -0,0 +1,197 @@
+package ledstrip_test
+
+import (
+	"image/color"
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/ledstrip"
+	"tinygo.org/x/drivers/tester"
+)
+
+// recorder keeps the last colors that were written.
+type recorder struct {
+	colors []color.RGBA
+}
+
+func (r *recorder) WriteColors(buf []color.RGBA) error {
+	r.colors = append(r.colors[:0], buf...)
+	return nil
+}
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+func TestMatrix(t *testing.T) {
+	for _, tc := range []struct {
+		matrix   ledstrip.Matrix
+		expected []uint8 // physical order of the logical LEDs (numbered 1..6)
+	}{
+		{ledstrip.Matrix{Width: 3, Height: 2}, []uint8{1, 2, 3, 4, 5, 6}},
+		{ledstrip.Matrix{Width: 3, Height: 2, Serpentine: true}, []uint8{1, 2, 3, 6, 5, 4}},
+		{ledstrip.Matrix{Width: 3, Height: 2, Vertical: true}, []uint8{1, 4, 2, 5, 3, 6}},
+		{ledstrip.Matrix{Width: 3, Height: 2, Vertical: true, Serpentine: true}, []uint8{1, 4, 5, 2, 3, 6}},
+		{ledstrip.Matrix{Width: 3, Height: 2, FlipX: true, FlipY: true}, []uint8{6, 5, 4, 3, 2, 1}},
+	} {
+		r := &recorder{}
+		s := ledstrip.NewMatrix(r, tc.matrix, ledstrip.Config{})
+		for i := range s.Pixels() {
+			s.Pixels()[i] = color.RGBA{R: uint8(i + 1)}
+		}
+		s.Display()
+		for i, c := range r.colors {
+			if c.R != tc.expected[i] {
+				t.Errorf("%+v: expected LED %d to be %d, got %d", tc.matrix, i, tc.expected[i], c.R)
+			}
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	s := ledstrip.NewMatrix(&recorder{}, ledstrip.Matrix{Width: 8, Height: 5}, ledstrip.Config{})
+	tester.TestRotation(t, s, func(x, y int16) bool {
+		return s.Pixels()[int(y)*8+int(x)].R != 0
+	})
+}
+
+func TestSegments(t *testing.T) {
+	r := &recorder{}
+	s := ledstrip.NewSegments(r, []ledstrip.Segment{
+		{Start: 0, Length: 2},
+		{Start: 3, Length: 3, Reverse: true},
+	}, ledstrip.Config{})
+	if s.Len() != 5 {
+		t.Fatalf("expected 5 LEDs, got %d", s.Len())
+	}
+	for i := 0; i < s.Len(); i++ {
+		s.Set(i, color.RGBA{R: uint8(i + 1)})
+	}
+	s.Display()
+	expected := []uint8{1, 2, 0, 5, 4, 3}
+	if len(r.colors) != len(expected) {
+		t.Fatalf("expected %d physical LEDs, got %d", len(expected), len(r.colors))
+	}
+	for i, c := range r.colors {
+		if c.R != expected[i] {
+			t.Errorf("expected LED %d to be %d, got %d", i, expected[i], c.R)
+		}
+		if c.A != 0xff {
+			t.Errorf("expected alpha 0xff for LED %d, got %d", i, c.A)
+		}
+	}
+}
+
+func TestCorrection(t *testing.T) {
+	r := &recorder{}
+	s := ledstrip.New(r, 2, ledstrip.Config{Gamma: 2})
+	s.Set(0, color.RGBA{R: 128, G: 255})
+	s.Set(1, white)
+	s.SetBrightness(128)
+	s.Display()
+	if c := r.colors[0]; c.R != 16 || c.G != 64 {
+		t.Errorf("unexpected gamma corrected color: %v", c)
+	}
+
+	// RGBW strips move the white part of the color to the white LED.
+	s = ledstrip.New(r, 1, ledstrip.Config{White: true})
+	s.Set(0, color.RGBA{R: 255, G: 100, B: 50})
+	s.Display()
+	if c := r.colors[0]; c != (color.RGBA{R: 205, G: 50, B: 0, A: 50}) {
+		t.Errorf("unexpected RGBW color: %v", c)
+	}
+}
+
+func TestCurrentLimit(t *testing.T) {
+	r := &recorder{}
+	s := ledstrip.New(r, 10, ledstrip.Config{MaxCurrent: 300, IdleCurrent: 1})
+	s.Fill(red)
+	s.Display()
+	if s.Current() != 210 || r.colors[0].R != 255 {
+		t.Errorf("expected unlimited current of 210mA, got %dmA (%v)", s.Current(), r.colors[0])
+	}
+
+	s.Fill(white)
+	s.Display()
+	if s.Current() > 300 || s.Current() < 280 {
+		t.Errorf("expected current just below 300mA, got %dmA", s.Current())
+	}
+	if c := r.colors[0]; c.R != c.G || c.R > 128 || c.R < 120 {
+		t.Errorf("unexpected dimmed color: %v", c)
+	}
+}
+
+func TestCurrentLimitIdle(t *testing.T) {
+	// The idle current alone is over the limit.
+	r := &recorder{}
+	s := ledstrip.New(r, 300, ledstrip.Config{MaxCurrent: 200, IdleCurrent: 1})
+	s.Clear()
+	s.Display()
+	if s.Current() != 300 || r.colors[0] != (color.RGBA{A: 255}) {
+		t.Errorf("expected idle current of 300mA with LEDs off, got %dmA (%v)", s.Current(), r.colors[0])
+	}
+
+	s.Fill(white)
+	s.Display()
+	if s.Current() != 300 || r.colors[0] != (color.RGBA{A: 255}) {
+		t.Errorf("expected LEDs off at 300mA, got %dmA (%v)", s.Current(), r.colors[0])
+	}
+}
+
+func TestHSV(t *testing.T) {
+	for _, tc := range []struct {
+		h        uint8
+		expected color.RGBA
+	}{
+		{0, color.RGBA{R: 255, A: 255}},
+		{43, color.RGBA{R: 255, G: 255, A: 255}},
+		{86, color.RGBA{G: 255, B: 0, A: 255}},
+		{129, color.RGBA{G: 255, B: 255, A: 255}},
+		{172, color.RGBA{B: 255, A: 255}},
+	} {
+		if c := ledstrip.HSV(tc.h, 255, 255); c != tc.expected {
+			t.Errorf("HSV(%d): expected %v, got %v", tc.h, tc.expected, c)
+		}
+	}
+	if c := ledstrip.HSV(100, 0, 128); c != (color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("expected gray, got %v", c)
+	}
+}
+
+func TestEffects(t *testing.T) {
+	leds := make([]color.RGBA, 6)
+
+	ledstrip.Chase{Color: red, Spacing: 3, Step: time.Second}.Render(leds, 1500*time.Millisecond)
+	for i, c := range leds {
+		if lit := c == red; lit != (i%3 == 1) {
+			t.Errorf("chase: unexpected color %v for LED %d", c, i)
+		}
+	}
+
+	ledstrip.Comet{Color: white, Tail: 2, Period: 6 * time.Second}.Render(leds, 3500*time.Millisecond)
+	if leds[3] != white || leds[2].R != 128 || leds[1] != (color.RGBA{}) || leds[4] != (color.RGBA{}) {
+		t.Errorf("comet: unexpected colors %v", leds)
+	}
+
+	ledstrip.Wipe{Color: red, Period: 7 * time.Second}.Render(leds, 3500*time.Millisecond)
+	for i, c := range leds {
+		if filled := c == red; filled != (i < 3) {
+			t.Errorf("wipe: unexpected color %v for LED %d", c, i)
+		}
+	}
+
+	ledstrip.Rainbow{Period: time.Second}.Render(leds, 0)
+	if leds[0] != red || leds[3].R != 0 {
+		t.Errorf("rainbow: unexpected colors %v", leds)
+	}
+
+	ledstrip.Breathe{Color: white, Period: time.Second}.Render(leds, 0)
+	if leds[0].R != 0 {
+		t.Errorf("breathe: expected LEDs to start off, got %v", leds[0])
+	}
+	ledstrip.Breathe{Color: white, Period: time.Second}.Render(leds, 500*time.Millisecond)
+	if leds[0].R != 255 {
+		t.Errorf("breathe: expected full brightness halfway, got %v", leds[0])
+	}
+}
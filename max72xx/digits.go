@@ -0,0 +1,80 @@
+package max72xx
+
+import (
+	"tinygo.org/x/drivers/sevenseg"
+	"tinygo.org/x/drivers/textdisplay"
+)
+
+// Digits shows text on 7-segment digits. It doesn't use the digit decoder of
+// the MAX7219, which only supports digits and a few letters, but the shared
+// 7-segment font of package sevenseg.
+//
+// Digit 0 of the MAX7219 is the rightmost digit, as on most modules.
+type Digits struct {
+	dev    *Device
+	count  uint8
+	glyphs [8]byte // custom glyphs for character codes 0-7
+}
+
+// NewDigits returns a text display for the given number of digits (1-8).
+func NewDigits(dev *Device, count uint8) *Digits {
+	return &Digits{
+		dev:   dev,
+		count: max(1, min(count, 8)),
+	}
+}
+
+// Configure disables digit decoding and sets the scan limit to the number of
+// digits.
+func (d *Digits) Configure() {
+	d.dev.SetDecodeMode(0)
+	d.dev.SetScanLimit(d.count)
+}
+
+// Size returns the number of digits, as a single row.
+func (d *Digits) Size() (columns, rows int16) {
+	return int16(d.count), 1
+}
+
+// ClearDisplay turns off all segments.
+func (d *Digits) ClearDisplay() {
+	for i := uint8(0); i < d.count; i++ {
+		d.dev.WriteCommand(REG_DIGIT0+i, 0)
+	}
+}
+
+// WriteAt shows the text starting at the given digit, counting from the left.
+// It implements textdisplay.Device.
+func (d *Digits) WriteAt(column, row int16, text []byte) {
+	if row != 0 {
+		return
+	}
+	for i, c := range text {
+		pos := int(column) + i
+		if pos < 0 || pos >= int(d.count) {
+			continue
+		}
+		d.dev.WriteCommand(REG_DIGIT0+d.count-1-uint8(pos), sevenseg.ToDPABCDEFG(d.encode(c)))
+	}
+}
+
+// CreateGlyph defines the segments (see package sevenseg) that are shown for
+// the character code 0-7. The data must be a single byte. It implements
+// textdisplay.Device.
+func (d *Digits) CreateGlyph(code byte, data []byte) error {
+	if code >= byte(len(d.glyphs)) {
+		return textdisplay.ErrGlyphCode
+	}
+	if len(data) != 1 {
+		return textdisplay.ErrGlyphSize
+	}
+	d.glyphs[code] = data[0]
+	return nil
+}
+
+func (d *Digits) encode(c byte) byte {
+	if c < byte(len(d.glyphs)) {
+		return d.glyphs[c]
+	}
+	return sevenseg.Encode(c)
+}
@@ -0,0 +1,207 @@
+package max72xx
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers"
+)
+
+// MatrixConfig is the configuration of a Matrix.
+type MatrixConfig struct {
+	// Number of 8x8 modules horizontally and vertically. The default is 4x1,
+	// which is a common 32x8 display.
+	Width  int16
+	Height int16
+
+	// Orientation of every module. Rotation0 is the orientation of the common
+	// FC-16 modules: digit 0 of each MAX7219 is the top row, and bit 7 is the
+	// leftmost column.
+	Rotation drivers.Rotation
+
+	// By default the first module in the chain (the one connected to the
+	// microcontroller) is at the top right, and the chain runs from right to
+	// left and then continues on the right of the next row. Set Reverse when the
+	// chain runs from left to right instead, starting at the top left.
+	Reverse bool
+
+	// Intensity of the LEDs, from 0 to 15.
+	Intensity uint8
+}
+
+// Matrix is a framebuffer for a display made of 8x8 LED matrix modules, each
+// driven by a MAX7219. All MAX7219s are daisy-chained and share a single CS
+// pin. It implements drivers.Displayer.
+type Matrix struct {
+	dev         *Device
+	width       int16            // in modules
+	height      int16            // in modules
+	orientation drivers.Rotation // of every module
+	rotation    drivers.Rotation // of the whole display
+	reverse     bool
+	buffer      []uint8 // 8 rows per module, in chain order
+	tx          []uint8 // register/data pairs for all modules
+}
+
+// NewMatrix returns a new framebuffer for daisy-chained matrix modules.
+func NewMatrix(dev *Device) *Matrix {
+	return &Matrix{dev: dev}
+}
+
+// Configure sets up all MAX7219s for use with an LED matrix, and clears the
+// display.
+func (m *Matrix) Configure(cfg MatrixConfig) {
+	m.width = cfg.Width
+	if m.width <= 0 {
+		m.width = 4
+	}
+	m.height = cfg.Height
+	if m.height <= 0 {
+		m.height = 1
+	}
+	m.orientation = cfg.Rotation
+	m.reverse = cfg.Reverse
+	modules := int(m.width) * int(m.height)
+	m.buffer = make([]uint8, modules*8)
+	m.tx = make([]uint8, modules*2)
+
+	m.dev.Configure()
+	m.writeAll(REG_DISPLAY_TEST, 0x00)
+	m.writeAll(REG_DECODE_MODE, 0x00)
+	m.writeAll(REG_SCANLIMIT, 7)
+	m.SetIntensity(cfg.Intensity)
+	m.ClearDisplay()
+	m.writeAll(REG_SHUTDOWN, 0x01)
+}
+
+// SetIntensity sets the intensity of all modules, from 0 to 15.
+func (m *Matrix) SetIntensity(intensity uint8) {
+	m.writeAll(REG_INTENSITY, min(intensity, 0x0F))
+}
+
+// Sleep puts all MAX7219s in shutdown mode, or wakes them up again.
+func (m *Matrix) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		m.writeAll(REG_SHUTDOWN, 0x00)
+	} else {
+		m.writeAll(REG_SHUTDOWN, 0x01)
+	}
+	return nil
+}
+
+// writeAll writes the same register of all MAX7219s.
+func (m *Matrix) writeAll(register, data byte) {
+	for i := 0; i < len(m.tx); i += 2 {
+		m.tx[i] = register
+		m.tx[i+1] = data
+	}
+	m.send()
+}
+
+// send sends the prepared register/data pairs in a single transaction.
+func (m *Matrix) send() {
+	m.dev.cs.Low()
+	m.dev.bus.Tx(m.tx, nil)
+	m.dev.cs.High()
+}
+
+// Size returns the size of the display in pixels, taking rotation into
+// account.
+func (m *Matrix) Size() (x, y int16) {
+	return m.rotation.Size(m.width*8, m.height*8)
+}
+
+// Rotation returns the rotation of the whole display.
+func (m *Matrix) Rotation() drivers.Rotation {
+	return m.rotation
+}
+
+// SetRotation rotates the whole display (clock-wise), unlike the Rotation of
+// the configuration which is the orientation of each module. It changes how
+// SetPixel and GetPixel map to the buffer, so clear the buffer after changing
+// it.
+func (m *Matrix) SetRotation(rotation drivers.Rotation) error {
+	m.rotation = rotation % 8
+	return nil
+}
+
+// SetPixel modifies the internal buffer in a single pixel. Any color other
+// than black turns the LED on.
+func (m *Matrix) SetPixel(x, y int16, c color.RGBA) {
+	index, bit, ok := m.position(x, y)
+	if !ok {
+		return
+	}
+	if c.R != 0 || c.G != 0 || c.B != 0 {
+		m.buffer[index] |= bit
+	} else {
+		m.buffer[index] &^= bit
+	}
+}
+
+// GetPixel returns whether the LED at the given position is on.
+func (m *Matrix) GetPixel(x, y int16) bool {
+	index, bit, ok := m.position(x, y)
+	return ok && m.buffer[index]&bit != 0
+}
+
+// position returns the buffer index and bit of a pixel.
+func (m *Matrix) position(x, y int16) (index int, bit uint8, ok bool) {
+	w, h := m.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return 0, 0, false
+	}
+	x, y = m.rotation.Transform(x, y, m.width*8, m.height*8)
+	moduleX, moduleY := x/8, y/8
+	x, y = x%8, y%8
+
+	// Convert to the row (digit) and column of the module.
+	if m.orientation.IsMirrored() {
+		x = 7 - x
+	}
+	var row, col int16
+	switch m.orientation % 4 {
+	case drivers.Rotation0:
+		row, col = y, x
+	case drivers.Rotation90:
+		row, col = 7-x, y
+	case drivers.Rotation180:
+		row, col = 7-y, 7-x
+	case drivers.Rotation270:
+		row, col = x, 7-y
+	}
+
+	if !m.reverse {
+		moduleX = m.width - 1 - moduleX
+	}
+	module := int(moduleY)*int(m.width) + int(moduleX)
+	return module*8 + int(row), 0x80 >> col, true
+}
+
+// Display sends the buffer to the LEDs. Each row is sent to all MAX7219s at
+// once.
+func (m *Matrix) Display() error {
+	modules := len(m.tx) / 2
+	for row := 0; row < 8; row++ {
+		// The data for the last module in the chain is sent first.
+		for i := 0; i < modules; i++ {
+			module := modules - 1 - i
+			m.tx[i*2] = REG_DIGIT0 + uint8(row)
+			m.tx[i*2+1] = m.buffer[module*8+row]
+		}
+		m.send()
+	}
+	return nil
+}
+
+// ClearBuffer clears the internal buffer.
+func (m *Matrix) ClearBuffer() {
+	for i := range m.buffer {
+		m.buffer[i] = 0
+	}
+}
+
+// ClearDisplay clears the internal buffer and the display.
+func (m *Matrix) ClearDisplay() {
+	m.ClearBuffer()
+	m.Display()
+}
@@ -0,0 +1,357 @@
+// Package menu implements nested menus with value editors, for devices with a
+// small display and a few buttons or a rotary encoder with a push button.
+//
+// A Navigator shows a menu on a Renderer and is driven by abstract events:
+// Up, Down, Select and Back. The events typically come from a rotary encoder
+// (see Navigator.Scroll), buttons handled by package input, or a keypad.
+// Renderers are provided for character displays such as the HD44780 (Text) and
+// for pixel displays such as the SSD1306 (Pixel).
+//
+// Menus contain items: actions, submenus and editors for integers, choices,
+// booleans and times of day. Selecting an editor starts editing its value,
+// which is changed with Up and Down, and confirmed with Select or restored
+// with Back.
+package menu // import "tinygo.org/x/drivers/menu"
+
+import (
+	"strconv"
+	"time"
+)
+
+// Event is an input event for a Navigator.
+type Event uint8
+
+// Events.
+const (
+	// Up selects the previous item, or increases the value being edited.
+	Up Event = iota + 1
+
+	// Down selects the next item, or decreases the value being edited.
+	Down
+
+	// Select opens a submenu, runs an action or starts editing a value. While
+	// editing, it moves to the next field of the value or confirms the value.
+	Select
+
+	// Back returns to the parent menu. While editing, it moves to the previous
+	// field of the value or cancels editing.
+	Back
+)
+
+// Item is an entry of a menu.
+type Item interface {
+	// Label returns the text of the item.
+	Label() string
+
+	// AppendValue appends the value of the item to buf, which is shown after
+	// the label. Items without a value append nothing.
+	AppendValue(buf []byte) []byte
+}
+
+// Activator is an item that does something when it is selected.
+type Activator interface {
+	Item
+
+	// Activate is called when the item is selected.
+	Activate()
+}
+
+// Editor is an item with a value that can be edited. The editor works on a
+// copy of the value, which is only stored when editing is confirmed.
+type Editor interface {
+	Item
+
+	// Edit starts editing the value.
+	Edit()
+
+	// Step changes the value being edited by the given number of steps.
+	Step(steps int)
+
+	// Next moves to the next field of the value, and returns true when there
+	// is none, in which case the value is stored.
+	Next() bool
+
+	// Cancel moves to the previous field of the value, and returns true when
+	// there is none, in which case editing is canceled.
+	Cancel() bool
+}
+
+// Menu is a list of items. A menu can be an item of another menu, which makes
+// it a submenu.
+type Menu struct {
+	Title string
+	Items []Item
+}
+
+// New returns a menu with the given title and items.
+func New(title string, items ...Item) *Menu {
+	return &Menu{Title: title, Items: items}
+}
+
+// Label returns the title of the menu.
+func (m *Menu) Label() string {
+	return m.Title
+}
+
+// AppendValue appends nothing, a submenu has no value.
+func (m *Menu) AppendValue(buf []byte) []byte {
+	return buf
+}
+
+type action struct {
+	label string
+	fn    func()
+}
+
+// Action returns an item that calls fn when it is selected.
+func Action(label string, fn func()) Item {
+	return &action{label: label, fn: fn}
+}
+
+func (a *action) Label() string                 { return a.label }
+func (a *action) AppendValue(buf []byte) []byte { return buf }
+
+func (a *action) Activate() {
+	if a.fn != nil {
+		a.fn()
+	}
+}
+
+type boolItem struct {
+	label    string
+	value    *bool
+	onChange func(bool)
+}
+
+// Bool returns an item that switches a boolean on or off when it is selected.
+// The onChange callback is optional.
+func Bool(label string, value *bool, onChange func(bool)) Item {
+	return &boolItem{label: label, value: value, onChange: onChange}
+}
+
+func (b *boolItem) Label() string { return b.label }
+
+func (b *boolItem) AppendValue(buf []byte) []byte {
+	if *b.value {
+		return append(buf, "on"...)
+	}
+	return append(buf, "off"...)
+}
+
+func (b *boolItem) Activate() {
+	*b.value = !*b.value
+	if b.onChange != nil {
+		b.onChange(*b.value)
+	}
+}
+
+type intItem struct {
+	label          string
+	value          *int
+	min, max, step int
+	onChange       func(int)
+	editing        bool
+	edit           int
+}
+
+// Int returns an editor for an integer between min and max, which changes by
+// step for every Up or Down. The onChange callback is optional, and is called
+// when a new value is confirmed.
+func Int(label string, value *int, min, max, step int, onChange func(int)) Editor {
+	if step <= 0 {
+		step = 1
+	}
+	return &intItem{label: label, value: value, min: min, max: max, step: step, onChange: onChange}
+}
+
+func (i *intItem) Label() string { return i.label }
+
+func (i *intItem) AppendValue(buf []byte) []byte {
+	if i.editing {
+		return strconv.AppendInt(buf, int64(i.edit), 10)
+	}
+	return strconv.AppendInt(buf, int64(*i.value), 10)
+}
+
+func (i *intItem) Edit() {
+	i.edit = clamp(*i.value, i.min, i.max)
+	i.editing = true
+}
+
+func (i *intItem) Step(steps int) {
+	i.edit = clamp(i.edit+steps*i.step, i.min, i.max)
+}
+
+func (i *intItem) Next() bool {
+	i.editing = false
+	if *i.value != i.edit {
+		*i.value = i.edit
+		if i.onChange != nil {
+			i.onChange(i.edit)
+		}
+	}
+	return true
+}
+
+func (i *intItem) Cancel() bool {
+	i.editing = false
+	return true
+}
+
+type enumItem struct {
+	label    string
+	value    *int
+	options  []string
+	onChange func(int)
+	editing  bool
+	edit     int
+}
+
+// Enum returns an editor for a choice between options, where value is the
+// index of the chosen option. The options wrap around. The onChange callback
+// is optional, and is called when a new choice is confirmed.
+func Enum(label string, value *int, options []string, onChange func(int)) Editor {
+	return &enumItem{label: label, value: value, options: options, onChange: onChange}
+}
+
+func (e *enumItem) Label() string { return e.label }
+
+func (e *enumItem) AppendValue(buf []byte) []byte {
+	index := *e.value
+	if e.editing {
+		index = e.edit
+	}
+	if index < 0 || index >= len(e.options) {
+		return append(buf, '?')
+	}
+	return append(buf, e.options[index]...)
+}
+
+func (e *enumItem) Edit() {
+	e.edit = clamp(*e.value, 0, len(e.options)-1)
+	e.editing = true
+}
+
+func (e *enumItem) Step(steps int) {
+	if len(e.options) == 0 {
+		return
+	}
+	e.edit = wrap(e.edit+steps, len(e.options))
+}
+
+func (e *enumItem) Next() bool {
+	e.editing = false
+	if *e.value != e.edit && len(e.options) > 0 {
+		*e.value = e.edit
+		if e.onChange != nil {
+			e.onChange(e.edit)
+		}
+	}
+	return true
+}
+
+func (e *enumItem) Cancel() bool {
+	e.editing = false
+	return true
+}
+
+type timeItem struct {
+	label    string
+	value    *time.Duration
+	onChange func(time.Duration)
+	editing  bool
+	field    int // 0 for hours, 1 for minutes
+	hours    int
+	minutes  int
+}
+
+// Time returns an editor for a time of day in hours and minutes, stored as the
+// duration since midnight. Select moves from the hours to the minutes, and Back
+// from the minutes to the hours. The onChange callback is optional, and is
+// called when a new time is confirmed.
+func Time(label string, value *time.Duration, onChange func(time.Duration)) Editor {
+	return &timeItem{label: label, value: value, onChange: onChange}
+}
+
+func (t *timeItem) Label() string { return t.label }
+
+func (t *timeItem) AppendValue(buf []byte) []byte {
+	hours, minutes := t.hours, t.minutes
+	if !t.editing {
+		hours, minutes = splitTime(*t.value)
+	}
+	// The field being edited is shown in brackets.
+	buf = appendField(buf, hours, t.editing && t.field == 0)
+	buf = append(buf, ':')
+	return appendField(buf, minutes, t.editing && t.field == 1)
+}
+
+func (t *timeItem) Edit() {
+	t.hours, t.minutes = splitTime(*t.value)
+	t.field = 0
+	t.editing = true
+}
+
+func (t *timeItem) Step(steps int) {
+	if t.field == 0 {
+		t.hours = wrap(t.hours+steps, 24)
+	} else {
+		t.minutes = wrap(t.minutes+steps, 60)
+	}
+}
+
+func (t *timeItem) Next() bool {
+	if t.field == 0 {
+		t.field = 1
+		return false
+	}
+	t.editing = false
+	value := time.Duration(t.hours)*time.Hour + time.Duration(t.minutes)*time.Minute
+	if *t.value != value {
+		*t.value = value
+		if t.onChange != nil {
+			t.onChange(value)
+		}
+	}
+	return true
+}
+
+func (t *timeItem) Cancel() bool {
+	if t.field == 1 {
+		t.field = 0
+		return false
+	}
+	t.editing = false
+	return true
+}
+
+// splitTime returns the hours and minutes of a time of day.
+func splitTime(d time.Duration) (hours, minutes int) {
+	minutes = wrap(int(d/time.Minute), 24*60)
+	return minutes / 60, minutes % 60
+}
+
+// appendField appends a two digit number, in brackets when it is edited.
+func appendField(buf []byte, v int, editing bool) []byte {
+	if editing {
+		buf = append(buf, '[')
+	}
+	buf = append(buf, byte('0'+v/10), byte('0'+v%10))
+	if editing {
+		buf = append(buf, ']')
+	}
+	return buf
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// wrap returns v modulo n, in the range 0 to n-1.
+func wrap(v, n int) int {
+	v %= n
+	if v < 0 {
+		v += n
+	}
+	return v
+}
@@ -0,0 +1,247 @@
+package menu
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// textDevice is a character display that keeps its contents in memory.
+type textDevice struct {
+	columns, rows int16
+	cells         [][]byte
+	writes        int
+}
+
+func newTextDevice(columns, rows int16) *textDevice {
+	d := &textDevice{columns: columns, rows: rows}
+	for i := int16(0); i < rows; i++ {
+		d.cells = append(d.cells, []byte(strings.Repeat(" ", int(columns))))
+	}
+	return d
+}
+
+func (d *textDevice) Size() (columns, rows int16) { return d.columns, d.rows }
+func (d *textDevice) ClearDisplay()               {}
+func (d *textDevice) CreateGlyph(code byte, data []byte) error {
+	return nil
+}
+
+func (d *textDevice) WriteAt(column, row int16, text []byte) {
+	copy(d.cells[row][column:], text)
+	d.writes++
+}
+
+func (d *textDevice) String() string {
+	var lines []string
+	for _, row := range d.cells {
+		lines = append(lines, string(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func checkScreen(t *testing.T, n *Navigator, dev *textDevice, want ...string) {
+	t.Helper()
+	if err := n.Draw(); err != nil {
+		t.Fatal(err)
+	}
+	if got := dev.String(); got != strings.Join(want, "\n") {
+		t.Errorf("got screen\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestNavigation(t *testing.T) {
+	var (
+		brightness = 50
+		mode       = 1
+		enabled    bool
+		alarm      = 7*time.Hour + 30*time.Minute
+		saved      int
+		changed    []int
+	)
+	root := New("Settings",
+		Int("Bright", &brightness, 0, 100, 10, func(v int) { changed = append(changed, v) }),
+		Enum("Mode", &mode, []string{"auto", "heat", "cool"}, nil),
+		New("Alarm",
+			Bool("Enabled", &enabled, nil),
+			Time("Time", &alarm, nil),
+		),
+		Action("Save", func() { saved++ }),
+	)
+	dev := newTextDevice(16, 3)
+	n := NewNavigator(root, NewText(dev), Config{ShowTitle: true})
+
+	checkScreen(t, n, dev,
+		"Settings        ",
+		">Bright       50",
+		" Mode       heat")
+
+	// Edit the brightness, then cancel a second edit.
+	n.Handle(Select)
+	n.Scroll(2)
+	checkScreen(t, n, dev,
+		"Settings        ",
+		"*Bright       70",
+		" Mode       heat")
+	n.Handle(Select)
+	n.Handle(Select)
+	n.Handle(Down)
+	n.Handle(Back)
+	checkScreen(t, n, dev,
+		"Settings        ",
+		">Bright       70",
+		" Mode       heat")
+	if brightness != 70 || len(changed) != 1 || changed[0] != 70 {
+		t.Errorf("got brightness %d, changes %v, want 70, [70]", brightness, changed)
+	}
+
+	// The enum wraps around.
+	n.Handle(Down)
+	n.Handle(Select)
+	n.Handle(Up)
+	n.Handle(Up)
+	n.Handle(Select)
+	if mode != 0 {
+		t.Errorf("got mode %d, want 0", mode)
+	}
+
+	// Scroll down to the last item, which runs an action.
+	n.Scroll(5)
+	checkScreen(t, n, dev,
+		"Settings        ",
+		" Alarm          ",
+		">Save           ")
+	n.Handle(Select)
+	if saved != 1 {
+		t.Errorf("action ran %d times, want 1", saved)
+	}
+
+	// Open the submenu and edit the time field by field.
+	n.Handle(Up)
+	n.Handle(Select)
+	if n.Menu().Title != "Alarm" {
+		t.Fatalf("got menu %q, want Alarm", n.Menu().Title)
+	}
+	n.Handle(Select)
+	n.Handle(Down)
+	n.Handle(Select)
+	checkScreen(t, n, dev,
+		"Alarm           ",
+		" Enabled      on",
+		"*Time    [07]:30")
+	n.Handle(Up)
+	n.Handle(Select)
+	n.Handle(Back)
+	n.Handle(Down)
+	n.Handle(Select)
+	n.Scroll(-45)
+	checkScreen(t, n, dev,
+		"Alarm           ",
+		" Enabled      on",
+		"*Time    07:[45]")
+	n.Handle(Select)
+	if want := 7*time.Hour + 45*time.Minute; alarm != want || !enabled {
+		t.Errorf("got alarm %v, enabled %v, want %v, true", alarm, enabled, want)
+	}
+
+	n.Handle(Back)
+	checkScreen(t, n, dev,
+		"Settings        ",
+		">Alarm          ",
+		" Save           ")
+}
+
+func TestWrap(t *testing.T) {
+	root := New("", Action("One", nil), Action("Two", nil), Action("Three", nil))
+	dev := newTextDevice(8, 2)
+	n := NewNavigator(root, NewText(dev), Config{Wrap: true})
+	n.Handle(Up)
+	checkScreen(t, n, dev,
+		" Two    ",
+		">Three  ")
+	n.Handle(Down)
+	checkScreen(t, n, dev,
+		">One    ",
+		" Two    ")
+
+	// Unchanged rows aren't written again.
+	writes := dev.writes
+	n.Invalidate()
+	checkScreen(t, n, dev,
+		">One    ",
+		" Two    ")
+	if dev.writes != writes {
+		t.Errorf("got %d writes for an unchanged screen, want 0", dev.writes-writes)
+	}
+}
+
+func TestTextLongLabel(t *testing.T) {
+	value := 12345
+	root := New("", Int("Very long label", &value, 0, 99999, 1, nil))
+	dev := newTextDevice(12, 1)
+	n := NewNavigator(root, NewText(dev), Config{})
+	checkScreen(t, n, dev, ">Very  12345")
+}
+
+// bitmapDisplay is a pixel display that keeps its contents in memory.
+type bitmapDisplay struct {
+	img     pixel.Image[pixel.Monochrome]
+	flushes int
+}
+
+func (d *bitmapDisplay) Size() (x, y int16) {
+	width, height := d.img.Size()
+	return int16(width), int16(height)
+}
+
+func (d *bitmapDisplay) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.Monochrome]) error {
+	width, height := bitmap.Size()
+	for py := 0; py < height; py++ {
+		for px := 0; px < width; px++ {
+			d.img.Set(int(x)+px, int(y)+py, bitmap.Get(px, py))
+		}
+	}
+	return nil
+}
+
+func (d *bitmapDisplay) Display() error {
+	d.flushes++
+	return nil
+}
+
+func TestPixel(t *testing.T) {
+	display := &bitmapDisplay{img: pixel.NewImage[pixel.Monochrome](32, 20)}
+	f := &font.Font{BitsPerPixel: 1, Ascent: 5, Descent: -1, YAdvance: 8}
+	r := NewPixel[pixel.Monochrome](display, PixelConfig{Font: f})
+	if r.Rows() != 2 {
+		t.Fatalf("got %d rows, want 2", r.Rows())
+	}
+	value := 1
+	root := New("", Action("One", nil), Int("Two", &value, 0, 9, 1, nil))
+	n := NewNavigator(root, r, Config{})
+	n.Handle(Down)
+	n.Handle(Select)
+	if err := n.Draw(); err != nil {
+		t.Fatal(err)
+	}
+	if display.flushes != 1 {
+		t.Errorf("got %d flushes, want 1", display.flushes)
+	}
+	on := pixel.NewColor[pixel.Monochrome](255, 255, 255)
+	for _, tc := range []struct {
+		x, y int
+		on   bool
+	}{
+		{0, 0, false},   // first row, not selected
+		{0, 8, false},   // label of the row being edited
+		{31, 8, true},   // value of the row being edited
+		{31, 16, false}, // below the rows
+	} {
+		if got := display.img.Get(tc.x, tc.y) == on; got != tc.on {
+			t.Errorf("pixel %d,%d: got %v, want %v", tc.x, tc.y, got, tc.on)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package menu
+
+// MaxDepth is the maximum nesting depth of submenus.
+const MaxDepth = 8
+
+// Style is how a row is drawn.
+type Style uint8
+
+// Row styles.
+const (
+	// Normal is an item that isn't selected.
+	Normal Style = iota
+
+	// Selected is the selected item.
+	Selected
+
+	// Editing is the selected item while its value is edited.
+	Editing
+
+	// Title is the title of the menu.
+	Title
+)
+
+// Renderer draws menus on a display, one row of text at a time.
+type Renderer interface {
+	// Rows returns the number of rows that fit on the display.
+	Rows() int
+
+	// DrawRow draws a row, with the label on the left and the value on the
+	// right. Rows without an item have an empty label and value.
+	DrawRow(row int, label string, value []byte, style Style) error
+
+	// Flush shows the rows drawn since the previous call, for displays with
+	// a buffer.
+	Flush() error
+}
+
+// Config is the configuration of a Navigator.
+type Config struct {
+	// ShowTitle shows the title of the current menu on the first row.
+	ShowTitle bool
+
+	// Wrap makes the selection wrap around from the last item to the first
+	// and back.
+	Wrap bool
+}
+
+// frame is a menu on the stack of open menus.
+type frame struct {
+	menu     *Menu
+	selected int
+	top      int // first visible item
+}
+
+// Navigator shows a menu and its submenus on a renderer, and changes the
+// selection, opens submenus and edits values in response to events.
+type Navigator struct {
+	renderer Renderer
+	config   Config
+	stack    [MaxDepth]frame
+	depth    int
+	editor   Editor // item being edited, if any
+	dirty    bool
+	value    []byte
+}
+
+// NewNavigator returns a navigator that shows the root menu on the renderer.
+func NewNavigator(root *Menu, renderer Renderer, config Config) *Navigator {
+	n := &Navigator{
+		renderer: renderer,
+		config:   config,
+		dirty:    true,
+	}
+	n.stack[0].menu = root
+	return n
+}
+
+// Menu returns the menu that is currently shown.
+func (n *Navigator) Menu() *Menu {
+	return n.stack[n.depth].menu
+}
+
+// Selected returns the selected item of the current menu, or nil if the menu
+// is empty.
+func (n *Navigator) Selected() Item {
+	f := &n.stack[n.depth]
+	if f.selected >= len(f.menu.Items) {
+		return nil
+	}
+	return f.menu.Items[f.selected]
+}
+
+// Editing returns whether the value of the selected item is being edited.
+func (n *Navigator) Editing() bool {
+	return n.editor != nil
+}
+
+// Handle handles an event.
+func (n *Navigator) Handle(event Event) {
+	switch event {
+	case Up:
+		n.step(1, -1)
+	case Down:
+		n.step(-1, 1)
+	case Select:
+		n.selectItem()
+	case Back:
+		n.back()
+	}
+}
+
+// Scroll handles a rotary encoder that turned by the given number of steps.
+// Turning clockwise (positive steps) selects the next item, like Down, but
+// increases the value being edited, like Up.
+func (n *Navigator) Scroll(steps int) {
+	if steps != 0 {
+		n.step(steps, steps)
+	}
+}
+
+// step changes the value being edited by value steps, or otherwise moves the
+// selection by items.
+func (n *Navigator) step(value, items int) {
+	n.dirty = true
+	if n.editor != nil {
+		n.editor.Step(value)
+		return
+	}
+	f := &n.stack[n.depth]
+	count := len(f.menu.Items)
+	if count == 0 {
+		return
+	}
+	if n.config.Wrap {
+		f.selected = wrap(f.selected+items, count)
+	} else {
+		f.selected = clamp(f.selected+items, 0, count-1)
+	}
+}
+
+// Reset returns to the root menu, and cancels editing.
+func (n *Navigator) Reset() {
+	if n.editor != nil {
+		for !n.editor.Cancel() {
+		}
+		n.editor = nil
+	}
+	n.depth = 0
+	n.dirty = true
+}
+
+// Invalidate redraws the menu on the next call to Draw, for example after
+// something else has drawn on the display.
+func (n *Navigator) Invalidate() {
+	n.dirty = true
+}
+
+func (n *Navigator) selectItem() {
+	n.dirty = true
+	if n.editor != nil {
+		if n.editor.Next() {
+			n.editor = nil
+		}
+		return
+	}
+	switch item := n.Selected().(type) {
+	case *Menu:
+		if n.depth+1 < MaxDepth {
+			n.depth++
+			n.stack[n.depth] = frame{menu: item}
+		}
+	case Editor:
+		item.Edit()
+		n.editor = item
+	case Activator:
+		item.Activate()
+	}
+}
+
+func (n *Navigator) back() {
+	n.dirty = true
+	if n.editor != nil {
+		if n.editor.Cancel() {
+			n.editor = nil
+		}
+		return
+	}
+	if n.depth > 0 {
+		n.depth--
+	}
+}
+
+// Draw draws the current menu if anything changed since the previous call.
+func (n *Navigator) Draw() error {
+	if !n.dirty {
+		return nil
+	}
+	f := &n.stack[n.depth]
+	rows := n.renderer.Rows()
+	row := 0
+	if n.config.ShowTitle && rows > 1 {
+		err := n.renderer.DrawRow(0, f.menu.Title, nil, Title)
+		if err != nil {
+			return err
+		}
+		row = 1
+	}
+
+	// Scroll the selected item into view.
+	visible := rows - row
+	if f.selected < f.top {
+		f.top = f.selected
+	} else if f.selected >= f.top+visible {
+		f.top = f.selected - visible + 1
+	}
+
+	for i := f.top; row < rows; i, row = i+1, row+1 {
+		var err error
+		if i < len(f.menu.Items) {
+			item := f.menu.Items[i]
+			style := Normal
+			if i == f.selected {
+				style = Selected
+				if n.editor != nil {
+					style = Editing
+				}
+			}
+			n.value = item.AppendValue(n.value[:0])
+			err = n.renderer.DrawRow(row, item.Label(), n.value, style)
+		} else {
+			err = n.renderer.DrawRow(row, "", nil, Normal)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	n.dirty = false
+	return n.renderer.Flush()
+}
@@ -0,0 +1,211 @@
+package menu
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/textdisplay"
+)
+
+// TextRenderer draws menus on a character display, such as an HD44780 LCD. The
+// selected item is marked with '>', or with '*' while its value is edited.
+// Only rows that changed are written to the display.
+type TextRenderer struct {
+	dev     textdisplay.Device
+	columns int
+	rows    int
+	shown   []byte // characters on the display
+	line    []byte
+}
+
+// NewText returns a renderer for a character display. The display is not
+// cleared.
+func NewText(dev textdisplay.Device) *TextRenderer {
+	columns, rows := dev.Size()
+	r := &TextRenderer{
+		dev:     dev,
+		columns: int(columns),
+		rows:    int(rows),
+		shown:   make([]byte, int(columns)*int(rows)),
+		line:    make([]byte, columns),
+	}
+	return r
+}
+
+// Rows returns the number of rows of the display.
+func (r *TextRenderer) Rows() int {
+	return r.rows
+}
+
+// DrawRow writes a row to the display if it changed.
+func (r *TextRenderer) DrawRow(row int, label string, value []byte, style Style) error {
+	if row < 0 || row >= r.rows || r.columns == 0 {
+		return nil
+	}
+	line := r.line
+	for i := range line {
+		line[i] = ' '
+	}
+	text := line
+	switch style {
+	case Selected:
+		line[0] = '>'
+		text = line[1:]
+	case Editing:
+		line[0] = '*'
+		text = line[1:]
+	case Normal:
+		text = line[1:]
+	}
+
+	// The value is right aligned, and the label is cut off when it doesn't
+	// fit in front of it.
+	if len(value) > len(text) {
+		value = value[:len(text)]
+	}
+	copy(text[len(text)-len(value):], value)
+	labelWidth := len(text) - len(value)
+	if len(value) > 0 {
+		labelWidth--
+	}
+	if labelWidth > 0 {
+		copy(text[:labelWidth], label)
+	}
+
+	shown := r.shown[row*r.columns : (row+1)*r.columns]
+	if string(shown) == string(line) {
+		return nil
+	}
+	copy(shown, line)
+	r.dev.WriteAt(0, int16(row), line)
+	return nil
+}
+
+// Flush does nothing, the rows are written immediately.
+func (r *TextRenderer) Flush() error {
+	return nil
+}
+
+// Displayer is a display that can draw image buffers, such as the ssd1306,
+// st7789 and ili9341.
+type Displayer[T pixel.Color] interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+
+	// DrawBitmap copies the image to the display at the given coordinates.
+	DrawBitmap(x, y int16, bitmap pixel.Image[T]) error
+}
+
+// PixelConfig is the configuration of a PixelRenderer.
+type PixelConfig struct {
+	// Font used for all text. Required.
+	Font *font.Font
+
+	// Colors of the menu. The default is white text on a black background.
+	// The selected item is drawn with the colors swapped.
+	Foreground color.RGBA
+	Background color.RGBA
+
+	// Left and right margin of the text in pixels, 2 by default.
+	Padding int
+}
+
+// PixelRenderer draws menus on a pixel display, row by row. The selected item
+// is drawn inverted, and while its value is edited only the value is inverted.
+// Displays with a buffer, such as the ssd1306, are updated in Flush.
+type PixelRenderer[T pixel.Color] struct {
+	display   Displayer[T]
+	config    PixelConfig
+	buf       pixel.Image[T]
+	width     int
+	rowHeight int
+	rows      int
+}
+
+// NewPixel returns a renderer for a pixel display. It panics when no font is
+// configured.
+func NewPixel[T pixel.Color](display Displayer[T], config PixelConfig) *PixelRenderer[T] {
+	if config.Font == nil {
+		panic("menu: no font configured")
+	}
+	if config.Background == (color.RGBA{}) && config.Foreground == (color.RGBA{}) {
+		config.Background = color.RGBA{A: 255}
+		config.Foreground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	if config.Padding == 0 {
+		config.Padding = 2
+	}
+	width, height := display.Size()
+	rowHeight := config.Font.LineHeight()
+	return &PixelRenderer[T]{
+		display:   display,
+		config:    config,
+		buf:       pixel.NewImage[T](int(width), rowHeight),
+		width:     int(width),
+		rowHeight: rowHeight,
+		rows:      int(height) / rowHeight,
+	}
+}
+
+// Rows returns the number of rows that fit on the display.
+func (r *PixelRenderer[T]) Rows() int {
+	return r.rows
+}
+
+// DrawRow draws a row into the display.
+func (r *PixelRenderer[T]) DrawRow(row int, label string, value []byte, style Style) error {
+	if row < 0 || row >= r.rows {
+		return nil
+	}
+	f := r.config.Font
+	fg, bg := r.config.Foreground, r.config.Background
+	if style == Selected {
+		fg, bg = bg, fg
+	}
+	textHeight := int(f.Ascent) - int(f.Descent)
+	baseline := (r.rowHeight-textHeight)/2 + int(f.Ascent)
+	padding := r.config.Padding
+
+	r.fill(0, 0, r.width, r.rowHeight, bg)
+	font.Draw(r.buf, f, padding, baseline, label, fg)
+	if len(value) > 0 {
+		// The value is right aligned, and drawn over the end of a label that
+		// is too long.
+		text := string(value)
+		x := r.width - padding - font.LineWidth(f, text)
+		valueFg, valueBg := fg, bg
+		if style == Editing {
+			valueFg, valueBg = bg, fg
+		}
+		r.fill(x-padding, 0, r.width, r.rowHeight, valueBg)
+		font.Draw(r.buf, f, x, baseline, text, valueFg)
+	}
+	if style == Title {
+		r.fill(0, r.rowHeight-1, r.width, r.rowHeight, fg)
+	}
+	return r.display.DrawBitmap(0, int16(row*r.rowHeight), r.buf)
+}
+
+// Flush updates displays with a buffer.
+func (r *PixelRenderer[T]) Flush() error {
+	if d, ok := r.display.(interface{ Display() error }); ok {
+		return d.Display()
+	}
+	return nil
+}
+
+// fill fills a rectangle of the row buffer.
+func (r *PixelRenderer[T]) fill(x0, y0, x1, y1 int, c color.RGBA) {
+	x0 = max(x0, 0)
+	value := pixel.NewColor[T](c.R, c.G, c.B)
+	if x0 == 0 && y0 == 0 && x1 == r.width && y1 == r.rowHeight {
+		r.buf.FillSolidColor(value)
+		return
+	}
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			r.buf.Set(x, y, value)
+		}
+	}
+}
@@ -7,6 +7,8 @@ package microbitmatrix // import "tinygo.org/x/drivers/microbitmatrix"
 
 import (
 	"machine"
+
+	"tinygo.org/x/drivers"
 )
 
 // 4 rotation orientations (0, 90, 180, 270), CW (clock wise)
@@ -52,7 +54,7 @@ const (
 type Device struct {
 	pin      [ledCols + ledRows]machine.Pin
 	buffer   [ledRows][ledCols]int8
-	rotation uint8
+	rotation drivers.Rotation
 }
 
 func (d *Device) assignPins() {
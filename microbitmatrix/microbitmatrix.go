@@ -3,6 +3,8 @@ package microbitmatrix // import "tinygo.org/x/drivers/microbitmatrix"
 import (
 	"image/color"
 	"time"
+
+	"tinygo.org/x/drivers"
 )
 
 type Config struct {
@@ -14,7 +16,9 @@ type Config struct {
 	//     1: 90 degree rotation clock wise
 	//     2: 180 degree rotation clock wise
 	//     3: 270 degree rotation clock wise
-	Rotation uint8
+	//
+	// The mirrored rotations of drivers.Rotation are supported too.
+	Rotation drivers.Rotation
 }
 
 const (
@@ -47,8 +51,16 @@ func (d *Device) Configure(cfg Config) {
 //	1: 90 degree rotation clock wise
 //	2: 180 degree rotation clock wise
 //	3: 270 degree rotation clock wise
-func (d *Device) SetRotation(rotation uint8) {
-	d.rotation = rotation % 4
+//
+// The mirrored rotations of drivers.Rotation are supported too.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
+}
+
+// Rotation returns the current rotation of the LED matrix.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
 }
 
 // Source:
@@ -94,12 +106,11 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 	if x < 0 || x >= 5 || y < 0 || y >= 5 {
 		return
 	}
-	col := x
-	row := y
+	col, row := d.position(x, y)
 	if c.R != 0 || c.G != 0 || c.B != 0 {
-		d.buffer[matrixRotations[d.rotation][row][col][rowIdx]][matrixRotations[d.rotation][row][col][colIdx]] = brightness(c.A)
+		d.buffer[row][col] = brightness(c.A)
 	} else {
-		d.buffer[matrixRotations[d.rotation][row][col][rowIdx]][matrixRotations[d.rotation][row][col][colIdx]] = 0
+		d.buffer[row][col] = 0
 	}
 }
 
@@ -133,9 +144,17 @@ func (d *Device) GetPixel(x int16, y int16) bool {
 	if x < 0 || x >= 5 || y < 0 || y >= 5 {
 		return false
 	}
-	col := x
-	row := y
-	return d.buffer[matrixRotations[d.rotation][row][col][rowIdx]][matrixRotations[d.rotation][row][col][colIdx]] > 0
+	col, row := d.position(x, y)
+	return d.buffer[row][col] > 0
+}
+
+// position returns the column and row in the buffer of a pixel.
+func (d *Device) position(x, y int16) (col, row uint8) {
+	if d.rotation.IsMirrored() {
+		x = 4 - x
+	}
+	target := matrixRotations[d.rotation%4][y][x]
+	return target[colIdx], target[rowIdx]
 }
 
 const displayRefreshDelay = 8 * time.Millisecond
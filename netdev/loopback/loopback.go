@@ -0,0 +1,669 @@
+// Package loopback implements a network device for testing network code on
+// the host, without hardware.
+//
+// A Device implements both netdev.Netdever and netlink.Netlinker, like the
+// drivers for network co-processors (espat, wifinina, rtl8720dn, comboat), so
+// application code written against these interfaces can run in regular Go
+// tests.
+//
+// By default, all sockets live in memory. The test reaches the sockets of the
+// device through ListenTCP, DialTCP and ListenUDP, which play the part of the
+// servers and clients on the network:
+//
+//	dev := loopback.New(loopback.Config{})
+//	dev.NetConnect(nil)
+//	broker, _ := dev.ListenTCP(netip.MustParseAddrPort("10.0.0.1:1883"))
+//	dev.SetHost("broker.local", netip.MustParseAddr("10.0.0.1"))
+//	// The code under test connects to broker.local:1883 through dev, and
+//	// the test accepts the connection from broker.
+//
+// With Config.Host set, sockets use the network stack of the host instead, to
+// talk to real servers.
+//
+// Failures are injected with SetHostError, FailConnect, SetLatency and
+// SetLinkUp, which also sends the network events registered with NetNotify.
+package loopback // import "tinygo.org/x/drivers/netdev/loopback"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"tinygo.org/x/drivers/netdev"
+	"tinygo.org/x/drivers/netlink"
+)
+
+var (
+	// ErrNetDown is returned by socket calls while the network is down.
+	ErrNetDown = errors.New("loopback: network is down")
+
+	errRefused     = errors.New("loopback: connection refused")
+	errNotBound    = errors.New("loopback: socket not bound")
+	errNotListener = errors.New("loopback: socket not listening")
+	errAddrInUse   = errors.New("loopback: address in use")
+)
+
+// Config is the configuration of a Device.
+type Config struct {
+	// Host makes sockets use the network stack of the host, instead of
+	// sockets in memory.
+	Host bool
+
+	// Addr is the IP address of the device, 127.0.0.1 by default.
+	Addr netip.Addr
+
+	// HardwareAddr is the MAC address of the device, 02:00:00:00:00:01 by
+	// default.
+	HardwareAddr net.HardwareAddr
+
+	// MaxSockets is the number of sockets that can be open at the same time,
+	// 10 by default, like most network co-processors.
+	MaxSockets int
+
+	// Backlog is the number of connections that can wait to be accepted by a
+	// listening socket in memory, 4 by default.
+	Backlog int
+}
+
+// socket is an open socket of the device.
+type socket struct {
+	stype    int
+	protocol int
+	local    netip.AddrPort
+	remote   netip.AddrPort
+	conn     net.Conn       // connected stream socket
+	listener net.Listener   // listening stream socket
+	packet   net.PacketConn // datagram socket
+}
+
+// Device is a network device in memory or on top of the network stack of the
+// host.
+type Device struct {
+	config Config
+
+	mu         sync.Mutex
+	up         bool
+	notify     func(netlink.Event)
+	connectErr error
+	latency    time.Duration
+	hosts      map[string]netip.Addr
+	hostErrors map[string]error
+	failures   map[netip.AddrPort]error
+	sockets    map[int]*socket
+	listeners  map[netip.AddrPort]*memListener
+	packets    map[netip.AddrPort]*memPacketConn
+	nextPort   uint16
+	nextFd     int
+}
+
+// New returns a new device. The network is down until NetConnect is called.
+func New(config Config) *Device {
+	if !config.Addr.IsValid() {
+		config.Addr = netip.AddrFrom4([4]byte{127, 0, 0, 1})
+	}
+	if config.HardwareAddr == nil {
+		config.HardwareAddr = net.HardwareAddr{0x02, 0, 0, 0, 0, 0x01}
+	}
+	if config.MaxSockets <= 0 {
+		config.MaxSockets = 10
+	}
+	if config.Backlog <= 0 {
+		config.Backlog = 4
+	}
+	return &Device{
+		config:     config,
+		hosts:      map[string]netip.Addr{"localhost": netip.AddrFrom4([4]byte{127, 0, 0, 1})},
+		hostErrors: make(map[string]error),
+		failures:   make(map[netip.AddrPort]error),
+		sockets:    make(map[int]*socket),
+		listeners:  make(map[netip.AddrPort]*memListener),
+		packets:    make(map[netip.AddrPort]*memPacketConn),
+		nextPort:   49152,
+	}
+}
+
+// SetHost makes GetHostByName return addr for name.
+func (d *Device) SetHost(name string, addr netip.Addr) {
+	d.mu.Lock()
+	d.hosts[name] = addr
+	delete(d.hostErrors, name)
+	d.mu.Unlock()
+}
+
+// SetHostError makes GetHostByName fail with err for name. A nil error
+// removes the failure.
+func (d *Device) SetHostError(name string, err error) {
+	d.mu.Lock()
+	if err != nil {
+		d.hostErrors[name] = err
+	} else {
+		delete(d.hostErrors, name)
+	}
+	d.mu.Unlock()
+}
+
+// FailConnect makes connections to addr fail with err. A nil error removes
+// the failure.
+func (d *Device) FailConnect(addr netip.AddrPort, err error) {
+	d.mu.Lock()
+	if err != nil {
+		d.failures[addr] = err
+	} else {
+		delete(d.failures, addr)
+	}
+	d.mu.Unlock()
+}
+
+// SetConnectError makes NetConnect fail with err. A nil error removes the
+// failure.
+func (d *Device) SetConnectError(err error) {
+	d.mu.Lock()
+	d.connectErr = err
+	d.mu.Unlock()
+}
+
+// SetLatency delays every name lookup, connection and send by the given
+// duration.
+func (d *Device) SetLatency(latency time.Duration) {
+	d.mu.Lock()
+	d.latency = latency
+	d.mu.Unlock()
+}
+
+// SetLinkUp brings the network up or down, as if the WiFi connection came
+// back or was lost, and sends EventNetUp or EventNetDown to the callback of
+// NetNotify. Taking the network down closes all sockets.
+func (d *Device) SetLinkUp(up bool) {
+	d.mu.Lock()
+	if d.up == up {
+		d.mu.Unlock()
+		return
+	}
+	d.up = up
+	notify := d.notify
+	var closers []io.Closer
+	if !up {
+		for _, s := range d.sockets {
+			closers = append(closers, s.closers()...)
+		}
+	}
+	d.mu.Unlock()
+	for _, c := range closers {
+		c.Close()
+	}
+	if notify != nil {
+		if up {
+			notify(netlink.EventNetUp)
+		} else {
+			notify(netlink.EventNetDown)
+		}
+	}
+}
+
+// Sockets returns the number of open sockets, to check for leaks.
+func (d *Device) Sockets() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.sockets)
+}
+
+// ListenTCP returns a listener for stream connections from the sockets of the
+// device to addr, which acts as a server on the network.
+func (d *Device) ListenTCP(addr netip.AddrPort) (net.Listener, error) {
+	if d.config.Host {
+		return net.Listen("tcp", addr.String())
+	}
+	return d.listen(addr)
+}
+
+// DialTCP connects to a listening socket of the device, as a client on the
+// network. In memory, the client has address 10.0.0.2.
+func (d *Device) DialTCP(addr netip.AddrPort) (net.Conn, error) {
+	if d.config.Host {
+		return net.Dial("tcp", addr.String())
+	}
+	l := d.lookupListener(addr)
+	if l == nil {
+		return nil, errRefused
+	}
+	return l.connect(d.ephemeral(netip.AddrFrom4([4]byte{10, 0, 0, 2})))
+}
+
+// ListenUDP returns a datagram endpoint at addr on the network, which can
+// send datagrams to and receive datagrams from the sockets of the device.
+func (d *Device) ListenUDP(addr netip.AddrPort) (net.PacketConn, error) {
+	if d.config.Host {
+		return net.ListenUDP("udp", net.UDPAddrFromAddrPort(addr))
+	}
+	return d.listenPacket(addr)
+}
+
+// NetConnect brings the network up, unless SetConnectError made it fail.
+func (d *Device) NetConnect(params *netlink.ConnectParams) error {
+	d.mu.Lock()
+	err := d.connectErr
+	up := d.up
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if up {
+		return netlink.ErrConnected
+	}
+	d.SetLinkUp(true)
+	return nil
+}
+
+// NetDisconnect takes the network down.
+func (d *Device) NetDisconnect() {
+	d.SetLinkUp(false)
+}
+
+// NetNotify registers a callback for network events.
+func (d *Device) NetNotify(cb func(netlink.Event)) {
+	d.mu.Lock()
+	d.notify = cb
+	d.mu.Unlock()
+}
+
+// GetHardwareAddr returns the MAC address of the device.
+func (d *Device) GetHardwareAddr() (net.HardwareAddr, error) {
+	return d.config.HardwareAddr, nil
+}
+
+// GetHostByName returns the address set with SetHost, or the address in name.
+// On the host network, other names are looked up with the resolver of the
+// host.
+func (d *Device) GetHostByName(name string) (netip.Addr, error) {
+	d.delay()
+	d.mu.Lock()
+	addr, ok := d.hosts[name]
+	err := d.hostErrors[name]
+	d.mu.Unlock()
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if ok {
+		return addr, nil
+	}
+	if addr, err := netip.ParseAddr(name); err == nil {
+		return addr, nil
+	}
+	if d.config.Host {
+		addrs, err := net.DefaultResolver.LookupNetIP(context.Background(), "ip4", name)
+		if err == nil && len(addrs) > 0 {
+			return addrs[0], nil
+		}
+	}
+	return netip.Addr{}, netdev.ErrHostUnknown
+}
+
+// Addr returns the IP address of the device.
+func (d *Device) Addr() (netip.Addr, error) {
+	return d.config.Addr, nil
+}
+
+// Socket creates a TCP or UDP socket. TLS isn't supported, see package tlsdev.
+func (d *Device) Socket(domain int, stype int, protocol int) (int, error) {
+	switch {
+	case domain != netdev.AF_INET:
+		return -1, netdev.ErrFamilyNotSupported
+	case stype == netdev.SOCK_STREAM && protocol == netdev.IPPROTO_TCP:
+	case stype == netdev.SOCK_DGRAM && protocol == netdev.IPPROTO_UDP:
+	default:
+		return -1, netdev.ErrProtocolNotSupported
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.up {
+		return -1, ErrNetDown
+	}
+	if len(d.sockets) >= d.config.MaxSockets {
+		return -1, netdev.ErrNoMoreSockets
+	}
+	d.nextFd++
+	d.sockets[d.nextFd] = &socket{stype: stype, protocol: protocol}
+	return d.nextFd, nil
+}
+
+// Bind sets the local address of a socket. For UDP sockets, it starts
+// receiving datagrams.
+func (d *Device) Bind(sockfd int, ip netip.AddrPort) error {
+	s, err := d.socket(sockfd)
+	if err != nil {
+		return err
+	}
+	s.local = ip
+	if s.protocol == netdev.IPPROTO_UDP {
+		return d.openPacket(s)
+	}
+	return nil
+}
+
+// Connect connects a socket to a server. For UDP sockets, it sets the
+// destination of Send.
+func (d *Device) Connect(sockfd int, host string, ip netip.AddrPort) error {
+	s, err := d.socket(sockfd)
+	if err != nil {
+		return err
+	}
+	d.delay()
+	d.mu.Lock()
+	err = d.failures[ip]
+	d.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.remote = ip
+	if s.protocol == netdev.IPPROTO_UDP {
+		if s.packet != nil {
+			return nil
+		}
+		return d.openPacket(s)
+	}
+	var conn net.Conn
+	if d.config.Host {
+		conn, err = net.Dial("tcp", ip.String())
+	} else if l := d.lookupListener(ip); l != nil {
+		conn, err = l.connect(d.ephemeral(d.config.Addr))
+	} else {
+		err = errRefused
+	}
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	s.conn = conn
+	d.mu.Unlock()
+	return nil
+}
+
+// Listen makes a bound TCP socket accept connections.
+func (d *Device) Listen(sockfd int, backlog int) error {
+	s, err := d.socket(sockfd)
+	if err != nil {
+		return err
+	}
+	if s.protocol != netdev.IPPROTO_TCP {
+		return netdev.ErrProtocolNotSupported
+	}
+	if !s.local.IsValid() {
+		return errNotBound
+	}
+	var listener net.Listener
+	if d.config.Host {
+		listener, err = net.Listen("tcp", s.local.String())
+	} else {
+		listener, err = d.listen(s.local)
+	}
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	s.listener = listener
+	d.mu.Unlock()
+	return nil
+}
+
+// Accept waits for a connection on a listening socket, and returns a new
+// socket for it.
+func (d *Device) Accept(sockfd int) (int, netip.AddrPort, error) {
+	s, err := d.socket(sockfd)
+	if err != nil {
+		return -1, netip.AddrPort{}, err
+	}
+	if s.listener == nil {
+		return -1, netip.AddrPort{}, errNotListener
+	}
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return -1, netip.AddrPort{}, err
+	}
+	remote, _ := netip.ParseAddrPort(conn.RemoteAddr().String())
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.sockets) >= d.config.MaxSockets {
+		conn.Close()
+		return -1, netip.AddrPort{}, netdev.ErrNoMoreSockets
+	}
+	d.nextFd++
+	d.sockets[d.nextFd] = &socket{
+		stype:    s.stype,
+		protocol: s.protocol,
+		local:    s.local,
+		remote:   remote,
+		conn:     conn,
+	}
+	return d.nextFd, remote, nil
+}
+
+// Send sends data on a connected socket.
+func (d *Device) Send(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	s, err := d.socket(sockfd)
+	if err != nil {
+		return -1, err
+	}
+	d.delay()
+	var n int
+	switch {
+	case s.conn != nil:
+		s.conn.SetWriteDeadline(deadline)
+		n, err = s.conn.Write(buf)
+	case s.packet != nil && s.remote.IsValid():
+		s.packet.SetWriteDeadline(deadline)
+		n, err = s.packet.WriteTo(buf, net.UDPAddrFromAddrPort(s.remote))
+	default:
+		return -1, net.ErrClosed
+	}
+	if err != nil {
+		return -1, mapError(err)
+	}
+	return n, nil
+}
+
+// Recv receives data from a socket. It returns io.EOF when the other end
+// closed the connection.
+func (d *Device) Recv(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	s, err := d.socket(sockfd)
+	if err != nil {
+		return -1, err
+	}
+	var n int
+	switch {
+	case s.conn != nil:
+		s.conn.SetReadDeadline(deadline)
+		n, err = s.conn.Read(buf)
+	case s.packet != nil:
+		s.packet.SetReadDeadline(deadline)
+		n, _, err = s.packet.ReadFrom(buf)
+	default:
+		return -1, net.ErrClosed
+	}
+	if n > 0 {
+		return n, nil
+	}
+	if err != nil {
+		return -1, mapError(err)
+	}
+	return n, nil
+}
+
+// Close closes a socket.
+func (d *Device) Close(sockfd int) error {
+	d.mu.Lock()
+	s, ok := d.sockets[sockfd]
+	delete(d.sockets, sockfd)
+	d.mu.Unlock()
+	if !ok {
+		return netdev.ErrInvalidSocketFd
+	}
+	for _, c := range s.closers() {
+		c.Close()
+	}
+	return nil
+}
+
+// SetSockOpt accepts the options used by the net package, and ignores them.
+func (d *Device) SetSockOpt(sockfd int, level int, opt int, value interface{}) error {
+	_, err := d.socket(sockfd)
+	if err != nil {
+		return err
+	}
+	switch {
+	case level == netdev.SOL_SOCKET && opt == netdev.SO_KEEPALIVE:
+	case level == netdev.SOL_TCP && opt == netdev.TCP_KEEPINTVL:
+	default:
+		return netdev.ErrNotSupported
+	}
+	return nil
+}
+
+func (d *Device) socket(sockfd int) (*socket, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s, ok := d.sockets[sockfd]
+	if !ok {
+		return nil, netdev.ErrInvalidSocketFd
+	}
+	if !d.up {
+		return nil, ErrNetDown
+	}
+	return s, nil
+}
+
+// closers returns everything that must be closed to close the socket.
+func (s *socket) closers() []io.Closer {
+	var closers []io.Closer
+	if s.conn != nil {
+		closers = append(closers, s.conn)
+	}
+	if s.listener != nil {
+		closers = append(closers, s.listener)
+	}
+	if s.packet != nil {
+		closers = append(closers, s.packet)
+	}
+	return closers
+}
+
+// openPacket starts receiving datagrams on a UDP socket, at its local address
+// or a free port.
+func (d *Device) openPacket(s *socket) error {
+	var packet net.PacketConn
+	var err error
+	local := s.local
+	if d.config.Host {
+		packet, err = net.ListenUDP("udp", net.UDPAddrFromAddrPort(local))
+	} else {
+		if !local.IsValid() || local.Port() == 0 {
+			local = d.ephemeral(d.config.Addr)
+		}
+		packet, err = d.listenPacket(local)
+	}
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	s.packet = packet
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *Device) delay() {
+	d.mu.Lock()
+	latency := d.latency
+	d.mu.Unlock()
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+}
+
+// ephemeral returns an address with a free port, for the local end of a
+// connection.
+func (d *Device) ephemeral(addr netip.Addr) netip.AddrPort {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	port := d.nextPort
+	d.nextPort++
+	if d.nextPort == 0 {
+		d.nextPort = 49152
+	}
+	return netip.AddrPortFrom(addr, port)
+}
+
+func (d *Device) listen(addr netip.AddrPort) (*memListener, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.listeners[addr] != nil {
+		return nil, errAddrInUse
+	}
+	l := &memListener{
+		dev:   d,
+		addr:  addr,
+		conns: make(chan net.Conn, d.config.Backlog),
+		done:  make(chan struct{}),
+	}
+	d.listeners[addr] = l
+	return l, nil
+}
+
+func (d *Device) listenPacket(addr netip.AddrPort) (*memPacketConn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.packets[addr] != nil {
+		return nil, errAddrInUse
+	}
+	p := &memPacketConn{
+		dev:     d,
+		addr:    addr,
+		packets: make(chan packet, 16),
+		done:    make(chan struct{}),
+	}
+	d.packets[addr] = p
+	return p, nil
+}
+
+// lookupListener returns the listener for addr, which may be listening on
+// all addresses.
+func (d *Device) lookupListener(addr netip.AddrPort) *memListener {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if l := d.listeners[addr]; l != nil {
+		return l
+	}
+	return d.listeners[netip.AddrPortFrom(netip.IPv4Unspecified(), addr.Port())]
+}
+
+func (d *Device) lookupPacket(addr netip.AddrPort) *memPacketConn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if p := d.packets[addr]; p != nil {
+		return p
+	}
+	return d.packets[netip.AddrPortFrom(netip.IPv4Unspecified(), addr.Port())]
+}
+
+// unregister removes a listener or datagram endpoint that was closed.
+func (d *Device) unregister(addr netip.AddrPort, c io.Closer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if l, ok := c.(*memListener); ok && d.listeners[addr] == l {
+		delete(d.listeners, addr)
+	}
+	if p, ok := c.(*memPacketConn); ok && d.packets[addr] == p {
+		delete(d.packets, addr)
+	}
+}
+
+// mapError returns the netdev error for timeouts, like the drivers do.
+func mapError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return netdev.ErrTimeout
+	}
+	return err
+}
@@ -0,0 +1,236 @@
+package loopback
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/netdev"
+	"tinygo.org/x/drivers/netlink"
+)
+
+// Compile time checks that Device implements the interfaces of the drivers.
+var (
+	_ netdev.Netdever   = (*Device)(nil)
+	_ netlink.Netlinker = (*Device)(nil)
+)
+
+func newDevice(t *testing.T, config Config) *Device {
+	t.Helper()
+	dev := New(config)
+	if err := dev.NetConnect(nil); err != nil {
+		t.Fatal(err)
+	}
+	return dev
+}
+
+func dial(t *testing.T, dev *Device, host string, port uint16) int {
+	t.Helper()
+	addr, err := dev.GetHostByName(host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TCP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Connect(fd, host, netip.AddrPortFrom(addr, port)); err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func recv(t *testing.T, dev *Device, fd int) string {
+	t.Helper()
+	buf := make([]byte, 64)
+	n, err := dev.Recv(fd, buf, 0, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+// testTCP connects to a server at the test side and exchanges some data.
+func testTCP(t *testing.T, dev *Device, addr netip.AddrPort) {
+	server, err := dev.ListenTCP(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	dev.SetHost("server.local", addr.Addr())
+
+	fd := dial(t, dev, "server.local", addr.Port())
+	conn, err := server.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := dev.Send(fd, []byte("ping"), 0, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Fatalf("server got %q, %v, want ping", buf, err)
+	}
+	conn.Write([]byte("pong"))
+	if got := recv(t, dev, fd); got != "pong" {
+		t.Errorf("device got %q, want pong", got)
+	}
+
+	// Nothing more to read.
+	_, err = dev.Recv(fd, buf, 0, time.Now().Add(10*time.Millisecond))
+	if err != netdev.ErrTimeout {
+		t.Errorf("got error %v, want %v", err, netdev.ErrTimeout)
+	}
+
+	conn.Close()
+	if _, err := dev.Recv(fd, buf, 0, time.Now().Add(time.Second)); err != io.EOF {
+		t.Errorf("got error %v after close, want EOF", err)
+	}
+	if err := dev.Close(fd); err != nil {
+		t.Fatal(err)
+	}
+	if dev.Sockets() != 0 {
+		t.Errorf("got %d open sockets, want 0", dev.Sockets())
+	}
+}
+
+func TestTCPClient(t *testing.T) {
+	testTCP(t, newDevice(t, Config{}), netip.MustParseAddrPort("10.0.0.1:1883"))
+}
+
+func TestTCPClientHost(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no host network:", err)
+	}
+	addr := netip.MustParseAddrPort(l.Addr().String())
+	l.Close()
+	testTCP(t, newDevice(t, Config{Host: true}), addr)
+}
+
+func TestTCPServer(t *testing.T) {
+	dev := newDevice(t, Config{})
+	fd, _ := dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TCP)
+	if err := dev.Bind(fd, netip.MustParseAddrPort("0.0.0.0:80")); err != nil {
+		t.Fatal(err)
+	}
+	if err := dev.Listen(fd, 2); err != nil {
+		t.Fatal(err)
+	}
+	addr, _ := dev.Addr()
+	conn, err := dev.DialTCP(netip.AddrPortFrom(addr, 80))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client, remote, err := dev.Accept(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remote.Addr() != netip.MustParseAddr("10.0.0.2") {
+		t.Errorf("got remote address %v, want 10.0.0.2", remote)
+	}
+	conn.Write([]byte("GET /"))
+	if got := recv(t, dev, client); got != "GET /" {
+		t.Errorf("got %q, want GET /", got)
+	}
+
+	if _, err := dev.DialTCP(netip.AddrPortFrom(addr, 81)); err == nil {
+		t.Error("connected to a port without listener")
+	}
+}
+
+func TestUDP(t *testing.T) {
+	dev := newDevice(t, Config{})
+	server, err := dev.ListenUDP(netip.MustParseAddrPort("10.0.0.1:123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	fd, _ := dev.Socket(netdev.AF_INET, netdev.SOCK_DGRAM, netdev.IPPROTO_UDP)
+	if err := dev.Connect(fd, "", netip.MustParseAddrPort("10.0.0.1:123")); err != nil {
+		t.Fatal(err)
+	}
+	dev.Send(fd, []byte("time?"), 0, time.Time{})
+	buf := make([]byte, 16)
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	n, from, err := server.ReadFrom(buf)
+	if err != nil || string(buf[:n]) != "time?" {
+		t.Fatalf("server got %q, %v, want time?", buf[:n], err)
+	}
+	server.WriteTo([]byte("noon"), from)
+	if got := recv(t, dev, fd); got != "noon" {
+		t.Errorf("device got %q, want noon", got)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	dev := New(Config{MaxSockets: 2})
+	if _, err := dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TCP); err != ErrNetDown {
+		t.Errorf("got error %v before NetConnect, want %v", err, ErrNetDown)
+	}
+	dev.SetConnectError(netlink.ErrAuthFailure)
+	if err := dev.NetConnect(nil); err != netlink.ErrAuthFailure {
+		t.Errorf("got error %v, want %v", err, netlink.ErrAuthFailure)
+	}
+	dev.SetConnectError(nil)
+	var events []netlink.Event
+	dev.NetNotify(func(e netlink.Event) { events = append(events, e) })
+	if err := dev.NetConnect(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// DNS.
+	dev.SetHostError("down.local", netdev.ErrHostUnknown)
+	if _, err := dev.GetHostByName("down.local"); err != netdev.ErrHostUnknown {
+		t.Errorf("got error %v, want %v", err, netdev.ErrHostUnknown)
+	}
+	if addr, err := dev.GetHostByName("192.168.1.5"); err != nil || addr != netip.MustParseAddr("192.168.1.5") {
+		t.Errorf("got %v, %v, want 192.168.1.5", addr, err)
+	}
+
+	// Connection failures and sockets running out.
+	addr := netip.MustParseAddrPort("10.0.0.1:80")
+	server, _ := dev.ListenTCP(addr)
+	defer server.Close()
+	errReset := errors.New("connection reset")
+	dev.FailConnect(addr, errReset)
+	fd, _ := dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TCP)
+	if err := dev.Connect(fd, "", addr); err != errReset {
+		t.Errorf("got error %v, want %v", err, errReset)
+	}
+	dev.FailConnect(addr, nil)
+	dev.SetLatency(20 * time.Millisecond)
+	start := time.Now()
+	if err := dev.Connect(fd, "", addr); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("connect was faster than the latency")
+	}
+	dev.SetLatency(0)
+	dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TCP)
+	if _, err := dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TCP); err != netdev.ErrNoMoreSockets {
+		t.Errorf("got error %v, want %v", err, netdev.ErrNoMoreSockets)
+	}
+
+	// Losing the network closes the connections.
+	conn, _ := server.Accept()
+	dev.SetLinkUp(false)
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("server got error %v, want EOF", err)
+	}
+	if _, err := dev.Send(fd, []byte("x"), 0, time.Time{}); err != ErrNetDown {
+		t.Errorf("got error %v, want %v", err, ErrNetDown)
+	}
+	dev.SetLinkUp(true)
+	if len(events) != 3 || events[0] != netlink.EventNetUp || events[1] != netlink.EventNetDown || events[2] != netlink.EventNetUp {
+		t.Errorf("got events %v, want up, down, up", events)
+	}
+}
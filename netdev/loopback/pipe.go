@@ -0,0 +1,284 @@
+package loopback
+
+import (
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+)
+
+// pipe is one direction of an in-memory stream, with an unlimited buffer so
+// that writes never block.
+type pipe struct {
+	mu     sync.Mutex
+	buf    []byte
+	closed bool
+	notify chan struct{} // closed when anything changes
+}
+
+func newPipe() *pipe {
+	return &pipe{notify: make(chan struct{})}
+}
+
+// changed wakes up waiting readers. The lock must be held.
+func (p *pipe) changed() {
+	close(p.notify)
+	p.notify = make(chan struct{})
+}
+
+func (p *pipe) close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		p.changed()
+	}
+	p.mu.Unlock()
+}
+
+// deadlines are the read and write deadlines of a connection.
+type deadlines struct {
+	mu    sync.Mutex
+	read  time.Time
+	write time.Time
+}
+
+func (d *deadlines) SetDeadline(t time.Time) error {
+	d.mu.Lock()
+	d.read, d.write = t, t
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *deadlines) SetReadDeadline(t time.Time) error {
+	d.mu.Lock()
+	d.read = t
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *deadlines) SetWriteDeadline(t time.Time) error {
+	d.mu.Lock()
+	d.write = t
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *deadlines) readDeadline() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.read
+}
+
+// wait waits until notify is closed, done is closed or the deadline passes.
+func wait(notify, done <-chan struct{}, deadline time.Time) error {
+	if deadline.IsZero() {
+		select {
+		case <-notify:
+			return nil
+		case <-done:
+			return net.ErrClosed
+		}
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return os.ErrDeadlineExceeded
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-notify:
+		return nil
+	case <-done:
+		return net.ErrClosed
+	case <-timer.C:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+// memConn is one end of an in-memory stream connection.
+type memConn struct {
+	deadlines
+	r, w          *pipe
+	local, remote netip.AddrPort
+	once          sync.Once
+	done          chan struct{}
+}
+
+// newConnPair returns the two ends of an in-memory stream connection.
+func newConnPair(a, b netip.AddrPort) (*memConn, *memConn) {
+	ab, ba := newPipe(), newPipe()
+	return &memConn{r: ba, w: ab, local: a, remote: b, done: make(chan struct{})},
+		&memConn{r: ab, w: ba, local: b, remote: a, done: make(chan struct{})}
+}
+
+func (c *memConn) Read(b []byte) (int, error) {
+	for {
+		select {
+		case <-c.done:
+			return 0, net.ErrClosed
+		default:
+		}
+		c.r.mu.Lock()
+		if len(c.r.buf) > 0 {
+			n := copy(b, c.r.buf)
+			c.r.buf = c.r.buf[n:]
+			c.r.mu.Unlock()
+			return n, nil
+		}
+		if c.r.closed {
+			c.r.mu.Unlock()
+			return 0, io.EOF
+		}
+		notify := c.r.notify
+		c.r.mu.Unlock()
+		err := wait(notify, c.done, c.readDeadline())
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (c *memConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.done:
+		return 0, net.ErrClosed
+	default:
+	}
+	c.w.mu.Lock()
+	defer c.w.mu.Unlock()
+	if c.w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	c.w.buf = append(c.w.buf, b...)
+	c.w.changed()
+	return len(b), nil
+}
+
+// Close closes the connection. The other end reads the remaining data and
+// then io.EOF.
+func (c *memConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+		c.w.close()
+		c.r.close()
+	})
+	return nil
+}
+
+func (c *memConn) LocalAddr() net.Addr  { return net.TCPAddrFromAddrPort(c.local) }
+func (c *memConn) RemoteAddr() net.Addr { return net.TCPAddrFromAddrPort(c.remote) }
+
+// memListener accepts in-memory stream connections.
+type memListener struct {
+	dev   *Device
+	addr  netip.AddrPort
+	conns chan net.Conn
+	once  sync.Once
+	done  chan struct{}
+}
+
+func (l *memListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+		l.dev.unregister(l.addr, l)
+	})
+	return nil
+}
+
+func (l *memListener) Addr() net.Addr { return net.TCPAddrFromAddrPort(l.addr) }
+
+// connect makes a connection to the listener from addr, and returns the end
+// of the caller.
+func (l *memListener) connect(addr netip.AddrPort) (net.Conn, error) {
+	client, server := newConnPair(addr, l.addr)
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		return nil, errRefused
+	default:
+		// The backlog is full.
+		return nil, errRefused
+	}
+}
+
+// packet is a UDP datagram.
+type packet struct {
+	from netip.AddrPort
+	data []byte
+}
+
+// memPacketConn is an in-memory UDP endpoint.
+type memPacketConn struct {
+	deadlines
+	dev     *Device
+	addr    netip.AddrPort
+	packets chan packet
+	once    sync.Once
+	done    chan struct{}
+}
+
+func (p *memPacketConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	deadline := p.readDeadline()
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return 0, nil, os.ErrDeadlineExceeded
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case pkt := <-p.packets:
+		n := copy(b, pkt.data)
+		return n, net.UDPAddrFromAddrPort(pkt.from), nil
+	case <-p.done:
+		return 0, nil, net.ErrClosed
+	case <-timeout:
+		return 0, nil, os.ErrDeadlineExceeded
+	}
+}
+
+// WriteTo sends a datagram. Like UDP, datagrams to nowhere and datagrams that
+// don't fit in the queue of the receiver are dropped.
+func (p *memPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	select {
+	case <-p.done:
+		return 0, net.ErrClosed
+	default:
+	}
+	to, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return 0, err
+	}
+	if dst := p.dev.lookupPacket(to); dst != nil {
+		select {
+		case dst.packets <- packet{from: p.addr, data: append([]byte(nil), b...)}:
+		default:
+		}
+	}
+	return len(b), nil
+}
+
+func (p *memPacketConn) Close() error {
+	p.once.Do(func() {
+		close(p.done)
+		p.dev.unregister(p.addr, p)
+	})
+	return nil
+}
+
+func (p *memPacketConn) LocalAddr() net.Addr { return net.UDPAddrFromAddrPort(p.addr) }
@@ -0,0 +1,291 @@
+// Package tlsdev adds TLS in Go to any netdev, for devices that can't do TLS
+// themselves, or when the TLS of the device isn't flexible enough.
+//
+// A Device wraps a Netdever. TLS sockets (IPPROTO_TLS) are passed on to the
+// wrapped device when it supports them, and otherwise created as TCP sockets
+// of the wrapped device with a crypto/tls client on top. The TLS client in Go
+// supports certificate pinning and client certificates, which TLS in the
+// network co-processor (NINA, ESP-AT) doesn't.
+//
+//	dev := tlsdev.New(rtl, tlsdev.Config{
+//		TLS: &tls.Config{RootCAs: roots},
+//	})
+//	netdev.UseNetdev(dev)
+//
+// All other sockets are passed on to the wrapped device unchanged.
+package tlsdev // import "tinygo.org/x/drivers/netdev/tlsdev"
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"tinygo.org/x/drivers/netdev"
+)
+
+// ErrPinMismatch is returned when no certificate of the server matches any of
+// the pins.
+var ErrPinMismatch = errors.New("tlsdev: no certificate matches a pin")
+
+// Config is the configuration of a Device.
+type Config struct {
+	// TLS is the configuration of the TLS client, with for example the root
+	// certificates (RootCAs) and client certificates (Certificates). The
+	// server name is set to the host name of every connection. Optional.
+	TLS *tls.Config
+
+	// Pins are SHA-256 hashes of the public keys (SubjectPublicKeyInfo) of
+	// trusted certificates, see Pin. When there are pins, a connection is
+	// only made when a certificate sent by the server matches a pin. The
+	// certificate chain is verified as well, unless TLS.InsecureSkipVerify is
+	// set, which makes the pins the only check.
+	Pins [][sha256.Size]byte
+
+	// Software makes the device always use TLS in Go, even when the wrapped
+	// device supports TLS. This is implied by pins, client certificates and
+	// root certificates, which only TLS in Go supports.
+	Software bool
+
+	// HandshakeTimeout limits the time of the TLS handshake, 30s by default.
+	HandshakeTimeout time.Duration
+}
+
+// Device is a Netdever that adds TLS in Go to a wrapped Netdever.
+type Device struct {
+	netdev.Netdever
+	config Config
+
+	mu    sync.Mutex
+	conns map[int]*conn // sockets with TLS in Go
+}
+
+// conn is a socket with TLS in Go.
+type conn struct {
+	sock sockConn
+	tls  *tls.Conn
+}
+
+// New returns a device that adds TLS in Go to dev.
+func New(dev netdev.Netdever, config Config) *Device {
+	if config.HandshakeTimeout <= 0 {
+		config.HandshakeTimeout = 30 * time.Second
+	}
+	return &Device{
+		Netdever: dev,
+		config:   config,
+		conns:    make(map[int]*conn),
+	}
+}
+
+// Pin returns the pin of a certificate, for Config.Pins.
+func Pin(cert *x509.Certificate) [sha256.Size]byte {
+	return sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+}
+
+// software returns whether TLS sockets must use TLS in Go.
+func (d *Device) software() bool {
+	c := d.config
+	return c.Software || len(c.Pins) > 0 ||
+		(c.TLS != nil && (len(c.TLS.Certificates) > 0 || c.TLS.GetClientCertificate != nil || c.TLS.RootCAs != nil))
+}
+
+// Socket creates a socket. TLS sockets are created on the wrapped device if it
+// supports them, and otherwise as TCP sockets with TLS in Go.
+func (d *Device) Socket(domain int, stype int, protocol int) (int, error) {
+	if protocol != netdev.IPPROTO_TLS {
+		return d.Netdever.Socket(domain, stype, protocol)
+	}
+	if !d.software() {
+		fd, err := d.Netdever.Socket(domain, stype, protocol)
+		if err != netdev.ErrProtocolNotSupported {
+			return fd, err
+		}
+	}
+	fd, err := d.Netdever.Socket(domain, stype, netdev.IPPROTO_TCP)
+	if err != nil {
+		return fd, err
+	}
+	d.mu.Lock()
+	d.conns[fd] = &conn{sock: sockConn{dev: d.Netdever, fd: fd}}
+	d.mu.Unlock()
+	return fd, nil
+}
+
+// Connect connects a socket. For sockets with TLS in Go, it connects over TCP
+// and then does the TLS handshake.
+func (d *Device) Connect(sockfd int, host string, ip netip.AddrPort) error {
+	c := d.conn(sockfd)
+	if c == nil {
+		return d.Netdever.Connect(sockfd, host, ip)
+	}
+	err := d.Netdever.Connect(sockfd, host, ip)
+	if err != nil {
+		return err
+	}
+	c.sock.remote = ip
+
+	var config *tls.Config
+	if d.config.TLS != nil {
+		config = d.config.TLS.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		config.ServerName = host
+		if host == "" {
+			config.ServerName = ip.Addr().String()
+		}
+	}
+	if len(d.config.Pins) > 0 {
+		verify := config.VerifyConnection
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if !d.pinned(state.PeerCertificates) {
+				return ErrPinMismatch
+			}
+			if verify != nil {
+				return verify(state)
+			}
+			return nil
+		}
+	}
+
+	c.tls = tls.Client(&c.sock, config)
+	c.sock.SetDeadline(time.Now().Add(d.config.HandshakeTimeout))
+	err = c.tls.Handshake()
+	c.sock.SetDeadline(time.Time{})
+	return err
+}
+
+// pinned returns whether any of the certificates matches a pin.
+func (d *Device) pinned(certs []*x509.Certificate) bool {
+	for _, cert := range certs {
+		pin := Pin(cert)
+		for _, p := range d.config.Pins {
+			if pin == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Send sends data on a socket, encrypted for sockets with TLS in Go.
+func (d *Device) Send(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	c := d.conn(sockfd)
+	if c == nil {
+		return d.Netdever.Send(sockfd, buf, flags, deadline)
+	}
+	if c.tls == nil {
+		return -1, net.ErrClosed
+	}
+	c.tls.SetWriteDeadline(deadline)
+	return c.tls.Write(buf)
+}
+
+// Recv receives data from a socket, decrypted for sockets with TLS in Go.
+func (d *Device) Recv(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	c := d.conn(sockfd)
+	if c == nil {
+		return d.Netdever.Recv(sockfd, buf, flags, deadline)
+	}
+	if c.tls == nil {
+		return -1, net.ErrClosed
+	}
+	c.tls.SetReadDeadline(deadline)
+	return c.tls.Read(buf)
+}
+
+// Close closes a socket. For sockets with TLS in Go, the server is notified
+// first.
+func (d *Device) Close(sockfd int) error {
+	d.mu.Lock()
+	c := d.conns[sockfd]
+	delete(d.conns, sockfd)
+	d.mu.Unlock()
+	if c == nil || c.tls == nil {
+		return d.Netdever.Close(sockfd)
+	}
+	// Don't wait long for the close notification to be sent.
+	c.tls.SetWriteDeadline(time.Now().Add(time.Second))
+	return c.tls.Close()
+}
+
+func (d *Device) conn(sockfd int) *conn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conns[sockfd]
+}
+
+// sockConn is a net.Conn on a socket of the wrapped device, for the TLS
+// client.
+type sockConn struct {
+	dev    netdev.Netdever
+	fd     int
+	remote netip.AddrPort
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func (s *sockConn) Read(b []byte) (int, error) {
+	s.mu.Lock()
+	deadline := s.readDeadline
+	s.mu.Unlock()
+	n, err := s.dev.Recv(s.fd, b, 0, deadline)
+	return max(n, 0), err
+}
+
+func (s *sockConn) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	deadline := s.writeDeadline
+	s.mu.Unlock()
+	written := 0
+	for written < len(b) {
+		n, err := s.dev.Send(s.fd, b[written:], 0, deadline)
+		written += max(n, 0)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (s *sockConn) Close() error {
+	return s.dev.Close(s.fd)
+}
+
+func (s *sockConn) LocalAddr() net.Addr {
+	addr, _ := s.dev.Addr()
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, 0))
+}
+
+func (s *sockConn) RemoteAddr() net.Addr {
+	return net.TCPAddrFromAddrPort(s.remote)
+}
+
+func (s *sockConn) SetDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.readDeadline, s.writeDeadline = t, t
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *sockConn) SetReadDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.readDeadline = t
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *sockConn) SetWriteDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.writeDeadline = t
+	s.mu.Unlock()
+	return nil
+}
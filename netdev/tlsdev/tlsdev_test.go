@@ -0,0 +1,229 @@
+package tlsdev
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/netdev"
+)
+
+// hostDev is a netdev on top of the host network, optionally with TLS.
+type hostDev struct {
+	tls bool
+
+	mu      sync.Mutex
+	sockets map[int]net.Conn
+	tlsFds  map[int]bool
+	next    int
+}
+
+func newHostDev(tls bool) *hostDev {
+	return &hostDev{tls: tls, sockets: make(map[int]net.Conn), tlsFds: make(map[int]bool)}
+}
+
+func (d *hostDev) GetHostByName(name string) (netip.Addr, error) { return netip.ParseAddr(name) }
+func (d *hostDev) Addr() (netip.Addr, error)                     { return netip.MustParseAddr("127.0.0.1"), nil }
+func (d *hostDev) Bind(sockfd int, ip netip.AddrPort) error      { return netdev.ErrNotSupported }
+func (d *hostDev) Listen(sockfd int, backlog int) error          { return netdev.ErrNotSupported }
+func (d *hostDev) Accept(sockfd int) (int, netip.AddrPort, error) {
+	return -1, netip.AddrPort{}, netdev.ErrNotSupported
+}
+func (d *hostDev) SetSockOpt(sockfd int, level int, opt int, value interface{}) error {
+	return nil
+}
+
+func (d *hostDev) Socket(domain int, stype int, protocol int) (int, error) {
+	if protocol == netdev.IPPROTO_TLS && !d.tls {
+		return -1, netdev.ErrProtocolNotSupported
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.next++
+	d.tlsFds[d.next] = protocol == netdev.IPPROTO_TLS
+	return d.next, nil
+}
+
+func (d *hostDev) Connect(sockfd int, host string, ip netip.AddrPort) error {
+	var c net.Conn
+	var err error
+	if d.tlsFds[sockfd] {
+		c, err = tls.Dial("tcp", ip.String(), &tls.Config{InsecureSkipVerify: true})
+	} else {
+		c, err = net.Dial("tcp", ip.String())
+	}
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.sockets[sockfd] = c
+	d.mu.Unlock()
+	return nil
+}
+
+func (d *hostDev) socket(sockfd int) net.Conn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sockets[sockfd]
+}
+
+func (d *hostDev) Send(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	c := d.socket(sockfd)
+	c.SetWriteDeadline(deadline)
+	return c.Write(buf)
+}
+
+func (d *hostDev) Recv(sockfd int, buf []byte, flags int, deadline time.Time) (int, error) {
+	c := d.socket(sockfd)
+	c.SetReadDeadline(deadline)
+	n, err := c.Read(buf)
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return -1, netdev.ErrTimeout
+	}
+	return n, err
+}
+
+func (d *hostDev) Close(sockfd int) error {
+	d.mu.Lock()
+	c := d.sockets[sockfd]
+	delete(d.sockets, sockfd)
+	d.mu.Unlock()
+	if c == nil {
+		return nil
+	}
+	return c.Close()
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			io.WriteString(w, "hello "+r.TLS.PeerCertificates[0].Subject.CommonName)
+			return
+		}
+		io.WriteString(w, "hello")
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// get does an HTTP request over a TLS socket of dev, and returns the body.
+func get(dev netdev.Netdever, server *httptest.Server) (string, error) {
+	fd, err := dev.Socket(netdev.AF_INET, netdev.SOCK_STREAM, netdev.IPPROTO_TLS)
+	if err != nil {
+		return "", err
+	}
+	defer dev.Close(fd)
+	addr := netip.MustParseAddrPort(server.Listener.Addr().String())
+	err = dev.Connect(fd, "example.com", addr)
+	if err != nil {
+		return "", err
+	}
+	request := "GET / HTTP/1.0\r\nHost: example.com\r\n\r\n"
+	_, err = dev.Send(fd, []byte(request), 0, time.Now().Add(5*time.Second))
+	if err != nil {
+		return "", err
+	}
+	var response []byte
+	buf := make([]byte, 256)
+	for {
+		n, err := dev.Recv(fd, buf, 0, time.Now().Add(5*time.Second))
+		response = append(response, buf[:max(n, 0)]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	_, body, _ := strings.Cut(string(response), "\r\n\r\n")
+	return body, nil
+}
+
+func TestRootCA(t *testing.T) {
+	server := newServer(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	dev := New(newHostDev(false), Config{TLS: &tls.Config{RootCAs: roots}})
+	body, err := get(dev, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "hello" {
+		t.Errorf("got body %q, want hello", body)
+	}
+
+	// Without the root certificate, the server isn't trusted.
+	dev = New(newHostDev(false), Config{Software: true})
+	if _, err := get(dev, server); err == nil {
+		t.Error("connected to an untrusted server")
+	}
+}
+
+func TestPins(t *testing.T) {
+	server := newServer(t)
+	dev := New(newHostDev(true), Config{
+		TLS:  &tls.Config{InsecureSkipVerify: true},
+		Pins: [][sha256.Size]byte{Pin(server.Certificate())},
+	})
+	body, err := get(dev, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "hello" {
+		t.Errorf("got body %q, want hello", body)
+	}
+
+	dev = New(newHostDev(true), Config{
+		TLS:  &tls.Config{InsecureSkipVerify: true},
+		Pins: [][sha256.Size]byte{{1, 2, 3}},
+	})
+	if _, err := get(dev, server); !errors.Is(err, ErrPinMismatch) {
+		t.Errorf("got error %v, want %v", err, ErrPinMismatch)
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	server := newServer(t)
+	// The test server certificate is valid for example.com, and works as a
+	// client certificate as well.
+	cert := server.TLS.Certificates[0]
+	dev := New(newHostDev(false), Config{
+		TLS: &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{cert}},
+	})
+	body, err := get(dev, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hello " + server.Certificate().Subject.CommonName; body != want {
+		t.Errorf("got body %q, want %q", body, want)
+	}
+}
+
+func TestOffload(t *testing.T) {
+	server := newServer(t)
+	host := newHostDev(true)
+	dev := New(host, Config{})
+	body, err := get(dev, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "hello" {
+		t.Errorf("got body %q, want hello", body)
+	}
+	if len(dev.conns) != 0 || !host.tlsFds[1] {
+		t.Error("TLS wasn't done by the wrapped device")
+	}
+}
@@ -22,11 +22,14 @@ type Device struct {
 	width      int16
 	height     int16
 	bufferSize int16
+	rotation   drivers.Rotation
 }
 
 type Config struct {
 	Width  int16
 	Height int16
+	// Rotation of the display, done in software.
+	Rotation drivers.Rotation
 }
 
 // New creates a new PCD8544 connection. The SPI bus must already be configured.
@@ -51,6 +54,7 @@ func (d *Device) Configure(cfg Config) {
 	} else {
 		d.height = 48
 	}
+	d.rotation = cfg.Rotation % 8
 	d.bufferSize = d.width * d.height / 8
 	d.buffer = make([]byte, d.bufferSize)
 
@@ -65,6 +69,17 @@ func (d *Device) Configure(cfg Config) {
 	d.SendCommand(DISPLAYCONTROL | DISPLAYNORMAL)
 }
 
+// Sleep puts the controller in power-down mode, or wakes it up again. The
+// display memory is kept while powered down.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.SendCommand(FUNCTIONSET | POWERDOWN)
+	} else {
+		d.SendCommand(FUNCTIONSET)
+	}
+	return nil
+}
+
 // ClearBuffer clears the image buffer
 func (d *Device) ClearBuffer() {
 	d.buffer = make([]byte, d.bufferSize)
@@ -104,7 +119,8 @@ func (d *Device) sendDataCommand(isCommand bool, data uint8) {
 // color.RGBA{0, 0, 0, 255} is consider transparent, anything else
 // with enable a pixel on the screen
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	x, y, ok := d.position(x, y)
+	if !ok {
 		return
 	}
 	byteIndex := x + (y/8)*d.width
@@ -117,13 +133,25 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // GetPixel returns if the specified pixel is on (true) or off (false)
 func (d *Device) GetPixel(x int16, y int16) bool {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	x, y, ok := d.position(x, y)
+	if !ok {
 		return false
 	}
 	byteIndex := x + (y/8)*d.width
 	return (d.buffer[byteIndex] >> uint8(y%8) & 0x1) == 1
 }
 
+// position converts a position on the rotated display to a position in the
+// buffer.
+func (d *Device) position(x, y int16) (int16, int16, bool) {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
+		return 0, 0, false
+	}
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
+	return x, y, true
+}
+
 // SetBuffer changes the whole buffer at once
 func (d *Device) SetBuffer(buffer []byte) error {
 	if int16(len(buffer)) != d.bufferSize {
@@ -146,7 +174,20 @@ func (d *Device) SendData(data uint8) {
 	d.sendDataCommand(false, data)
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the currently configured rotation.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the device (clock-wise). SetPixel and
+// GetPixel use the new rotation right away, but the buffer isn't rotated: call
+// ClearBuffer and draw again to rotate what is on the screen.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
@@ -105,6 +105,26 @@ func (img Image[T]) LimitHeight(height int) Image[T] {
 	}
 }
 
+// Rows returns a subimage with only the rows from y0 up to (but not including)
+// y1. The subimage shares the underlying buffer with img. It will panic if the
+// first row doesn't start at a whole byte offset, which can happen for formats
+// like RGB444 and Monochrome with some image widths.
+func (img Image[T]) Rows(y0, y1 int) Image[T] {
+	if y0 < 0 || y1 < y0 || y1 > int(img.height) {
+		panic("Image.Rows: out of bounds")
+	}
+	var zeroColor T
+	bitOffset := y0 * int(img.width) * zeroColor.BitsPerPixel()
+	if bitOffset%8 != 0 {
+		panic("Image.Rows: row doesn't start at a byte boundary")
+	}
+	return Image[T]{
+		width:  img.width,
+		height: int16(y1 - y0),
+		data:   unsafe.Add(img.data, bitOffset/8),
+	}
+}
+
 // Len returns the number of pixels in this image buffer.
 func (img Image[T]) Len() int {
 	return int(img.width) * int(img.height)
@@ -148,6 +168,13 @@ func (img Image[T]) setPixel(index int, c T) {
 			*((*byte)(ptr)) &^= (1 << (7 - uint8(bits)))
 		}
 
+		return
+	case zeroColor.BitsPerPixel() == 2:
+		// Gray2, four pixels per byte.
+		offset := index / 4
+		shift := 6 - 2*uint8(index%4)
+		ptr := (*byte)(unsafe.Add(img.data, offset))
+		*ptr = *ptr&^(0x3<<shift) | (uint8(any(c).(Gray2))&0x3)<<shift
 		return
 	case zeroColor.BitsPerPixel()%8 == 0:
 		// Each color starts at a whole byte offset.
@@ -206,6 +233,12 @@ func (img Image[T]) Get(x, y int) T {
 		ptr := (*byte)(unsafe.Add(img.data, offset))
 		c = ((*ptr >> (7 - uint8(bits))) & 0x1) > 0
 		return any(c).(T)
+	case zeroColor.BitsPerPixel() == 2:
+		// Gray2, four pixels per byte.
+		offset := index / 4
+		shift := 6 - 2*uint8(index%4)
+		ptr := (*byte)(unsafe.Add(img.data, offset))
+		return any(Gray2((*ptr >> shift) & 0x3)).(T)
 	case zeroColor.BitsPerPixel()%8 == 0:
 		// Colors like RGB565, RGB888, etc.
 		offset := index * int(unsafe.Sizeof(zeroColor))
@@ -272,6 +305,20 @@ func (img Image[T]) FillSolidColor(color T) {
 		return
 	}
 
+	// Special case for Gray2: fill whole bytes at a time.
+	if c, ok := any(color).(Gray2); ok {
+		colorByte := uint8(c&0x3) * 0x55
+		numBytes := img.Len() / 4
+		rawBuf := unsafe.Slice((*byte)(img.data), numBytes)
+		for i := range rawBuf {
+			rawBuf[i] = colorByte
+		}
+		for i := numBytes * 4; i < img.Len(); i++ {
+			img.setPixel(i, color)
+		}
+		return
+	}
+
 	// Special case for RGB444.
 	if c, ok := any(color).(RGB444BE); ok {
 		// RGB444 can be stored in a more optimized way, by storing two colors
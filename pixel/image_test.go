@@ -97,6 +97,59 @@ func TestImageMonochrome(t *testing.T) {
 	}
 }
 
+func TestImageGray2(t *testing.T) {
+	image := pixel.NewImage[pixel.Gray2](5, 3)
+	if len(image.RawBuffer()) != 4 {
+		t.Errorf("expected a 4 byte buffer, got %d bytes", len(image.RawBuffer()))
+	}
+	for _, tc := range []struct {
+		c        color.RGBA
+		expected pixel.Gray2
+	}{
+		{color.RGBA{A: 0xff}, 0},
+		{color.RGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff}, 1},
+		{color.RGBA{R: 0xb0, G: 0xb0, B: 0xb0, A: 0xff}, 2},
+		{color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, 3},
+	} {
+		if c := pixel.NewColor[pixel.Gray2](tc.c.R, tc.c.G, tc.c.B); c != tc.expected {
+			t.Errorf("NewColor(%v): expected %d, got %d", tc.c, tc.expected, c)
+		}
+	}
+
+	image.FillSolidColor(2)
+	image.Set(1, 0, 1)
+	image.Set(4, 2, 3)
+	if raw := image.RawBuffer(); raw[0] != 0b10_01_10_10 || raw[3] != 0b10_10_11_00 {
+		t.Errorf("unexpected buffer contents: %08b", raw)
+	}
+	if c := image.Get(1, 0); c != 1 {
+		t.Errorf("expected 1 at 1, 0, got %d", c)
+	}
+	if c := image.Get(3, 2); c != 2 {
+		t.Errorf("expected 2 at 3, 2, got %d", c)
+	}
+}
+
+func TestImageRows(t *testing.T) {
+	image := pixel.NewImage[pixel.RGB565BE](4, 6)
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 4; x++ {
+			image.Set(x, y, pixel.NewRGB565BE(uint8(y*8), 0, 0))
+		}
+	}
+	rows := image.Rows(2, 5)
+	if width, height := rows.Size(); width != 4 || height != 3 {
+		t.Errorf("rows.Size(): expected 4, 3 but got %d, %d", width, height)
+	}
+	if c := rows.Get(3, 0); c != image.Get(3, 2) {
+		t.Errorf("expected first row to be row 2, got color %d", c)
+	}
+	rows.Set(0, 2, pixel.NewRGB565BE(0, 0, 0xff))
+	if c := image.Get(0, 4); c != pixel.NewRGB565BE(0, 0, 0xff) {
+		t.Errorf("expected rows to share the underlying buffer, got color %d", c)
+	}
+}
+
 // 128x128
 var rprofile = []byte{
 	0x00, 0x00, 0x11, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x44, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00,
@@ -194,6 +247,9 @@ func TestImageNoise(t *testing.T) {
 	t.Run("RGB444BE", func(t *testing.T) {
 		testImageNoiseN[pixel.RGB444BE](t)
 	})
+	t.Run("Gray2", func(t *testing.T) {
+		testImageNoiseN[pixel.Gray2](t)
+	})
 	t.Run("Monochrome", func(t *testing.T) {
 		testImageNoiseN[pixel.Monochrome](t)
 	})
@@ -16,7 +16,7 @@ import (
 // particular display. Each pixel is at least 1 byte in size.
 // The color format is sRGB (or close to it) in all cases except for 1-bit.
 type Color interface {
-	RGB888 | RGB565BE | RGB555 | RGB444BE | Monochrome
+	RGB888 | RGB565BE | RGB555 | RGB444BE | Gray2 | Monochrome
 
 	BaseColor
 }
@@ -50,6 +50,8 @@ func NewColor[T Color](r, g, b uint8) T {
 		return any(NewRGB555(r, g, b)).(T)
 	case RGB444BE:
 		return any(NewRGB444BE(r, g, b)).(T)
+	case Gray2:
+		return any(NewGray2(r, g, b)).(T)
 	case Monochrome:
 		return any(NewMonochrome(r, g, b)).(T)
 	default:
@@ -204,6 +206,33 @@ func (c RGB444BE) RGBA() color.RGBA {
 	return color
 }
 
+// Gray2 is a 2-bit grayscale color with 4 levels, from 0 (black) to 3 (white).
+// It is used by e-paper displays in their grayscale mode. In an Image[Gray2],
+// four pixels are stored in a byte with the first pixel in the most significant
+// bits.
+type Gray2 uint8
+
+func NewGray2(r, g, b uint8) Gray2 {
+	// Approximate the luminance using the BT.601 weights, and round it to the
+	// nearest of the 4 levels.
+	y := (uint16(r)*77 + uint16(g)*150 + uint16(b)*29) >> 8
+	return Gray2((y + 42) / 85)
+}
+
+func (c Gray2) BitsPerPixel() int {
+	return 2
+}
+
+func (c Gray2) RGBA() color.RGBA {
+	value := uint8(c&3) * 85
+	return color.RGBA{
+		R: value,
+		G: value,
+		B: value,
+		A: 255,
+	}
+}
+
 type Monochrome bool
 
 func NewMonochrome(r, g, b uint8) Monochrome {
@@ -0,0 +1,30 @@
+package seesaw
+
+// ConfigureInputs configures the GPIO pins set in the pins bitmask as inputs,
+// with or without a pull-up resistor.
+func (d *Device) ConfigureInputs(pins uint32, pullup bool) error {
+	buf := [4]byte{byte(pins >> 24), byte(pins >> 16), byte(pins >> 8), byte(pins)}
+	err := d.Write(ModuleGpioBase, FunctionGpioDirclrBulk, buf[:])
+	if err != nil {
+		return err
+	}
+	if !pullup {
+		return d.Write(ModuleGpioBase, FunctionGpioPullenclr, buf[:])
+	}
+	err = d.Write(ModuleGpioBase, FunctionGpioPullenset, buf[:])
+	if err != nil {
+		return err
+	}
+	// The output value of an input selects a pull-up rather than a pull-down.
+	return d.Write(ModuleGpioBase, FunctionGpioBulkSet, buf[:])
+}
+
+// ReadGPIO reads the state of all GPIO pins, as a bitmask.
+func (d *Device) ReadGPIO() (uint32, error) {
+	var buf [4]byte
+	err := d.Read(ModuleGpioBase, FunctionGpioBulk, buf[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3]), nil
+}
@@ -78,3 +78,33 @@ func TestDevice_Write(t *testing.T) {
 	qt := quicktest.New(t)
 	qt.Assert(err, quicktest.IsNil)
 }
+
+func TestDevice_ConfigureInputs(t *testing.T) {
+	pins := []byte{0x00, 0x01, 0x00, 0x0C}
+	mocked := newMockDev(t, 0x49,
+		when(append([]byte{0x01, 0x03}, pins...), nil, nil),
+		when(append([]byte{0x01, 0x0B}, pins...), nil, nil),
+		when(append([]byte{0x01, 0x05}, pins...), nil, nil),
+	)
+
+	sut := New(mocked)
+
+	err := sut.ConfigureInputs(0x0001000C, true)
+	qt := quicktest.New(t)
+	qt.Assert(err, quicktest.IsNil)
+}
+
+func TestDevice_ReadGPIO(t *testing.T) {
+	mocked := newMockDev(t, 0x49,
+		when([]byte{0x01, 0x04}, nil, nil),
+		when(nil, []byte{0x00, 0x01, 0x00, 0x08}, nil),
+	)
+
+	sut := New(mocked)
+	sut.ReadDelay = 0
+
+	pins, err := sut.ReadGPIO()
+	qt := quicktest.New(t)
+	qt.Assert(err, quicktest.IsNil)
+	qt.Assert(pins, quicktest.Equals, uint32(0x00010008))
+}
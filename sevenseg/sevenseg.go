@@ -0,0 +1,92 @@
+// Package sevenseg contains a font for 7-segment displays, as used by the
+// tm1637 and the max72xx (without digit decoding).
+//
+// Segments are encoded with one bit per segment, from segment A in the least
+// significant bit to segment G, with the decimal point (or colon) in the most
+// significant bit:
+//
+//	 -A-
+//	F   B
+//	 -G-
+//	E   C
+//	 -D-  DP
+package sevenseg // import "tinygo.org/x/drivers/sevenseg"
+
+// Segment bits.
+const (
+	A byte = 1 << iota
+	B
+	C
+	D
+	E
+	F
+	G
+	DP
+)
+
+// Digits contains the segments of the digits 0-9.
+var Digits = [10]byte{0x3F, 0x06, 0x5B, 0x4F, 0x66, 0x6D, 0x7D, 0x07, 0x7F, 0x6F}
+
+// Segments of the letters A-Z. Lowercase letters use the same segments.
+var letters = [26]byte{
+	0x77, 0x7C, 0x39, 0x5E, 0x79, 0x71, 0x3D, 0x76, 0x06, 0x1E,
+	0x76, 0x38, 0x55, 0x54, 0x3F, 0x73, 0x67, 0x50, 0x6D, 0x78,
+	0x3E, 0x1C, 0x2A, 0x76, 0x6E, 0x5B,
+}
+
+// Encode returns the segments of an ASCII character. Characters that can't be
+// shown return 0 (blank). The '*' character is shown as a degree sign.
+func Encode(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return Digits[c-'0']
+	case c >= 'A' && c <= 'Z':
+		return letters[c-'A']
+	case c >= 'a' && c <= 'z':
+		return letters[c-'a']
+	}
+	switch c {
+	case '-':
+		return G
+	case '_':
+		return D
+	case '=':
+		return D | G
+	case '*':
+		return A | B | F | G // degree
+	case '.', ',':
+		return DP
+	case '\'':
+		return B
+	case '"':
+		return B | F
+	case '[', '(':
+		return A | D | E | F
+	case ']', ')':
+		return A | B | C | D
+	case '/':
+		return B | E | G
+	case '\\':
+		return C | F | G
+	case '|':
+		return E | F
+	case '^':
+		return A | B | F
+	case '?':
+		return A | B | E | G
+	}
+	return 0
+}
+
+// ToDPABCDEFG converts segments to the order used by the MAX7219 and MAX7221
+// when digit decoding is disabled: the decimal point in the most significant
+// bit, followed by segments A to G.
+func ToDPABCDEFG(segments byte) byte {
+	var r byte
+	for i := 0; i < 7; i++ {
+		if segments&(1<<i) != 0 {
+			r |= 0x40 >> i
+		}
+	}
+	return r | segments&DP
+}
@@ -0,0 +1,45 @@
+package sevenseg_test
+
+import (
+	"testing"
+
+	"tinygo.org/x/drivers/sevenseg"
+)
+
+func TestEncode(t *testing.T) {
+	for _, tc := range []struct {
+		c        byte
+		expected byte
+	}{
+		{'0', 0x3F},
+		{'8', 0x7F},
+		{'A', 0x77},
+		{'a', 0x77},
+		{'Z', 0x5B},
+		{'-', sevenseg.G},
+		{'.', sevenseg.DP},
+		{' ', 0},
+		{'~', 0},
+	} {
+		if s := sevenseg.Encode(tc.c); s != tc.expected {
+			t.Errorf("Encode(%q): expected %#02x, got %#02x", tc.c, tc.expected, s)
+		}
+	}
+}
+
+func TestToDPABCDEFG(t *testing.T) {
+	for _, tc := range []struct {
+		segments byte
+		expected byte
+	}{
+		{sevenseg.A, 0x40},
+		{sevenseg.G, 0x01},
+		{sevenseg.DP, 0x80},
+		{sevenseg.Digits[1], 0x30},
+		{sevenseg.Digits[8] | sevenseg.DP, 0xFF},
+	} {
+		if s := sevenseg.ToDPABCDEFG(tc.segments); s != tc.expected {
+			t.Errorf("ToDPABCDEFG(%#02x): expected %#02x, got %#02x", tc.segments, tc.expected, s)
+		}
+	}
+}
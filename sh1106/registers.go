@@ -20,6 +20,8 @@ const (
 	SETLOWCOLUMN                         = 0x00
 	SETHIGHCOLUMN                        = 0x10
 	SETSTARTLINE                         = 0x40
+	SETPAGEADDR                          = 0xB0
+	SETDCDC                              = 0xAD
 	MEMORYMODE                           = 0x20
 	COLUMNADDR                           = 0x21
 	PAGEADDR                             = 0x22
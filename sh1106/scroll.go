@@ -0,0 +1,54 @@
+package sh1106
+
+import "errors"
+
+var errScrollPages = errors.New("invalid scroll page range")
+
+// ScrollDirection is the horizontal direction of a scroll.
+type ScrollDirection uint8
+
+const (
+	ScrollRight ScrollDirection = iota
+	ScrollLeft
+)
+
+// Scroll shifts the pages (rows of 8 pixels) from startPage to endPage, both
+// inclusive, by the given number of columns in the buffer. Columns that are
+// shifted out on one side come back in on the other side.
+//
+// Unlike the SSD1306, the SH1106 has no horizontal scroll engine, so for a
+// continuous scroll call Scroll and Display repeatedly. The direction and pages
+// are those of the display without rotation. Vertical scrolling is done by the
+// controller, see SetScroll.
+func (d *Device) Scroll(direction ScrollDirection, startPage, endPage uint8, columns int16) error {
+	if startPage > endPage || int16(endPage) >= d.height/8 {
+		return errScrollPages
+	}
+	columns %= d.width
+	if columns < 0 {
+		columns += d.width
+	}
+	if direction == ScrollRight {
+		columns = (d.width - columns) % d.width
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	// Rotate every page left in place, by reversing both parts and then the
+	// whole page.
+	for pg := int16(startPage); pg <= int16(endPage); pg++ {
+		page := d.buffer[pg*d.width : (pg+1)*d.width]
+		reverse(page[:columns])
+		reverse(page[columns:])
+		reverse(page)
+	}
+	return nil
+}
+
+// reverse reverses the order of the bytes.
+func reverse(buf []byte) {
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+}
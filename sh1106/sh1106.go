@@ -22,6 +22,9 @@ type Device struct {
 	height     int16
 	bufferSize int16
 	vccState   VccMode
+	rotation   drivers.Rotation
+	contrast   uint8
+	colOffset  int16
 }
 
 // Config is the configuration for the display
@@ -29,7 +32,18 @@ type Config struct {
 	Width    int16
 	Height   int16
 	VccState VccMode
-	Address  uint16
+	// I2C address of the display, usually 0x3C or 0x3D depending on how the
+	// SA0 pin is connected. Every Device has its own buffer, so two displays
+	// can share a bus when they use different addresses.
+	Address uint16
+	// Rotation of the display, done in software.
+	Rotation drivers.Rotation
+	// The controller has 132 columns of display memory, of which the display
+	// usually shows the middle ones. ColumnOffset is the first column that is
+	// shown. The default centers the display, which is 2 for the common 128
+	// pixel wide displays. As 0 selects the default, use -1 for displays that
+	// start at column 0.
+	ColumnOffset int16
 }
 
 type I2CBus struct {
@@ -97,6 +111,15 @@ func (d *Device) Configure(cfg Config) {
 	} else {
 		d.vccState = SWITCHCAPVCC
 	}
+	d.rotation = cfg.Rotation % 8
+	switch {
+	case cfg.ColumnOffset < 0:
+		d.colOffset = 0
+	case cfg.ColumnOffset > 0:
+		d.colOffset = cfg.ColumnOffset
+	default:
+		d.colOffset = (132 - d.width) / 2
+	}
 	d.bufferSize = d.width * d.height / 8
 	d.buffer = make([]byte, d.bufferSize)
 
@@ -118,6 +141,12 @@ func (d *Device) Configure(cfg Config) {
 	} else {
 		d.Command(0x14)
 	}
+	d.Command(SETDCDC)
+	if d.vccState == EXTERNALVCC {
+		d.Command(0x8A) // DC-DC converter off
+	} else {
+		d.Command(0x8B) // DC-DC converter on
+	}
 	d.Command(MEMORYMODE)
 	d.Command(0x00)
 	d.Command(SEGREMAP | 0x1)
@@ -126,25 +155,22 @@ func (d *Device) Configure(cfg Config) {
 	if (d.width == 128 && d.height == 64) || (d.width == 64 && d.height == 48) { // 128x64 or 64x48
 		d.Command(SETCOMPINS)
 		d.Command(0x12)
-		d.Command(SETCONTRAST)
 		if d.vccState == EXTERNALVCC {
-			d.Command(0x9F)
+			d.SetContrast(0x9F)
 		} else {
-			d.Command(0xCF)
+			d.SetContrast(0xCF)
 		}
 	} else if d.width == 128 && d.height == 32 { // 128x32
 		d.Command(SETCOMPINS)
 		d.Command(0x02)
-		d.Command(SETCONTRAST)
-		d.Command(0x8F)
+		d.SetContrast(0x8F)
 	} else if d.width == 96 && d.height == 16 { // 96x16
 		d.Command(SETCOMPINS)
 		d.Command(0x2)
-		d.Command(SETCONTRAST)
 		if d.vccState == EXTERNALVCC {
-			d.Command(0x10)
+			d.SetContrast(0x10)
 		} else {
-			d.Command(0xAF)
+			d.SetContrast(0xAF)
 		}
 	} else {
 		// fail silently, it might work
@@ -180,22 +206,14 @@ func (d *Device) ClearDisplay() {
 
 // Display sends the whole buffer to the screen
 func (d *Device) Display() error {
-	// In the 128x64 (SPI) screen resetting to 0x0 after 128 times corrupt the buffer
-	// Since we're printing the whole buffer, avoid resetting it
-	if d.width != 128 || d.height != 64 {
-		d.Command(COLUMNADDR)
-		d.Command(0)
-		d.Command(uint8(d.width - 1))
-		d.Command(PAGEADDR)
-		d.Command(0)
-		d.Command(uint8(d.height/8) - 1)
-	}
-
-	for pg := uint8(0); pg < uint8(d.height/8); pg++ {
-		d.Command(0xB0 | (pg & 0x07)) // SET_PAGE_ADDR
-		d.Command(SETLOWCOLUMN | 2)
-		d.Command(SETHIGHCOLUMN | 0)
-		d.Tx(d.buffer[uint16(pg)*0x80:uint16(pg+1)*0x80], false)
+	// The SH1106 only supports page addressing, so the column address is set
+	// for every page.
+	col := uint8(d.colOffset)
+	for pg := int16(0); pg < d.height/8; pg++ {
+		d.Command(SETPAGEADDR | uint8(pg&0x07))
+		d.Command(SETLOWCOLUMN | col&0x0F)
+		d.Command(SETHIGHCOLUMN | col>>4)
+		d.Tx(d.buffer[pg*d.width:(pg+1)*d.width], false)
 	}
 
 	return nil
@@ -205,7 +223,8 @@ func (d *Device) Display() error {
 // color.RGBA{0, 0, 0, 255} is consider transparent, anything else
 // with enable a pixel on the screen
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	x, y, ok := d.position(x, y)
+	if !ok {
 		return
 	}
 	byteIndex := x + (y/8)*d.width
@@ -218,13 +237,25 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // GetPixel returns if the specified pixel is on (true) or off (false)
 func (d *Device) GetPixel(x int16, y int16) bool {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	x, y, ok := d.position(x, y)
+	if !ok {
 		return false
 	}
 	byteIndex := x + (y/8)*d.width
 	return (d.buffer[byteIndex] >> uint8(y%8) & 0x1) == 1
 }
 
+// position converts a position on the rotated display to a position in the
+// buffer.
+func (d *Device) position(x, y int16) (int16, int16, bool) {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
+		return 0, 0, false
+	}
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
+	return x, y, true
+}
+
 // SetBuffer changes the whole buffer at once
 func (d *Device) SetBuffer(buffer []byte) error {
 	if int16(len(buffer)) != d.bufferSize {
@@ -237,10 +268,69 @@ func (d *Device) SetBuffer(buffer []byte) error {
 	return nil
 }
 
+// SetScroll sets the first line of the display memory that is shown at the top
+// of the display, which scrolls the display vertically.
 func (d *Device) SetScroll(line int16) {
 	d.Command(SETSTARTLINE + uint8(line&0b111111))
 }
 
+// SetContrast sets the contrast of the display, from 0 to 255. On an OLED
+// display this changes the brightness.
+func (d *Device) SetContrast(contrast uint8) {
+	d.contrast = contrast
+	d.Command(SETCONTRAST)
+	d.Command(contrast)
+}
+
+// Contrast returns the current contrast of the display.
+func (d *Device) Contrast() uint8 {
+	return d.contrast
+}
+
+// FadeContrast changes the contrast gradually from the current value to the
+// given value over the given duration. It blocks until the fade is done.
+func (d *Device) FadeContrast(contrast uint8, duration time.Duration) {
+	from := int(d.contrast)
+	diff := int(contrast) - from
+	steps := max(diff, -diff)
+	if steps == 0 {
+		return
+	}
+
+	// Don't flood the bus with commands for fast fades.
+	const minInterval = 5 * time.Millisecond
+	steps = max(1, min(steps, int(duration/minInterval)))
+	interval := duration / time.Duration(steps)
+	for i := 1; i <= steps; i++ {
+		d.SetContrast(uint8(from + diff*i/steps))
+		if i < steps {
+			time.Sleep(interval)
+		}
+	}
+}
+
+// InvertColors inverts the colors of the display in hardware, without changing
+// the buffer.
+func (d *Device) InvertColors(invert bool) {
+	if invert {
+		d.Command(INVERTDISPLAY)
+	} else {
+		d.Command(NORMALDISPLAY)
+	}
+}
+
+// Set the sleep mode for this display. When sleeping, the panel uses a lot
+// less power. The display won't show an image anymore, but the memory contents
+// will be kept.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.Command(DISPLAYOFF)
+	} else {
+		d.Command(DISPLAYON)
+	}
+	return nil
+}
+
 // Command sends a command to the display
 func (d *Device) Command(command uint8) {
 	d.cmdbuf[0] = command
@@ -311,9 +401,22 @@ func (b *SPIBus) tx(data []byte, isCommand bool) {
 	}
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the currently configured rotation.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the device (clock-wise). The segment and
+// COM remapping set by Configure doesn't change; SetPixel places pixels in the
+// pages of the buffer according to the rotation instead.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 // TODO: is this really necessary? seems to work fine without this on macropad-rp2040 at least
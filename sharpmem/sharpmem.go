@@ -55,6 +55,7 @@ type Device struct {
 	bytesPerLine int16
 	vcom         uint8
 	diffing      bool
+	rotation     drivers.Rotation
 }
 
 type Config struct {
@@ -64,6 +65,9 @@ type Config struct {
 	// DisableOptimizations disables frame and line invalidation optimizations.
 	// Useful if constant frame times are desired.
 	DisableOptimizations bool
+
+	// Rotation of the display, done in software.
+	Rotation drivers.Rotation
 }
 
 // New creates a new device connection.
@@ -89,6 +93,7 @@ func (d *Device) Configure(cfg Config) {
 	d.width = cfg.Width
 	d.height = cfg.Height
 	d.diffing = !cfg.DisableOptimizations
+	d.rotation = cfg.Rotation % 8
 
 	d.initialize()
 }
@@ -134,9 +139,11 @@ func (d *Device) SetPixel(x, y int16, c color.RGBA) {
 	}
 
 	// bounds check
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
 		return
 	}
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
 
 	offset := y * d.bytesPerLine
 
@@ -161,9 +168,22 @@ func (d *Device) SetPixel(x, y int16, c color.RGBA) {
 	}
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (x, y int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the currently configured rotation.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the display (clock-wise). Only lines that
+// are drawn again are marked as changed, so redraw the whole buffer when diffing
+// is enabled.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 // Display renders the buffer to the screen. It only transmits changed lines if
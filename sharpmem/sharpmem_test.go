@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers/tester"
 )
 
 func Test_setBit(t *testing.T) {
@@ -136,6 +137,18 @@ func Test_Device(t *testing.T) {
 	}
 }
 
+func Test_Rotation(t *testing.T) {
+	c := qt.New(t)
+
+	display := New(&mockBus{}, mockPin{})
+	display.Configure(ConfigLS011B7DH03)
+
+	tester.TestRotation(c, &display, func(x, y int16) bool {
+		// A cleared bit is a black (set) pixel.
+		return !hasBit(display.buffer[y*display.bytesPerLine+x/8], uint8(x%8))
+	})
+}
+
 func Test_HiPad(t *testing.T) {
 	c := qt.New(t)
 
@@ -9,6 +9,11 @@ tinygo build -size short -o ./build/test.hex -target=feather-rp2040 ./examples/a
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/adt7410/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/adxl345/main.go
 tinygo build -size short -o ./build/test.hex -target=pybadge ./examples/amg88xx
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/band/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/console/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/input/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/keymatrix/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/menu/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/apa102/main.go
 tinygo build -size short -o ./build/test.hex -target=nano-33-ble ./examples/apds9960/proximity/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/apa102/itsybitsy-m0/main.go
@@ -27,12 +32,14 @@ tinygo build -size short -o ./build/test.hex -target=microbit ./examples/easyste
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/flash/console/spi
 tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/flash/console/qspi
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/gc9a01/main.go
+tinygo build -size short -o ./build/test.hex -target=microbit ./examples/font/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/gps/i2c/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/gps/uart/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/hcsr04/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/hd44780/customchar/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/hd44780/text/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/hd44780i2c/main.go
+tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/textdisplay/main.go
 tinygo build -size short -o ./build/test.hex -target=nano-33-ble ./examples/hts221/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/hub75/main.go
 tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/ili9341/basic
@@ -70,27 +77,35 @@ tinygo build -size short -o ./build/test.hex -target=microbit ./examples/shtc3/m
 tinygo build -size short -o ./build/test.hex -target=xiao-ble ./examples/ssd1306/
 tinygo build -size short -o ./build/test.hex -target=xiao-rp2040 ./examples/ssd1306/
 tinygo build -size short -o ./build/test.hex -target=thumby ./examples/ssd1306/
+tinygo build -size short -o ./build/test.hex -target=xiao-ble ./examples/ssd1306/scroll
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/ssd1331/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/st7735/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/st7789/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/vsync/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-express ./examples/thermistor/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-bluefruit ./examples/tone
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/tm1637/main.go
 tinygo build -size short -o ./build/test.hex -target=pico ./examples/touch/capacitive
 tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/touch/resistive/fourwire/main.go
 tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/touch/resistive/pyportal_touchpaint/main.go
+tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/touch/calibration/main.go
+tinygo build -size short -o ./build/test.hex -target=pyportal ./examples/widget/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/vl53l1x/main.go
 tinygo build -size short -o ./build/test.hex -target=itsybitsy-m0 ./examples/vl6180x/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-nrf52840-sense ./examples/waveshare-epd/epd1in54/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/waveshare-epd/epd2in13/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/waveshare-epd/epd2in13x/main.go
 tinygo build -size short -o ./build/test.hex -target=microbit ./examples/waveshare-epd/epd4in2/main.go
+tinygo build -size short -o ./build/test.hex -target=microbit ./examples/epaper/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-nrf52840 ./examples/idle/main.go
 tinygo build -size short -o ./build/test.hex -target=pico ./examples/waveshare-epd/epd2in66b/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-express ./examples/ws2812
 tinygo build -size short -o ./build/test.bin -target=m5stamp-c3          ./examples/ws2812
 tinygo build -size short -o ./build/test.hex -target=feather-nrf52840 ./examples/is31fl3731/main.go
+tinygo build -size short -o ./build/test.hex -target=feather-nrf52840 ./examples/is31fl3731-animation/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino   ./examples/ws2812
 tinygo build -size short -o ./build/test.hex -target=digispark ./examples/ws2812
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/ledstrip/main.go
 tinygo build -size short -o ./build/test.hex -target=trinket-m0 ./examples/bme280/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-express ./examples/microphone/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-express ./examples/buzzer/main.go
@@ -103,6 +118,7 @@ tinygo build -size short -o ./build/test.hex -target=nucleo-f103rb ./examples/sh
 tinygo build -size short -o ./build/test.hex -target=hifive1b ./examples/ssd1351/main.go
 tinygo build -size short -o ./build/test.hex -target=circuitplay-express ./examples/lis2mdl/main.go
 tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/max72xx/main.go
+tinygo build -size short -o ./build/test.hex -target=arduino-nano33 ./examples/max72xx-matrix/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-m0 ./examples/dht/main.go
 # tinygo build -size short -o ./build/test.hex -target=arduino ./examples/keypad4x4/main.go
 tinygo build -size short -o ./build/test.hex -target=feather-rp2040 ./examples/pcf8523/
@@ -121,9 +137,11 @@ tinygo build -size short -o ./build/test.elf -target=wioterminal ./examples/axp1
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/xpt2046/main.go
 tinygo build -size short -o ./build/test.elf -target=m5stack-core2 ./examples/ft6336/basic/
 tinygo build -size short -o ./build/test.elf -target=m5stack-core2 ./examples/ft6336/touchpaint/
+tinygo build -size short -o ./build/test.elf -target=m5stack-core2 ./examples/ft6336/gesture/
 tinygo build -size short -o ./build/test.hex -target=nucleo-wl55jc ./examples/sx126x/lora_rxtx/
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/ssd1289/main.go
 tinygo build -size short -o ./build/test.hex -target=pico ./examples/irremote/main.go
+tinygo build -size short -o ./build/test.hex -target=pico ./examples/irremote/learn/
 tinygo build -size short -o ./build/test.hex -target=badger2040 ./examples/uc8151/main.go
 tinygo build -size short -o ./build/test.uf2 -target=pico ./examples/scd4x/main.go
 tinygo build -size short -o ./build/test.uf2 -target=circuitplay-express ./examples/makeybutton/main.go
@@ -11,3 +11,18 @@ type SPI interface {
 	// If you want to transfer multiple bytes, it is more efficient to use Tx instead.
 	Transfer(b byte) (byte, error)
 }
+
+// SPIAsync is a SPI bus that can transmit data in the background, for example
+// using DMA. Drivers check for this interface at runtime and fall back to a
+// regular Tx call when the bus doesn't implement it.
+type SPIAsync interface {
+	SPI
+
+	// StartTx starts a transfer like Tx but returns before it has finished.
+	// The buffers must not be modified or reused until Wait has returned.
+	StartTx(w, r []byte) error
+
+	// Wait blocks until the transfer started with StartTx has completed and
+	// returns any error that occurred during the transfer.
+	Wait() error
+}
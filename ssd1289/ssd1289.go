@@ -7,6 +7,8 @@ import (
 	"image/color"
 	"machine"
 	"time"
+
+	"tinygo.org/x/drivers"
 )
 
 type Bus interface {
@@ -14,11 +16,12 @@ type Bus interface {
 }
 
 type Device struct {
-	rs  machine.Pin
-	wr  machine.Pin
-	cs  machine.Pin
-	rst machine.Pin
-	bus Bus
+	rs       machine.Pin
+	wr       machine.Pin
+	cs       machine.Pin
+	rst      machine.Pin
+	bus      Bus
+	rotation drivers.Rotation
 }
 
 const width = int16(240)
@@ -155,7 +158,8 @@ func (d *Device) ClearDisplay() {
 }
 
 func (d *Device) FillDisplay(c color.RGBA) {
-	d.FillRect(0, 0, width, height, c)
+	w, h := d.Size()
+	d.FillRect(0, 0, w, h, c)
 }
 
 func encodeColor(c color.RGBA) uint16 {
@@ -164,6 +168,11 @@ func encodeColor(c color.RGBA) uint16 {
 }
 
 func (d *Device) SetPixel(x, y int16, c color.RGBA) {
+	w, h := d.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return
+	}
+	x, y = d.rotation.Transform(x, y, width, height)
 
 	encoded := encodeColor(c)
 
@@ -175,6 +184,14 @@ func (d *Device) SetPixel(x, y int16, c color.RGBA) {
 }
 
 func (d *Device) FillRect(x, y, w, h int16, c color.RGBA) {
+	if d.rotation != drivers.Rotation0 {
+		// The order of the pixels doesn't matter, so only the corners of
+		// the rectangle need to be rotated.
+		x0, y0 := d.rotation.Transform(x, y, width, height)
+		x1, y1 := d.rotation.Transform(x+w-1, y+h-1, width, height)
+		x, y = min(x0, x1), min(y0, y1)
+		w, h = max(x0, x1)-x+1, max(y0, y1)-y+1
+	}
 	encoded := encodeColor(c)
 
 	d.cs.Low()
@@ -194,6 +211,33 @@ func (d *Device) Display() error {
 	return nil
 }
 
+// Sleep puts the controller in sleep mode, or wakes it up again. The display
+// memory is kept while sleeping.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.lcdWriteComData(DISPLAYCONTROL, 0x0000)
+		d.lcdWriteComData(SLEEPMODE, 0x0001)
+	} else {
+		d.lcdWriteComData(SLEEPMODE, 0x0000)
+		time.Sleep(time.Millisecond * 30)
+		d.lcdWriteComData(DISPLAYCONTROL, 0x033)
+	}
+	return nil
+}
+
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (x, y int16) {
-	return width, height
+	return d.rotation.Size(width, height)
+}
+
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the device (clock-wise). The rotation is
+// done in software.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
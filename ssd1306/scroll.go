@@ -0,0 +1,125 @@
+package ssd1306
+
+import "tinygo.org/x/drivers"
+
+// ScrollDirection is the horizontal direction of a hardware scroll.
+type ScrollDirection uint8
+
+const (
+	ScrollRight ScrollDirection = iota
+	ScrollLeft
+)
+
+// ScrollSpeed is the time between two steps of a hardware scroll, in frames.
+// The values are those of the controller, which are not in order.
+type ScrollSpeed uint8
+
+const (
+	Scroll5Frames   ScrollSpeed = 0x00
+	Scroll64Frames  ScrollSpeed = 0x01
+	Scroll128Frames ScrollSpeed = 0x02
+	Scroll256Frames ScrollSpeed = 0x03
+	Scroll3Frames   ScrollSpeed = 0x04
+	Scroll4Frames   ScrollSpeed = 0x05
+	Scroll25Frames  ScrollSpeed = 0x06
+	Scroll2Frames   ScrollSpeed = 0x07
+)
+
+// StartScroll starts a continuous horizontal scroll of the pages (rows of 8
+// pixels) from startPage to endPage, both inclusive. The controller scrolls
+// the display memory by itself, without any further communication. Call
+// StopScroll before changing the contents of the display.
+//
+// The direction and pages take the rotation into account for the rotations
+// done by the controller. Scrolling is not supported with the rotations by 90
+// and 270 degrees, which are done in software.
+func (d *Device) StartScroll(direction ScrollDirection, startPage, endPage uint8, speed ScrollSpeed) error {
+	command, startPage, endPage, err := d.scrollSetup(direction, startPage, endPage)
+	if err != nil {
+		return err
+	}
+	d.Command(command)
+	d.Command(0x00) // dummy byte
+	d.Command(startPage)
+	d.Command(uint8(speed) & 0x07)
+	d.Command(endPage)
+	d.Command(0x00) // dummy bytes
+	d.Command(0xFF)
+	d.Command(ACTIVATE_SCROLL)
+	return nil
+}
+
+// StartDiagonalScroll starts a continuous horizontal scroll of the pages from
+// startPage to endPage, like StartScroll, combined with a vertical scroll of
+// the whole display by verticalOffset rows per step upwards.
+func (d *Device) StartDiagonalScroll(direction ScrollDirection, startPage, endPage uint8, speed ScrollSpeed, verticalOffset int16) error {
+	if verticalOffset <= 0 || verticalOffset >= d.height {
+		return errScrollOffset
+	}
+	command, startPage, endPage, err := d.scrollSetup(direction, startPage, endPage)
+	if err != nil {
+		return err
+	}
+	if d.rotation == drivers.Rotation180 || d.rotation == drivers.Rotation180Mirror {
+		// The rows are scanned bottom to top, so scroll the other way
+		// around.
+		verticalOffset = d.height - verticalOffset
+	}
+	if command == RIGHT_HORIZONTAL_SCROLL {
+		command = VERTICAL_AND_RIGHT_HORIZONTAL_SCROLL
+	} else {
+		command = VERTICAL_AND_LEFT_HORIZONTAL_SCROLL
+	}
+
+	// Scroll all rows vertically.
+	d.Command(SET_VERTICAL_SCROLL_AREA)
+	d.Command(0)
+	d.Command(uint8(d.height))
+
+	d.Command(command)
+	d.Command(0x00) // dummy byte
+	d.Command(startPage)
+	d.Command(uint8(speed) & 0x07)
+	d.Command(endPage)
+	d.Command(uint8(verticalOffset))
+	d.Command(ACTIVATE_SCROLL)
+	return nil
+}
+
+// StopScroll stops a hardware scroll. The display memory is in an undefined
+// state afterwards, so the buffer is sent to the display again.
+func (d *Device) StopScroll() error {
+	d.Command(DEACTIVATE_SCROLL)
+	return d.Display()
+}
+
+// scrollSetup stops any running scroll, and returns the scroll command and the
+// pages for the controller taking rotation into account.
+func (d *Device) scrollSetup(direction ScrollDirection, startPage, endPage uint8) (uint8, uint8, uint8, error) {
+	if d.rotation%2 == 1 {
+		return 0, 0, 0, errScrollRotation
+	}
+	pages := uint8(d.height / 8)
+	if startPage > endPage || endPage >= pages {
+		return 0, 0, 0, errScrollPages
+	}
+
+	// The scroll must be stopped before changing its parameters.
+	d.Command(DEACTIVATE_SCROLL)
+
+	switch d.rotation {
+	case drivers.Rotation180, drivers.Rotation0Mirror:
+		// The columns are mapped right to left.
+		direction ^= 1
+	}
+	switch d.rotation {
+	case drivers.Rotation180, drivers.Rotation180Mirror:
+		// The pages are scanned bottom to top.
+		startPage, endPage = pages-1-endPage, pages-1-startPage
+	}
+
+	if direction == ScrollLeft {
+		return LEFT_HORIZONTAL_SCROLL, startPage, endPage, nil
+	}
+	return RIGHT_HORIZONTAL_SCROLL, startPage, endPage, nil
+}
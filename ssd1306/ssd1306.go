@@ -13,8 +13,11 @@ import (
 )
 
 var (
-	errBufferSize = errors.New("invalid size buffer")
-	errOutOfRange = errors.New("out of screen range")
+	errBufferSize     = errors.New("invalid size buffer")
+	errOutOfRange     = errors.New("out of screen range")
+	errScrollPages    = errors.New("invalid scroll page range")
+	errScrollOffset   = errors.New("invalid vertical scroll offset")
+	errScrollRotation = errors.New("scrolling is not supported when rotated by 90 or 270 degrees")
 )
 
 type ResetValue [2]byte
@@ -30,6 +33,7 @@ type Device struct {
 	resetCol  ResetValue
 	resetPage ResetValue
 	rotation  drivers.Rotation
+	contrast  uint8
 }
 
 // Config is the configuration for the display
@@ -37,7 +41,10 @@ type Config struct {
 	Width    int16
 	Height   int16
 	VccState VccMode
-	Address  uint16
+	// I2C address of the display, usually 0x3C or 0x3D depending on how the
+	// SA0 pin is connected. Every Device has its own buffer, so two displays
+	// can share a bus when they use different addresses.
+	Address uint16
 	// ResetCol and ResetPage are used to reset the screen to 0x0
 	// This is useful for some screens that have a different size than 128x64
 	// For example, the Thumby's screen is 72x40
@@ -112,25 +119,22 @@ func (d *Device) Configure(cfg Config) {
 	if (d.width == 128 && d.height == 64) || (d.width == 64 && d.height == 48) { // 128x64 or 64x48
 		d.Command(SETCOMPINS)
 		d.Command(0x12)
-		d.Command(SETCONTRAST)
 		if d.vccState == EXTERNALVCC {
-			d.Command(0x9F)
+			d.SetContrast(0x9F)
 		} else {
-			d.Command(0xCF)
+			d.SetContrast(0xCF)
 		}
 	} else if d.width == 128 && d.height == 32 { // 128x32
 		d.Command(SETCOMPINS)
 		d.Command(0x02)
-		d.Command(SETCONTRAST)
-		d.Command(0x8F)
+		d.SetContrast(0x8F)
 	} else if d.width == 96 && d.height == 16 { // 96x16
 		d.Command(SETCOMPINS)
 		d.Command(0x2)
-		d.Command(SETCONTRAST)
 		if d.vccState == EXTERNALVCC {
-			d.Command(0x10)
+			d.SetContrast(0x10)
 		} else {
-			d.Command(0xAF)
+			d.SetContrast(0xAF)
 		}
 	} else {
 		// fail silently, it might work
@@ -197,7 +201,8 @@ func (d *Device) Display() error {
 // color.RGBA{0, 0, 0, 255} is consider transparent, anything else
 // with enable a pixel on the screen
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	x, y, ok := d.position(x, y)
+	if !ok {
 		return
 	}
 	byteIndex := x + (y/8)*d.width
@@ -210,13 +215,28 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // GetPixel returns if the specified pixel is on (true) or off (false)
 func (d *Device) GetPixel(x int16, y int16) bool {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	x, y, ok := d.position(x, y)
+	if !ok {
 		return false
 	}
 	byteIndex := x + (y/8)*d.width
 	return (d.buffer[byteIndex] >> uint8(y%8) & 0x1) == 1
 }
 
+// position converts a position on the rotated display to a position in the
+// buffer. Rotations by 0 and 180 degrees are done by the controller, the others
+// in software.
+func (d *Device) position(x, y int16) (int16, int16, bool) {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
+		return 0, 0, false
+	}
+	if d.rotation%2 == 1 {
+		x, y = d.rotation.Transform(x, y, d.width, d.height)
+	}
+	return x, y, true
+}
+
 // SetBuffer changes the whole buffer at once
 func (d *Device) SetBuffer(buffer []byte) error {
 	if len(buffer) != len(d.buffer) {
@@ -231,15 +251,16 @@ func (d *Device) GetBuffer() []byte {
 	return d.buffer
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
 }
 
 // DrawBitmap copies the bitmap to the screen at the given coordinates.
 func (d *Device) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.Monochrome]) error {
 	width, height := bitmap.Size()
-	if x < 0 || x+int16(width) > d.width || y < 0 || y+int16(height) > d.height {
+	dw, dh := d.Size()
+	if x < 0 || x+int16(width) > dw || y < 0 || y+int16(height) > dh {
 		return errOutOfRange
 	}
 
@@ -257,18 +278,24 @@ func (d *Device) Rotation() drivers.Rotation {
 	return d.rotation
 }
 
-// SetRotation changes the rotation of the device (clock-wise).
+// SetRotation changes the rotation of the device (clock-wise). Rotations by 0
+// and 180 degrees, with or without mirroring, are done by the controller. The
+// other rotations are done by SetPixel, which doesn't move what is already in
+// the buffer.
 func (d *Device) SetRotation(rotation drivers.Rotation) error {
-	d.rotation = rotation
+	d.rotation = rotation % 8
 	switch d.rotation {
-	case drivers.Rotation0:
-		d.Command(SEGREMAP | 0x1) // Reverse horizontal mapping
-		d.Command(COMSCANDEC)     // Reverse vertical mapping
 	case drivers.Rotation180:
 		d.Command(SEGREMAP)   // Normal horizontal mapping
 		d.Command(COMSCANINC) // Normal vertical mapping
-	// nothing to do
+	case drivers.Rotation0Mirror:
+		d.Command(SEGREMAP)   // Normal horizontal mapping
+		d.Command(COMSCANDEC) // Reverse vertical mapping
+	case drivers.Rotation180Mirror:
+		d.Command(SEGREMAP | 0x1) // Reverse horizontal mapping
+		d.Command(COMSCANINC)     // Normal vertical mapping
 	default:
+		// Rotation0, and the base orientation for the software rotations.
 		d.Command(SEGREMAP | 0x1) // Reverse horizontal mapping
 		d.Command(COMSCANDEC)     // Reverse vertical mapping
 	}
@@ -287,12 +314,57 @@ func (d *Device) Sleep(sleepEnabled bool) error {
 	return nil
 }
 
+// SetContrast sets the contrast of the display, from 0 to 255. On an OLED
+// display this changes the brightness.
+func (d *Device) SetContrast(contrast uint8) {
+	d.contrast = contrast
+	d.Command(SETCONTRAST)
+	d.Command(contrast)
+}
+
+// Contrast returns the current contrast of the display.
+func (d *Device) Contrast() uint8 {
+	return d.contrast
+}
+
+// FadeContrast changes the contrast gradually from the current value to the
+// given value over the given duration. It blocks until the fade is done.
+func (d *Device) FadeContrast(contrast uint8, duration time.Duration) {
+	from := int(d.contrast)
+	diff := int(contrast) - from
+	steps := max(diff, -diff)
+	if steps == 0 {
+		return
+	}
+
+	// Don't flood the bus with commands for fast fades.
+	const minInterval = 5 * time.Millisecond
+	steps = max(1, min(steps, int(duration/minInterval)))
+	interval := duration / time.Duration(steps)
+	for i := 1; i <= steps; i++ {
+		d.SetContrast(uint8(from + diff*i/steps))
+		if i < steps {
+			time.Sleep(interval)
+		}
+	}
+}
+
+// InvertColors inverts the colors of the display in hardware, without changing
+// the buffer.
+func (d *Device) InvertColors(invert bool) {
+	if invert {
+		d.Command(INVERTDISPLAY)
+	} else {
+		d.Command(NORMALDISPLAY)
+	}
+}
+
 // FillRectangle fills a rectangle at a given coordinates with a color
 func (d *Device) FillRectangle(x, y, width, height int16, c color.RGBA) error {
 	dw, dh := d.Size()
 
 	if x < 0 || y < 0 || width <= 0 || height <= 0 ||
-		x >= d.width || (x+width) > dw || y >= dh || (y+height) > dh {
+		x >= dw || (x+width) > dw || y >= dh || (y+height) > dh {
 		return errOutOfRange
 	}
 
@@ -27,12 +27,14 @@ type Device struct {
 	batchLength int16
 	isBGR       bool
 	batchData   []uint8
+	rotation    drivers.Rotation
 }
 
 // Config is the configuration for the display
 type Config struct {
-	Width  int16
-	Height int16
+	Width    int16
+	Height   int16
+	Rotation drivers.Rotation
 }
 
 // New creates a new SSD1331 connection. The SPI wire must already be configured.
@@ -78,9 +80,7 @@ func (d *Device) Configure(cfg Config) {
 
 	// Initialization
 	d.Command(DISPLAYOFF)
-	d.Command(SETREMAP)
-	d.Command(0x72) // RGB
-	//d.Command(0x76) // BGR
+	d.SetRotation(cfg.Rotation)
 	d.Command(STARTLINE)
 	d.Command(0x0)
 	d.Command(DISPLAYOFFSET)
@@ -124,7 +124,8 @@ func (d *Device) Display() error {
 
 // SetPixel sets a pixel in the screen
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || y < 0 || x >= d.width || y >= d.height {
+	w, h := d.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
 		return
 	}
 	d.FillRectangle(x, y, 1, 1, c)
@@ -132,6 +133,11 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // setWindow prepares the screen to be modified at a given rectangle
 func (d *Device) setWindow(x, y, w, h int16) {
+	if d.rotation%2 == 1 {
+		// Rows and columns are swapped, and the controller writes in vertical
+		// address increment mode.
+		x, y, w, h = y, x, h, w
+	}
 	/*d.Tx([]uint8{SETCOLUMN}, true)
 	d.Tx([]uint8{uint8(x), uint8(x + w - 1)}, false)
 	d.Tx([]uint8{SETROW}, true)
@@ -146,8 +152,9 @@ func (d *Device) setWindow(x, y, w, h int16) {
 
 // FillRectangle fills a rectangle at a given coordinates with a color
 func (d *Device) FillRectangle(x, y, width, height int16, c color.RGBA) error {
+	w, h := d.Size()
 	if x < 0 || y < 0 || width <= 0 || height <= 0 ||
-		x >= d.width || (x+width) > d.width || y >= d.height || (y+height) > d.height {
+		x >= w || (x+width) > w || y >= h || (y+height) > h {
 		return errors.New("rectangle coordinates outside display area")
 	}
 	d.setWindow(x, y, width, height)
@@ -174,8 +181,9 @@ func (d *Device) FillRectangle(x, y, width, height int16, c color.RGBA) error {
 
 // FillRectangle fills a rectangle at a given coordinates with a buffer
 func (d *Device) FillRectangleWithBuffer(x, y, width, height int16, buffer []color.RGBA) error {
+	w, h := d.Size()
 	if x < 0 || y < 0 || width <= 0 || height <= 0 ||
-		x >= d.width || (x+width) > d.width || y >= d.height || (y+height) > d.height {
+		x >= w || (x+width) > w || y >= h || (y+height) > h {
 		return errors.New("rectangle coordinates outside display area")
 	}
 	k := width * height
@@ -226,7 +234,8 @@ func (d *Device) DrawFastHLine(x0, x1, y int16, c color.RGBA) {
 
 // FillScreen fills the screen with a given color
 func (d *Device) FillScreen(c color.RGBA) {
-	d.FillRectangle(0, 0, d.width, d.height, c)
+	w, h := d.Size()
+	d.FillRectangle(0, 0, w, h, c)
 }
 
 // SetContrast sets the three contrast values (A, B & C)
@@ -239,6 +248,18 @@ func (d *Device) SetContrast(contrastA, contrastB, contrastC uint8) {
 	d.Command(contrastC)
 }
 
+// Set the sleep mode for this display. When sleeping, the panel uses a lot
+// less power. The display won't show an image anymore, but the memory contents
+// will be kept.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.Command(DISPLAYOFF)
+	} else {
+		d.Command(DISPLAYON)
+	}
+	return nil
+}
+
 // Command sends a command to the display
 func (d *Device) Command(command uint8) {
 	d.Tx([]byte{command}, true)
@@ -255,9 +276,43 @@ func (d *Device) Tx(data []byte, isCommand bool) {
 	d.bus.Tx(data, nil)
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the device (clock-wise), using the
+// remapping of the controller. Rotations by 90 and 270 degrees swap rows and
+// columns, and use the vertical address increment mode.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	var remap uint8
+	switch d.rotation % 4 {
+	case drivers.Rotation0:
+		remap = 0x72 // COM scan and column address remap
+	case drivers.Rotation90:
+		remap = 0x71 // COM scan remap, vertical increment
+	case drivers.Rotation180:
+		remap = 0x60
+	case drivers.Rotation270:
+		remap = 0x63 // column address remap, vertical increment
+	}
+	if d.rotation.IsMirrored() {
+		// Mirror the x axis of the rotated display.
+		if d.rotation%2 == 1 {
+			remap ^= 0x10
+		} else {
+			remap ^= 0x02
+		}
+	}
+	d.Command(SETREMAP)
+	d.Command(remap) // RGB, set 0x04 for BGR
+	return nil
 }
 
 // IsBGR changes the color mode (RGB/BGR)
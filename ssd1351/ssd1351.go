@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/pixel"
 )
 
 var (
@@ -30,6 +31,8 @@ type Device struct {
 	rowOffset    int16
 	columnOffset int16
 	bufferLength int16
+	rotation     drivers.Rotation
+	busy         bool // an asynchronous transfer is in progress
 }
 
 // Config is the configuration for the display
@@ -38,6 +41,7 @@ type Config struct {
 	Height       int16
 	RowOffset    int16
 	ColumnOffset int16
+	Rotation     drivers.Rotation
 }
 
 // New creates a new SSD1351 connection. The SPI wire must already be configured.
@@ -101,8 +105,7 @@ func (d *Device) Configure(cfg Config) {
 	d.Data(0xF1)
 	d.Command(SET_MUX_RATIO)
 	d.Data(0x7F)
-	d.Command(SET_REMAP_COLORDEPTH)
-	d.Data(0x62)
+	d.SetRotation(cfg.Rotation)
 	d.Command(SET_COLUMN_ADDRESS)
 	d.Data(0x00)
 	d.Data(0x7F)
@@ -147,7 +150,8 @@ func (d *Device) Display() error {
 
 // SetPixel sets a pixel in the buffer
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || y < 0 || x >= d.width || y >= d.height {
+	w, h := d.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
 		return
 	}
 	d.FillRectangle(x, y, 1, 1, c)
@@ -155,6 +159,11 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // setWindow prepares the screen memory to be modified at given coordinates
 func (d *Device) setWindow(x, y, w, h int16) {
+	if d.rotation%2 == 1 {
+		// Rows and columns are swapped, and the controller writes in vertical
+		// address increment mode.
+		x, y, w, h = y, x, h, w
+	}
 	x += d.columnOffset
 	y += d.rowOffset
 	d.Command(SET_COLUMN_ADDRESS)
@@ -166,8 +175,9 @@ func (d *Device) setWindow(x, y, w, h int16) {
 
 // FillRectangle fills a rectangle at given coordinates with a color
 func (d *Device) FillRectangle(x, y, width, height int16, c color.RGBA) error {
+	dw, dh := d.Size()
 	if x < 0 || y < 0 || width <= 0 || height <= 0 ||
-		x >= d.width || (x+width) > d.width || y >= d.height || (y+height) > d.height {
+		x >= dw || (x+width) > dw || y >= dh || (y+height) > dh {
 		return errDrawingOutOfBounds
 	}
 	d.setWindow(x, y, width, height)
@@ -199,8 +209,9 @@ func (d *Device) FillRectangle(x, y, width, height int16, c color.RGBA) error {
 
 // FillRectangleWithBuffer fills a rectangle at given coordinates with a buffer
 func (d *Device) FillRectangleWithBuffer(x, y, width, height int16, buffer []color.RGBA) error {
+	dw, dh := d.Size()
 	if x < 0 || y < 0 || width <= 0 || height <= 0 ||
-		x >= d.width || (x+width) > d.width || y >= d.height || (y+height) > d.height {
+		x >= dw || (x+width) > dw || y >= dh || (y+height) > dh {
 		return errDrawingOutOfBounds
 	}
 	dim := int16(width * height)
@@ -239,6 +250,67 @@ func (d *Device) FillRectangleWithBuffer(x, y, width, height int16, buffer []col
 	return nil
 }
 
+// DrawRGBBitmap8 copies an RGB bitmap to the internal buffer at given coordinates
+func (d *Device) DrawRGBBitmap8(x, y int16, data []uint8, w, h int16) error {
+	dw, dh := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= dw || (x+w) > dw || y >= dh || (y+h) > dh {
+		return errDrawingOutOfBounds
+	}
+	d.setWindow(x, y, w, h)
+	d.Tx(data, false)
+	return nil
+}
+
+// DrawBitmap copies the bitmap to the internal buffer on the screen at the
+// given coordinates. It returns once the image data has been sent completely.
+func (d *Device) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB565BE]) error {
+	width, height := bitmap.Size()
+	return d.DrawRGBBitmap8(x, y, bitmap.RawBuffer(), int16(width), int16(height))
+}
+
+// StartDrawBitmap starts sending the bitmap to the screen at the given
+// coordinates. If the SPI bus supports asynchronous transfers (see
+// drivers.SPIAsync) it returns before the transfer has finished, and the bitmap
+// must not be modified until Wait returns. Otherwise it behaves like
+// DrawBitmap.
+func (d *Device) StartDrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB565BE]) error {
+	width, height := bitmap.Size()
+	w, h := int16(width), int16(height)
+	dw, dh := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= dw || (x+w) > dw || y >= dh || (y+h) > dh {
+		return errDrawingOutOfBounds
+	}
+	d.setWindow(x, y, w, h)
+	bus, ok := d.bus.(drivers.SPIAsync)
+	if !ok {
+		d.Tx(bitmap.RawBuffer(), false)
+		return nil
+	}
+	// Keep the chip select pin low until the transfer has finished.
+	d.dcPin.High()
+	d.csPin.Low()
+	if err := bus.StartTx(bitmap.RawBuffer(), nil); err != nil {
+		d.csPin.High()
+		return err
+	}
+	d.busy = true
+	return nil
+}
+
+// Wait blocks until the transfer started by StartDrawBitmap has finished. It
+// returns immediately if no transfer is in progress.
+func (d *Device) Wait() error {
+	if !d.busy {
+		return nil
+	}
+	d.busy = false
+	err := d.bus.(drivers.SPIAsync).Wait()
+	d.csPin.High()
+	return err
+}
+
 // DrawFastVLine draws a vertical line faster than using SetPixel
 func (d *Device) DrawFastVLine(x, y0, y1 int16, c color.RGBA) {
 	if y0 > y1 {
@@ -257,7 +329,8 @@ func (d *Device) DrawFastHLine(x0, x1, y int16, c color.RGBA) {
 
 // FillScreen fills the screen with a given color
 func (d *Device) FillScreen(c color.RGBA) {
-	d.FillRectangle(0, 0, d.width, d.height, c)
+	w, h := d.Size()
+	d.FillRectangle(0, 0, w, h, c)
 }
 
 // SetContrast sets the three contrast values (A, B & C)
@@ -266,6 +339,18 @@ func (d *Device) SetContrast(contrastA, contrastB, contrastC uint8) {
 	d.Tx([]byte{contrastA, contrastB, contrastC}, false)
 }
 
+// Set the sleep mode for this display. When sleeping, the panel uses a lot
+// less power. The display won't show an image anymore, but the memory contents
+// will be kept.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.Command(SLEEP_MODE_DISPLAY_OFF)
+	} else {
+		d.Command(SLEEP_MODE_DISPLAY_ON)
+	}
+	return nil
+}
+
 // Command sends a command byte to the display
 func (d *Device) Command(command uint8) {
 	d.Tx([]byte{command}, true)
@@ -278,15 +363,50 @@ func (d *Device) Data(data uint8) {
 
 // Tx sends data to the display
 func (d *Device) Tx(data []byte, isCommand bool) {
+	d.Wait()
 	d.dcPin.Set(!isCommand)
 	d.csPin.Low()
 	d.bus.Tx(data, nil)
 	d.csPin.High()
 }
 
-// Size returns the current size of the display
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation of the device (clock-wise), using the
+// remapping of the controller. Rotations by 90 and 270 degrees swap rows and
+// columns, and use the vertical address increment mode.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	var remap uint8
+	switch d.rotation % 4 {
+	case drivers.Rotation0:
+		remap = 0x62 // column address remap
+	case drivers.Rotation90:
+		remap = 0x61 // vertical increment
+	case drivers.Rotation180:
+		remap = 0x70 // COM scan remap
+	case drivers.Rotation270:
+		remap = 0x73 // COM scan and column address remap, vertical increment
+	}
+	if d.rotation.IsMirrored() {
+		// Mirror the x axis of the rotated display.
+		if d.rotation%2 == 1 {
+			remap ^= 0x10
+		} else {
+			remap ^= 0x02
+		}
+	}
+	d.Command(SET_REMAP_COLORDEPTH)
+	d.Data(remap)
+	return nil
 }
 
 // RGBATo565 converts a color.RGBA to uint16 used in the display
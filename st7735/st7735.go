@@ -51,6 +51,7 @@ type DeviceOf[T Color] struct {
 	batchLength  int16
 	model        Model
 	isBGR        bool
+	busy         bool           // an asynchronous transfer is in progress
 	batchData    pixel.Image[T] // "image" with width, height of (batchLength, 1)
 }
 
@@ -245,7 +246,7 @@ func (d *DeviceOf[T]) SetPixel(x int16, y int16, c color.RGBA) {
 
 // setWindow prepares the screen to be modified at a given rectangle
 func (d *DeviceOf[T]) setWindow(x, y, w, h int16) {
-	if d.rotation == drivers.Rotation0 || d.rotation == drivers.Rotation180 {
+	if d.rotation%2 == 0 {
 		x += d.columnOffset
 		y += d.rowOffset
 	} else {
@@ -325,6 +326,42 @@ func (d *DeviceOf[T]) DrawBitmap(x, y int16, bitmap pixel.Image[T]) error {
 	return d.DrawRGBBitmap8(x, y, bitmap.RawBuffer(), int16(width), int16(height))
 }
 
+// StartDrawBitmap starts sending the bitmap to the screen at the given
+// coordinates. If the SPI bus supports asynchronous transfers (see
+// drivers.SPIAsync) it returns before the transfer has finished, and the bitmap
+// must not be modified until Wait returns. Otherwise it behaves like
+// DrawBitmap.
+func (d *DeviceOf[T]) StartDrawBitmap(x, y int16, bitmap pixel.Image[T]) error {
+	width, height := bitmap.Size()
+	w, h := int16(width), int16(height)
+	k, i := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= k || (x+w) > k || y >= i || (y+h) > i {
+		return errOutOfBounds
+	}
+	d.setWindow(x, y, w, h)
+	if bus, ok := d.bus.(drivers.SPIAsync); ok {
+		d.dcPin.High()
+		if err := bus.StartTx(bitmap.RawBuffer(), nil); err != nil {
+			return err
+		}
+		d.busy = true
+		return nil
+	}
+	d.Tx(bitmap.RawBuffer(), false)
+	return nil
+}
+
+// Wait blocks until the transfer started by StartDrawBitmap has finished. It
+// returns immediately if no transfer is in progress.
+func (d *DeviceOf[T]) Wait() error {
+	if !d.busy {
+		return nil
+	}
+	d.busy = false
+	return d.bus.(drivers.SPIAsync).Wait()
+}
+
 // FillRectangle fills a rectangle at a given coordinates with a buffer
 func (d *DeviceOf[T]) FillRectangleWithBuffer(x, y, width, height int16, buffer []color.RGBA) error {
 	k, l := d.Size()
@@ -377,7 +414,7 @@ func (d *DeviceOf[T]) DrawFastHLine(x0, x1, y int16, c color.RGBA) {
 
 // FillScreen fills the screen with a given color
 func (d *DeviceOf[T]) FillScreen(c color.RGBA) {
-	if d.rotation == drivers.Rotation0 || d.rotation == drivers.Rotation180 {
+	if d.rotation%2 == 0 {
 		d.FillRectangle(0, 0, d.width, d.height, c)
 	} else {
 		d.FillRectangle(0, 0, d.height, d.width, c)
@@ -391,6 +428,7 @@ func (d *DeviceOf[T]) Rotation() drivers.Rotation {
 
 // SetRotation changes the rotation of the device (clock-wise)
 func (d *DeviceOf[T]) SetRotation(rotation drivers.Rotation) error {
+	rotation %= 8
 	d.rotation = rotation
 	madctl := uint8(0)
 	switch rotation % 4 {
@@ -403,6 +441,14 @@ func (d *DeviceOf[T]) SetRotation(rotation drivers.Rotation) error {
 	case drivers.Rotation270:
 		madctl = MADCTL_MX | MADCTL_MV
 	}
+	if rotation.IsMirrored() {
+		// Mirror the x axis of the rotated display.
+		if rotation%2 == 1 {
+			madctl ^= MADCTL_MY
+		} else {
+			madctl ^= MADCTL_MX
+		}
+	}
 	if d.isBGR {
 		madctl |= MADCTL_BGR
 	}
@@ -423,13 +469,14 @@ func (d *DeviceOf[T]) Data(data uint8) {
 
 // Tx sends data to the display
 func (d *DeviceOf[T]) Tx(data []byte, isCommand bool) {
+	d.Wait()
 	d.dcPin.Set(!isCommand)
 	d.bus.Tx(data, nil)
 }
 
 // Size returns the current size of the display.
 func (d *DeviceOf[T]) Size() (w, h int16) {
-	if d.rotation == drivers.Rotation0 || d.rotation == drivers.Rotation180 {
+	if d.rotation%2 == 0 {
 		return d.width, d.height
 	}
 	return d.height, d.width
@@ -51,6 +51,9 @@ const (
 	PWCTR6     = 0xFC
 	GMCTRP1    = 0xE0
 	GMCTRN1    = 0xE1
+	TEOFF      = 0x34
+	TEON       = 0x35
+	STE        = 0x44
 	GSCAN      = 0x45
 	VSCRDEF    = 0x33
 	VSCRSADD   = 0x37
@@ -62,6 +62,7 @@ type DeviceOf[T Color] struct {
 	batchData       pixel.Image[T] // "image" with (width, height) of (batchLength, 1)
 	isBGR           bool
 	vSyncLines      int16
+	busy            bool // an asynchronous transfer is in progress
 	cmdBuf          [1]byte
 	buf             [6]byte
 }
@@ -227,8 +228,9 @@ func (d *DeviceOf[T]) sendCommand(command uint8, data []byte) error {
 }
 
 // startWrite must be called at the beginning of all exported methods to set the
-// chip select pin low.
+// chip select pin low. It waits for any asynchronous transfer to finish first.
 func (d *DeviceOf[T]) startWrite() {
+	d.Wait()
 	if d.csPin != machine.NoPin {
 		d.csPin.Low()
 	}
@@ -323,6 +325,31 @@ func (d *DeviceOf[T]) GetLowestScanLine() uint16 {
 	return uint16(math.Ceil(float64(d.vSyncLines)/2)/2) + 1
 }
 
+// EnableTEOutput enables the TE ("tearing effect") line.
+// The TE line goes high when the screen is not currently being updated and can
+// be used to start drawing. When used correctly, it can avoid tearing entirely.
+func (d *DeviceOf[T]) EnableTEOutput(on bool) {
+	d.startWrite()
+	if on {
+		d.buf[0] = 0
+		d.sendCommand(TEON, d.buf[:1]) // M=0 (V-blanking only, no H-blanking)
+	} else {
+		d.sendCommand(TEOFF, nil)
+	}
+	d.endWrite()
+}
+
+// SetTEScanLine sets the scanline at which the TE line goes high, instead of
+// at the start of the vertical blanking period. The TE output must also be
+// enabled using EnableTEOutput.
+func (d *DeviceOf[T]) SetTEScanLine(line uint16) {
+	d.buf[0] = uint8(line >> 8)
+	d.buf[1] = uint8(line)
+	d.startWrite()
+	d.sendCommand(STE, d.buf[:2])
+	d.endWrite()
+}
+
 // Display does nothing, there's no buffer as it might be too big for some boards
 func (d *DeviceOf[T]) Display() error {
 	return nil
@@ -330,9 +357,8 @@ func (d *DeviceOf[T]) Display() error {
 
 // SetPixel sets a pixel in the screen
 func (d *DeviceOf[T]) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || y < 0 ||
-		(((d.rotation == drivers.Rotation0 || d.rotation == drivers.Rotation180) && (x >= d.width || y >= d.height)) ||
-			((d.rotation == drivers.Rotation90 || d.rotation == drivers.Rotation270) && (x >= d.height || y >= d.width))) {
+	w, h := d.Size()
+	if x < 0 || y < 0 || x >= w || y >= h {
 		return
 	}
 	d.FillRectangle(x, y, 1, 1, c)
@@ -404,6 +430,47 @@ func (d *DeviceOf[T]) DrawBitmap(x, y int16, bitmap pixel.Image[T]) error {
 	return d.DrawRGBBitmap8(x, y, bitmap.RawBuffer(), int16(width), int16(height))
 }
 
+// StartDrawBitmap starts sending the bitmap to the screen at the given
+// coordinates. If the SPI bus supports asynchronous transfers (see
+// drivers.SPIAsync) it returns before the transfer has finished, and the bitmap
+// must not be modified until Wait returns. Otherwise it behaves like
+// DrawBitmap.
+func (d *DeviceOf[T]) StartDrawBitmap(x, y int16, bitmap pixel.Image[T]) error {
+	width, height := bitmap.Size()
+	w, h := int16(width), int16(height)
+	k, i := d.Size()
+	if x < 0 || y < 0 || w <= 0 || h <= 0 ||
+		x >= k || (x+w) > k || y >= i || (y+h) > i {
+		return errOutOfBounds
+	}
+	d.startWrite()
+	d.setWindow(x, y, w, h)
+	if bus, ok := d.bus.(drivers.SPIAsync); ok {
+		// Keep the chip select pin low until the transfer has finished.
+		if err := bus.StartTx(bitmap.RawBuffer(), nil); err != nil {
+			d.endWrite()
+			return err
+		}
+		d.busy = true
+		return nil
+	}
+	err := d.bus.Tx(bitmap.RawBuffer(), nil)
+	d.endWrite()
+	return err
+}
+
+// Wait blocks until the transfer started by StartDrawBitmap has finished. It
+// returns immediately if no transfer is in progress.
+func (d *DeviceOf[T]) Wait() error {
+	if !d.busy {
+		return nil
+	}
+	d.busy = false
+	err := d.bus.(drivers.SPIAsync).Wait()
+	d.endWrite()
+	return err
+}
+
 // FillRectangleWithBuffer fills buffer with a rectangle at a given coordinates.
 func (d *DeviceOf[T]) FillRectangleWithBuffer(x, y, width, height int16, buffer []color.RGBA) error {
 	i, j := d.Size()
@@ -465,7 +532,7 @@ func (d *DeviceOf[T]) FillScreen(c color.RGBA) {
 }
 
 func (d *DeviceOf[T]) fillScreen(c color.RGBA) {
-	if d.rotation == NO_ROTATION || d.rotation == ROTATION_180 {
+	if d.rotation%2 == 0 {
 		d.fillRectangle(0, 0, d.width, d.height, c)
 	} else {
 		d.fillRectangle(0, 0, d.height, d.width, c)
@@ -498,6 +565,7 @@ func (d *DeviceOf[T]) Rotation() drivers.Rotation {
 
 // SetRotation changes the rotation of the device (clock-wise)
 func (d *DeviceOf[T]) SetRotation(rotation Rotation) error {
+	rotation %= 8
 	d.rotation = rotation
 	d.startWrite()
 	err := d.setRotation(rotation)
@@ -524,6 +592,14 @@ func (d *DeviceOf[T]) setRotation(rotation Rotation) error {
 		d.rowOffset = d.columnOffsetCfg
 		d.columnOffset = d.rowOffsetCfg
 	}
+	if rotation.IsMirrored() {
+		// Mirror the x axis of the rotated display.
+		if rotation%2 == 1 {
+			madctl ^= MADCTL_MY
+		} else {
+			madctl ^= MADCTL_MX
+		}
+	}
 	if d.isBGR {
 		madctl |= MADCTL_BGR
 	}
@@ -532,7 +608,7 @@ func (d *DeviceOf[T]) setRotation(rotation Rotation) error {
 
 // Size returns the current size of the display.
 func (d *DeviceOf[T]) Size() (w, h int16) {
-	if d.rotation == drivers.Rotation0 || d.rotation == drivers.Rotation180 {
+	if d.rotation%2 == 0 {
 		return d.width, d.height
 	}
 	return d.height, d.width
@@ -596,7 +672,7 @@ func (d *DeviceOf[T]) SetScrollArea(topFixedArea, bottomFixedArea int16) {
 		topFixedArea += d.rowOffset
 		bottomFixedArea += (320 - d.height) - d.rowOffset
 	}
-	if d.rotation == drivers.Rotation180 {
+	if d.rotation%4 == drivers.Rotation180 {
 		// The screen is rotated by 180°, so we have to switch the top and
 		// bottom fixed area.
 		topFixedArea, bottomFixedArea = bottomFixedArea, topFixedArea
@@ -613,7 +689,7 @@ func (d *DeviceOf[T]) SetScrollArea(topFixedArea, bottomFixedArea int16) {
 
 // SetScroll sets the vertical scroll address of the display.
 func (d *DeviceOf[T]) SetScroll(line int16) {
-	if d.rotation == drivers.Rotation180 {
+	if d.rotation%4 == drivers.Rotation180 {
 		// The screen is rotated by 180°, so we have to invert the scroll line
 		// (taking care of the RowOffset).
 		line = (319 - d.rowOffset) - line
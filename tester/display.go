@@ -0,0 +1,98 @@
+package tester
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers"
+)
+
+// RotatableDisplay is a display that supports all rotations of
+// drivers.Rotation.
+type RotatableDisplay interface {
+	drivers.Displayer
+	Rotation() drivers.Rotation
+	SetRotation(rotation drivers.Rotation) error
+}
+
+// rotationPattern is the expected position of the rotated origin on the
+// display without rotation, and the directions of the rotated x and y axes.
+// Corners are given as 0 for the top/left and 1 for the bottom/right.
+var rotationPattern = [8]struct {
+	cornerX, cornerY int16
+	xdx, xdy         int16
+	ydx, ydy         int16
+}{
+	drivers.Rotation0:         {0, 0, 1, 0, 0, 1},
+	drivers.Rotation90:        {1, 0, 0, 1, -1, 0},
+	drivers.Rotation180:       {1, 1, -1, 0, 0, -1},
+	drivers.Rotation270:       {0, 1, 0, -1, 1, 0},
+	drivers.Rotation0Mirror:   {1, 0, -1, 0, 0, 1},
+	drivers.Rotation90Mirror:  {1, 1, 0, -1, -1, 0},
+	drivers.Rotation180Mirror: {0, 1, 1, 0, 0, -1},
+	drivers.Rotation270Mirror: {0, 0, 0, 1, 1, 0},
+}
+
+// TestRotation checks the rotation of a display using a test pattern: for every
+// rotation, it draws an L shape in the top left corner of the rotated display,
+// and checks where it ends up on the display without rotation. The getPixel
+// function must return whether a pixel of the display without rotation is set,
+// usually by reading the internal buffer of the driver.
+//
+// The display must be empty and unrotated, and at least 3 pixels wide and
+// high. It is left empty and unrotated.
+func TestRotation(c Failer, display RotatableDisplay, getPixel func(x, y int16) bool) {
+	width, height := display.Size()
+	pattern := [3][2]int16{{0, 0}, {1, 0}, {0, 2}}
+
+	for rotation := drivers.Rotation(drivers.Rotation0); rotation <= drivers.Rotation270Mirror; rotation++ {
+		err := display.SetRotation(rotation)
+		if err != nil {
+			c.Fatalf("rotation %d: SetRotation failed: %v", rotation, err)
+		}
+		if display.Rotation() != rotation {
+			c.Fatalf("rotation %d: Rotation returned %d", rotation, display.Rotation())
+		}
+		w, h := display.Size()
+		if rotation%2 == 1 {
+			w, h = h, w
+		}
+		if w != width || h != height {
+			c.Fatalf("rotation %d: unexpected size %dx%d", rotation, w, h)
+		}
+
+		for _, p := range pattern {
+			display.SetPixel(p[0], p[1], color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+		}
+
+		expected := rotationPattern[rotation]
+		x0 := expected.cornerX * (width - 1)
+		y0 := expected.cornerY * (height - 1)
+		set := 0
+		for y := int16(0); y < height; y++ {
+			for x := int16(0); x < width; x++ {
+				if getPixel(x, y) {
+					set++
+				}
+			}
+		}
+		for _, p := range pattern {
+			x := x0 + p[0]*expected.xdx + p[1]*expected.ydx
+			y := y0 + p[0]*expected.xdy + p[1]*expected.ydy
+			if !getPixel(x, y) {
+				c.Fatalf("rotation %d: pixel %d,%d should be at %d,%d", rotation, p[0], p[1], x, y)
+			}
+		}
+		if set != len(pattern) {
+			c.Fatalf("rotation %d: expected %d pixels to be set, got %d", rotation, len(pattern), set)
+		}
+
+		for _, p := range pattern {
+			display.SetPixel(p[0], p[1], color.RGBA{A: 0xff})
+		}
+	}
+
+	err := display.SetRotation(drivers.Rotation0)
+	if err != nil {
+		c.Fatalf("SetRotation failed: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package tester
+
+import (
+	"image/color"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"tinygo.org/x/drivers"
+)
+
+// fakeDisplay is a monochrome display that rotates using
+// drivers.Rotation.Transform.
+type fakeDisplay struct {
+	width, height int16
+	rotation      drivers.Rotation
+	pixels        []bool
+}
+
+func (d *fakeDisplay) Size() (int16, int16) {
+	return d.rotation.Size(d.width, d.height)
+}
+
+func (d *fakeDisplay) SetPixel(x, y int16, c color.RGBA) {
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
+	d.pixels[int(y)*int(d.width)+int(x)] = c.R != 0
+}
+
+func (d *fakeDisplay) Display() error {
+	return nil
+}
+
+func (d *fakeDisplay) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+func (d *fakeDisplay) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation
+	return nil
+}
+
+func TestRotationPattern(t *testing.T) {
+	c := qt.New(t)
+	d := &fakeDisplay{width: 7, height: 4, pixels: make([]bool, 7*4)}
+	TestRotation(c, d, func(x, y int16) bool {
+		return d.pixels[int(y)*int(d.width)+int(x)]
+	})
+}
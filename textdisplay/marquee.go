@@ -0,0 +1,59 @@
+package textdisplay
+
+// Marquee scrolls text that is too long for a row from right to left.
+type Marquee struct {
+	display *Display
+	row     int16
+	text    []byte
+	pos     int
+}
+
+// NewMarquee returns a new marquee that shows the text on the given row of the
+// display. Call Step regularly to scroll the text.
+func NewMarquee(display *Display, row int16, text string) *Marquee {
+	m := &Marquee{display: display, row: row}
+	m.SetText(text)
+	return m
+}
+
+// SetText changes the text, and starts scrolling from the beginning.
+func (m *Marquee) SetText(text string) {
+	m.text = append(m.text[:0], text...)
+	m.pos = 0
+}
+
+// Step draws the row, and moves the text one character to the left for the
+// next call. Text that fits on the row doesn't scroll. Otherwise the text
+// scrolls in from the right, and starts again once it has scrolled out
+// completely.
+func (m *Marquee) Step() {
+	columns, _ := m.display.Size()
+	width := int(columns)
+	if len(m.text) <= width {
+		m.draw(0, width)
+		return
+	}
+	m.draw(width-m.pos, width)
+	m.pos++
+	if m.pos > width+len(m.text) {
+		m.pos = 0
+	}
+}
+
+// draw shows the text starting at the given column, with the rest of the row
+// blank.
+func (m *Marquee) draw(column, width int) {
+	var buf [80]byte
+	row := buf[:0]
+	if width > len(buf) {
+		row = make([]byte, 0, width)
+	}
+	for i := 0; i < width; i++ {
+		c := byte(' ')
+		if j := i - column; j >= 0 && j < len(m.text) {
+			c = m.text[j]
+		}
+		row = append(row, c)
+	}
+	m.display.WriteAt(0, m.row, row)
+}
@@ -0,0 +1,190 @@
+// Package textdisplay implements a common interface for character displays,
+// such as HD44780 LCDs and 7-segment LED displays.
+//
+// Each driver implements the small Device interface. A Display wraps a Device
+// and adds a cursor, an io.Writer that handles newlines and scrolling, and
+// marquee text, so that the same UI code works on all character displays.
+//
+// The Device interface is implemented by hd44780.Device, hd44780i2c.Device,
+// tm1637.Device and max72xx.Digits.
+package textdisplay // import "tinygo.org/x/drivers/textdisplay"
+
+import "errors"
+
+var (
+	// ErrGlyphCode is returned when a custom glyph uses an unsupported code.
+	ErrGlyphCode = errors.New("textdisplay: unsupported glyph code")
+
+	// ErrGlyphSize is returned when the data of a custom glyph has the wrong
+	// size.
+	ErrGlyphSize = errors.New("textdisplay: wrong glyph size")
+)
+
+// Device is a display that shows a grid of characters.
+type Device interface {
+	// Size returns the size of the display in character cells.
+	Size() (columns, rows int16)
+
+	// ClearDisplay clears the whole display.
+	ClearDisplay()
+
+	// WriteAt shows the text at the given position. The text doesn't contain
+	// control characters and fits on the row.
+	WriteAt(column, row int16, text []byte)
+
+	// CreateGlyph defines a custom glyph, which is shown for the given
+	// character code. Character displays like the HD44780 support codes 0-7
+	// with 8 bytes of data (one per row, 5 bits per row). 7-segment displays
+	// support codes 0-7 with a single byte of data containing the segments,
+	// see package sevenseg.
+	CreateGlyph(code byte, data []byte) error
+}
+
+// Display shows text on a character display. It keeps a copy of all characters
+// on the display, so that it can scroll.
+type Display struct {
+	dev     Device
+	columns int16
+	rows    int16
+	cells   []byte
+	x, y    int16
+}
+
+// New returns a new Display for the device. The display is not cleared.
+func New(dev Device) *Display {
+	columns, rows := dev.Size()
+	d := &Display{
+		dev:     dev,
+		columns: columns,
+		rows:    rows,
+		cells:   make([]byte, int(columns)*int(rows)),
+	}
+	for i := range d.cells {
+		d.cells[i] = ' '
+	}
+	return d
+}
+
+// Size returns the size of the display in character cells.
+func (d *Display) Size() (columns, rows int16) {
+	return d.columns, d.rows
+}
+
+// Clear clears the display and moves the cursor to the top left.
+func (d *Display) Clear() {
+	for i := range d.cells {
+		d.cells[i] = ' '
+	}
+	d.x, d.y = 0, 0
+	d.dev.ClearDisplay()
+}
+
+// SetCursor moves the cursor to the given cell, where the next Write starts.
+func (d *Display) SetCursor(column, row int16) {
+	d.x = max(0, min(column, d.columns))
+	d.y = max(0, min(row, d.rows-1))
+}
+
+// Cursor returns the position of the cursor.
+func (d *Display) Cursor() (column, row int16) {
+	return d.x, d.y
+}
+
+// CreateGlyph defines a custom glyph, see Device.CreateGlyph.
+func (d *Display) CreateGlyph(code byte, data []byte) error {
+	return d.dev.CreateGlyph(code, data)
+}
+
+// WriteAt shows the text at the given position, without moving the cursor. Text
+// that doesn't fit on the row is cut off. Control characters aren't
+// interpreted.
+func (d *Display) WriteAt(column, row int16, text []byte) {
+	if row < 0 || row >= d.rows || column >= d.columns {
+		return
+	}
+	if column < 0 {
+		if int(-column) >= len(text) {
+			return
+		}
+		text = text[-column:]
+		column = 0
+	}
+	if len(text) > int(d.columns-column) {
+		text = text[:d.columns-column]
+	}
+	if len(text) == 0 {
+		return
+	}
+	copy(d.cells[int(row)*int(d.columns)+int(column):], text)
+	d.dev.WriteAt(column, row, text)
+}
+
+// Write writes the text at the cursor, and moves the cursor. Lines that are too
+// long wrap to the next row, '\n' moves the cursor to the start of the next row
+// and '\r' to the start of the current row. When the cursor moves past the last
+// row, all rows scroll up. On a display without cells, the text is discarded.
+func (d *Display) Write(p []byte) (n int, err error) {
+	if d.columns <= 0 || d.rows <= 0 {
+		return len(p), nil
+	}
+	for len(p) > 0 {
+		switch p[0] {
+		case '\n':
+			d.newline()
+			p = p[1:]
+			n++
+			continue
+		case '\r':
+			d.x = 0
+			p = p[1:]
+			n++
+			continue
+		}
+		if d.x >= d.columns {
+			d.newline()
+		}
+
+		// Write as much as possible at once.
+		end := 0
+		for end < len(p) && end < int(d.columns-d.x) && p[end] != '\n' && p[end] != '\r' {
+			end++
+		}
+		d.WriteAt(d.x, d.y, p[:end])
+		d.x += int16(end)
+		p = p[end:]
+		n += end
+	}
+	return n, nil
+}
+
+// newline moves the cursor to the start of the next row, scrolling if needed.
+func (d *Display) newline() {
+	d.x = 0
+	if d.y < d.rows-1 {
+		d.y++
+		return
+	}
+	d.Scroll()
+}
+
+// Scroll moves all rows up by one, and clears the last row.
+func (d *Display) Scroll() {
+	columns := int(d.columns)
+	copy(d.cells, d.cells[columns:])
+	for i := len(d.cells) - columns; i < len(d.cells); i++ {
+		d.cells[i] = ' '
+	}
+	for row := int16(0); row < d.rows; row++ {
+		start := int(row) * columns
+		d.dev.WriteAt(0, row, d.cells[start:start+columns])
+	}
+}
+
+// String returns the contents of a row.
+func (d *Display) String(row int16) string {
+	if row < 0 || row >= d.rows {
+		return ""
+	}
+	start := int(row) * int(d.columns)
+	return string(d.cells[start : start+int(d.columns)])
+}
@@ -0,0 +1,111 @@
+package textdisplay_test
+
+import (
+	"fmt"
+	"testing"
+
+	"tinygo.org/x/drivers/textdisplay"
+)
+
+// fakeDevice is a character display in memory.
+type fakeDevice struct {
+	columns, rows int16
+	cells         [][]byte
+	writes        int
+}
+
+func newFakeDevice(columns, rows int16) *fakeDevice {
+	d := &fakeDevice{columns: columns, rows: rows}
+	d.ClearDisplay()
+	return d
+}
+
+func (d *fakeDevice) Size() (int16, int16) {
+	return d.columns, d.rows
+}
+
+func (d *fakeDevice) ClearDisplay() {
+	d.cells = make([][]byte, d.rows)
+	for i := range d.cells {
+		d.cells[i] = make([]byte, d.columns)
+		for j := range d.cells[i] {
+			d.cells[i][j] = ' '
+		}
+	}
+}
+
+func (d *fakeDevice) WriteAt(column, row int16, text []byte) {
+	if int(column)+len(text) > int(d.columns) {
+		panic("text doesn't fit")
+	}
+	copy(d.cells[row][column:], text)
+	d.writes++
+}
+
+func (d *fakeDevice) CreateGlyph(code byte, data []byte) error {
+	return nil
+}
+
+func (d *fakeDevice) check(t *testing.T, expected ...string) {
+	t.Helper()
+	for i, row := range d.cells {
+		if string(row) != expected[i] {
+			t.Errorf("row %d: expected %q, got %q", i, expected[i], row)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dev := newFakeDevice(8, 2)
+	d := textdisplay.New(dev)
+
+	if n, err := d.Write([]byte("Hello\nworld")); n != 11 || err != nil {
+		t.Errorf("Write returned %d, %v, expected 11, nil", n, err)
+	}
+	dev.check(t, "Hello   ", "world   ")
+	if x, y := d.Cursor(); x != 5 || y != 1 {
+		t.Errorf("unexpected cursor position %d,%d", x, y)
+	}
+
+	// Overwrite the start of the row.
+	if n, err := fmt.Fprint(d, "\rW"); n != 2 || err != nil {
+		t.Errorf("Fprint returned %d, %v, expected 2, nil", n, err)
+	}
+	dev.check(t, "Hello   ", "World   ")
+
+	// Long lines wrap, and the display scrolls.
+	d.SetCursor(6, 1)
+	fmt.Fprint(d, "!!abcdefghij")
+	dev.check(t, "abcdefgh", "ij      ")
+	fmt.Fprint(d, "\n")
+	dev.check(t, "ij      ", "        ")
+	if d.String(0) != "ij      " {
+		t.Errorf("unexpected row contents %q", d.String(0))
+	}
+
+	d.Clear()
+	dev.check(t, "        ", "        ")
+	d.WriteAt(-2, 0, []byte("xxabcdefghijkl"))
+	dev.check(t, "abcdefgh", "        ")
+
+	// A display without columns discards the text.
+	empty := textdisplay.New(newFakeDevice(0, 2))
+	if n, err := empty.Write([]byte("ab\ncd")); n != 5 || err != nil {
+		t.Errorf("Write on empty display returned %d, %v, expected 5, nil", n, err)
+	}
+}
+
+func TestMarquee(t *testing.T) {
+	dev := newFakeDevice(4, 1)
+	d := textdisplay.New(dev)
+
+	m := textdisplay.NewMarquee(d, 0, "abc")
+	m.Step()
+	dev.check(t, "abc ")
+
+	m.SetText("abcdef")
+	for _, expected := range []string{"    ", "   a", "  ab", " abc", "abcd", "bcde", "cdef", "def ", "ef  ", "f   ", "    ", "    ", "   a"} {
+		m.Step()
+		dev.check(t, expected)
+	}
+}
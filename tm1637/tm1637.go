@@ -6,6 +6,9 @@ package tm1637
 import (
 	"machine"
 	"time"
+
+	"tinygo.org/x/drivers/sevenseg"
+	"tinygo.org/x/drivers/textdisplay"
 )
 
 // Device wraps the pins of the TM1637.
@@ -13,6 +16,7 @@ type Device struct {
 	clk        machine.Pin
 	dio        machine.Pin
 	brightness uint8
+	glyphs     [8]byte // custom glyphs for character codes 0-7
 }
 
 // New creates a new TM1637 device.
@@ -52,7 +56,7 @@ func (d *Device) DisplayText(text []byte) {
 		if i > 3 {
 			break
 		}
-		sequences = append(sequences, encodeChr(t))
+		sequences = append(sequences, d.encodeChr(t))
 	}
 	d.writeData(sequences, 0)
 }
@@ -63,7 +67,7 @@ func (d *Device) DisplayChr(chr byte, pos uint8) {
 	if pos > 3 {
 		pos = 3
 	}
-	d.writeData([]byte{encodeChr(chr)}, pos)
+	d.writeData([]byte{d.encodeChr(chr)}, pos)
 }
 
 // DisplayNumber shows a number on the display.
@@ -76,7 +80,7 @@ func (d *Device) DisplayNumber(num int16) {
 	var sequences []byte
 	var start int16
 	if num < 0 {
-		sequences = append(sequences, segments[37])
+		sequences = append(sequences, sevenseg.G)
 		num *= -1
 		start = 100
 		num %= 1000
@@ -87,10 +91,10 @@ func (d *Device) DisplayNumber(num int16) {
 	for i := start; i >= 1; i /= 10 {
 		if num >= i {
 			n := (num / int16(i)) % 10
-			sequences = append(sequences, segments[n])
+			sequences = append(sequences, sevenseg.Digits[n])
 		} else {
 			if i == 1 && num == 0 {
-				sequences = append(sequences, segments[0])
+				sequences = append(sequences, sevenseg.Digits[0])
 			} else {
 				sequences = append(sequences, 0)
 			}
@@ -103,7 +107,7 @@ func (d *Device) DisplayNumber(num int16) {
 // at position 0-3.
 func (d *Device) DisplayDigit(digit uint8, pos uint8) {
 	digit %= 10
-	d.writeData([]byte{segments[digit]}, pos)
+	d.writeData([]byte{sevenseg.Digits[digit]}, pos)
 }
 
 // DisplayClock allows you to display hour and minute numbers
@@ -114,7 +118,7 @@ func (d *Device) DisplayClock(num1 uint8, num2 uint8, colon bool) {
 	for k := 0; k < 2; k++ {
 		for i := 10; i >= 1; i /= 10 {
 			n := (num[k] / uint8(i)) % 10
-			sequences = append(sequences, segments[n])
+			sequences = append(sequences, sevenseg.Digits[n])
 		}
 	}
 	if colon {
@@ -123,24 +127,44 @@ func (d *Device) DisplayClock(num1 uint8, num2 uint8, colon bool) {
 	d.writeData(sequences, 0)
 }
 
-func encodeChr(c byte) byte {
-	r := rune(c)
-	switch {
-	case r == 32:
-		return segments[36] // space
-	case r == 42:
-		return segments[38] // star/degrees
-	case r == 45:
-		return segments[37] // dash
-	case r >= 65 && r <= 90:
-		return segments[r-55] // uppercase A-Z
-	case r >= 97 && r <= 122:
-		return segments[r-87] // lowercase a-z
-	case r >= 48 && r <= 57:
-		return segments[r-48] // 0-9
-	default:
-		return byte(0)
+func (d *Device) encodeChr(c byte) byte {
+	if c < byte(len(d.glyphs)) {
+		return d.glyphs[c]
+	}
+	return sevenseg.Encode(c)
+}
+
+// Size returns the number of digits of the display, as one row of 4 digits.
+func (d *Device) Size() (columns, rows int16) {
+	return 4, 1
+}
+
+// WriteAt shows the text starting at the given digit. It implements
+// textdisplay.Device.
+func (d *Device) WriteAt(column, row int16, text []byte) {
+	if row != 0 || column < 0 || column > 3 {
+		return
+	}
+	var sequences [4]byte
+	n := copy(sequences[:4-column], text)
+	for i := 0; i < n; i++ {
+		sequences[i] = d.encodeChr(sequences[i])
+	}
+	d.writeData(sequences[:n], uint8(column))
+}
+
+// CreateGlyph defines the segments (see package sevenseg) that are shown for
+// the character code 0-7. The data must be a single byte. It implements
+// textdisplay.Device.
+func (d *Device) CreateGlyph(code byte, data []byte) error {
+	if code >= byte(len(d.glyphs)) {
+		return textdisplay.ErrGlyphCode
+	}
+	if len(data) != 1 {
+		return textdisplay.ErrGlyphSize
 	}
+	d.glyphs[code] = data[0]
+	return nil
 }
 
 func delaytm() {
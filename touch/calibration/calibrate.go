@@ -0,0 +1,114 @@
+package calibration
+
+import (
+	"errors"
+	"image/color"
+	"time"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/touch"
+)
+
+var errPoints = errors.New("calibration: number of points must be 3 or 5")
+
+// Size of the crosshair targets, in pixels from the center.
+const targetSize = 8
+
+// Number of raw samples averaged for every target.
+const samplesPerTarget = 8
+
+// Calibrate asks the user to touch 3 or 5 targets drawn on the display, and
+// returns the transform computed from the touches. Using 5 points takes a bit
+// longer but averages out the inaccuracy of a single touch. Touches with a
+// pressure at or below config.PressureThreshold are ignored.
+//
+// The targets are drawn in white and erased in black, so the display should be
+// cleared to black first. Calibrate blocks until all targets are touched.
+func Calibrate(display drivers.Displayer, pointer touch.Pointer, points int, config Config) (Matrix, error) {
+	width, height := display.Size()
+	var targets [][2]int16
+	switch points {
+	case 3:
+		targets = [][2]int16{{10, 10}, {90, 50}, {50, 90}}
+	case 5:
+		targets = [][2]int16{{10, 10}, {90, 10}, {90, 90}, {10, 90}, {50, 50}}
+	default:
+		return Matrix{}, errPoints
+	}
+
+	rotation := drivers.Rotation(drivers.Rotation0)
+	if r, ok := display.(rotator); ok {
+		rotation = r.Rotation()
+	}
+	physicalWidth, physicalHeight := rotation.Size(width, height)
+
+	samples := make([]Sample, len(targets))
+	for i, target := range targets {
+		x := int16(int32(target[0]) * int32(width) / 100)
+		y := int16(int32(target[1]) * int32(height) / 100)
+		if err := drawTarget(display, x, y, color.RGBA{R: 255, G: 255, B: 255, A: 255}); err != nil {
+			return Matrix{}, err
+		}
+		raw := readTarget(pointer, config.PressureThreshold)
+		if err := drawTarget(display, x, y, color.RGBA{A: 255}); err != nil {
+			return Matrix{}, err
+		}
+
+		// The matrix is independent of the rotation, so that the display can
+		// be rotated afterwards.
+		samples[i].Raw = raw
+		samples[i].X, samples[i].Y = rotation.Transform(x, y, physicalWidth, physicalHeight)
+	}
+	return Compute(samples)
+}
+
+// drawTarget draws a crosshair centered on x, y.
+func drawTarget(display drivers.Displayer, x, y int16, c color.RGBA) error {
+	for i := int16(-targetSize); i <= targetSize; i++ {
+		display.SetPixel(x+i, y, c)
+		display.SetPixel(x, y+i, c)
+	}
+	return display.Display()
+}
+
+// readTarget waits for a touch, and returns the average of the raw samples
+// read while touching. It returns once the touch is released.
+func readTarget(pointer touch.Pointer, threshold int) touch.Point {
+	pressed := func(p touch.Point) bool {
+		return p.Z > threshold && p.Z > 0
+	}
+
+	var sum touch.Point
+	for n := 0; n < samplesPerTarget; {
+		for !pressed(pointer.ReadTouchPoint()) {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		// Give the touch some time to settle, then sample it. Start over
+		// when it's released too early.
+		time.Sleep(50 * time.Millisecond)
+		sum, n = touch.Point{}, 0
+		for ; n < samplesPerTarget; n++ {
+			p := pointer.ReadTouchPoint()
+			if !pressed(p) {
+				break
+			}
+			sum.X += p.X
+			sum.Y += p.Y
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// Wait until released for a while, so that a bouncing touch isn't taken
+	// as the touch of the next target.
+	for released := 0; released < 5; {
+		if pressed(pointer.ReadTouchPoint()) {
+			released = 0
+		} else {
+			released++
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return touch.Point{X: sum.X / samplesPerTarget, Y: sum.Y / samplesPerTarget, Z: 1}
+}
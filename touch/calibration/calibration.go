@@ -0,0 +1,188 @@
+// Package calibration maps the raw coordinates of a touch screen controller to
+// display coordinates.
+//
+// Touch controllers such as the xpt2046, ft6336 and the resistive four-wire
+// driver return coordinates on their own scale, which doesn't line up exactly
+// with the display: the touch panel may be shifted, slightly rotated or
+// mirrored relative to the display. An affine transform, computed from three or
+// more touches on known positions, corrects all of these at once.
+//
+// Calibrate draws targets on the display and computes the transform from the
+// touches. The resulting Matrix can be stored (see MarshalBinary) so that the
+// calibration only needs to be done once. Pointer wraps a touch.Pointer and
+// returns display coordinates, following the rotation of the display.
+package calibration // import "tinygo.org/x/drivers/touch/calibration"
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"tinygo.org/x/drivers/touch"
+)
+
+var (
+	errTooFewSamples = errors.New("calibration: at least 3 samples are needed")
+	errDegenerate    = errors.New("calibration: samples are on a line or too close together")
+	errInvalidData   = errors.New("calibration: invalid matrix data")
+)
+
+// Number of fractional bits of the matrix coefficients.
+const fractionBits = 20
+
+// Matrix is an affine transform from raw touch coordinates to display
+// coordinates, without display rotation. The coefficients are fixed point
+// numbers with 20 fractional bits:
+//
+//	x = (A*rawX + B*rawY + C) / 2^20
+//	y = (D*rawX + E*rawY + F) / 2^20
+type Matrix struct {
+	A, B, C int32
+	D, E, F int32
+}
+
+// Identity is the transform that leaves coordinates unchanged, for touch
+// controllers that already return display coordinates.
+var Identity = Matrix{A: 1 << fractionBits, E: 1 << fractionBits}
+
+// Scale returns the transform for a touch screen that is perfectly aligned with
+// the display, given the raw values at the left, right, top and bottom edges of
+// the display. This is what most applications hardcode without calibration.
+func Scale(rawLeft, rawRight, rawTop, rawBottom int, width, height int16) Matrix {
+	m := Matrix{}
+	if rawRight != rawLeft {
+		m.A = int32((int64(width) << fractionBits) / int64(rawRight-rawLeft))
+		m.C = int32(-int64(m.A) * int64(rawLeft))
+	}
+	if rawBottom != rawTop {
+		m.E = int32((int64(height) << fractionBits) / int64(rawBottom-rawTop))
+		m.F = int32(-int64(m.E) * int64(rawTop))
+	}
+	return m
+}
+
+// Transform converts raw touch coordinates to display coordinates.
+func (m Matrix) Transform(rawX, rawY int) (x, y int) {
+	const half = 1 << (fractionBits - 1) // for rounding
+	fx := int64(m.A)*int64(rawX) + int64(m.B)*int64(rawY) + int64(m.C) + half
+	fy := int64(m.D)*int64(rawX) + int64(m.E)*int64(rawY) + int64(m.F) + half
+	return int(fx >> fractionBits), int(fy >> fractionBits)
+}
+
+// Size of the serialized matrix: a two byte header and six coefficients.
+const matrixSize = 2 + 6*4
+
+// MarshalBinary returns the matrix as a 26 byte array, for example to store it
+// in flash.
+func (m Matrix) MarshalBinary() ([]byte, error) {
+	data := make([]byte, matrixSize)
+	data[0] = 'T'
+	data[1] = 1 // version
+	for i, v := range [6]int32{m.A, m.B, m.C, m.D, m.E, m.F} {
+		binary.LittleEndian.PutUint32(data[2+i*4:], uint32(v))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary reads a matrix stored with MarshalBinary.
+func (m *Matrix) UnmarshalBinary(data []byte) error {
+	if len(data) != matrixSize || data[0] != 'T' || data[1] != 1 {
+		return errInvalidData
+	}
+	var v [6]int32
+	for i := range v {
+		v[i] = int32(binary.LittleEndian.Uint32(data[2+i*4:]))
+	}
+	*m = Matrix{v[0], v[1], v[2], v[3], v[4], v[5]}
+	return nil
+}
+
+// Sample is a touch on a known position of the display.
+type Sample struct {
+	// Raw touch coordinates, as returned by the touch controller.
+	Raw touch.Point
+
+	// Position on the display without rotation.
+	X, Y int16
+}
+
+// Compute returns the transform that best maps the raw coordinates of the
+// samples to their display positions. Three samples give an exact transform,
+// more samples are combined with a least squares fit to average out the errors
+// of the individual touches.
+func Compute(samples []Sample) (Matrix, error) {
+	if len(samples) < 3 {
+		return Matrix{}, errTooFewSamples
+	}
+
+	// Solve the normal equations (MᵀM)·c = Mᵀ·v, where every row of M is
+	// (rawX, rawY, 1) and v is the display x or y coordinate. The raw
+	// coordinates are centered first to keep the numbers small.
+	var cx, cy float64
+	for _, s := range samples {
+		cx += float64(s.Raw.X)
+		cy += float64(s.Raw.Y)
+	}
+	n := float64(len(samples))
+	cx /= n
+	cy /= n
+
+	var mtm [3][3]float64
+	var mtx, mty [3]float64
+	for _, s := range samples {
+		row := [3]float64{float64(s.Raw.X) - cx, float64(s.Raw.Y) - cy, 1}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				mtm[i][j] += row[i] * row[j]
+			}
+			mtx[i] += row[i] * float64(s.X)
+			mty[i] += row[i] * float64(s.Y)
+		}
+	}
+
+	// With centered coordinates the determinant is n·(Sxx·Syy - Sxy²), which
+	// is close to zero relative to n·Sxx·Syy when the samples are on a line.
+	det := det3(mtm)
+	if det <= 1e-6*mtm[0][0]*mtm[1][1]*mtm[2][2] {
+		return Matrix{}, errDegenerate
+	}
+	a, b, c := solve3(mtm, mtx, det)
+	d, e, f := solve3(mtm, mty, det)
+
+	// Undo the centering: v = a*(x-cx) + b*(y-cy) + c.
+	c -= a*cx + b*cy
+	f -= d*cx + e*cy
+
+	const scale = 1 << fractionBits
+	return Matrix{
+		A: round(a * scale), B: round(b * scale), C: round(c * scale),
+		D: round(d * scale), E: round(e * scale), F: round(f * scale),
+	}, nil
+}
+
+// det3 returns the determinant of a 3x3 matrix.
+func det3(m [3][3]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// solve3 solves m·x = v using Cramer's rule, given the determinant of m.
+func solve3(m [3][3]float64, v [3]float64, det float64) (float64, float64, float64) {
+	var x [3]float64
+	for col := 0; col < 3; col++ {
+		mc := m
+		for row := 0; row < 3; row++ {
+			mc[row][col] = v[row]
+		}
+		x[col] = det3(mc) / det
+	}
+	return x[0], x[1], x[2]
+}
+
+// round rounds to the nearest integer.
+func round(v float64) int32 {
+	if v < 0 {
+		return int32(v - 0.5)
+	}
+	return int32(v + 0.5)
+}
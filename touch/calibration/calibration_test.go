@@ -0,0 +1,197 @@
+package calibration
+
+import (
+	"testing"
+
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/touch"
+)
+
+// panel simulates a touch panel that is mirrored, slightly rotated and offset
+// relative to a 240x320 display, like a typical resistive panel.
+func panel(x, y int) touch.Point {
+	return touch.Point{
+		X: 60000 - 230*x + 3*y,
+		Y: 4000 + 2*x + 180*y,
+		Z: 100,
+	}
+}
+
+func checkMatrix(t *testing.T, m Matrix) {
+	t.Helper()
+	for _, p := range [][2]int{{0, 0}, {239, 0}, {120, 160}, {17, 300}, {239, 319}} {
+		raw := panel(p[0], p[1])
+		x, y := m.Transform(raw.X, raw.Y)
+		if x != p[0] || y != p[1] {
+			t.Errorf("expected %d,%d for raw %d,%d, got %d,%d", p[0], p[1], raw.X, raw.Y, x, y)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	var samples []Sample
+	for _, p := range [][2]int16{{24, 32}, {216, 160}, {120, 288}} {
+		samples = append(samples, Sample{Raw: panel(int(p[0]), int(p[1])), X: p[0], Y: p[1]})
+	}
+	m, err := Compute(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMatrix(t, m)
+
+	// Five points with some noise.
+	samples = nil
+	noise := []int{50, -40, 30, 0, -50}
+	for i, p := range [][2]int16{{24, 32}, {216, 32}, {216, 288}, {24, 288}, {120, 160}} {
+		raw := panel(int(p[0]), int(p[1]))
+		raw.X += noise[i]
+		raw.Y -= noise[i]
+		samples = append(samples, Sample{Raw: raw, X: p[0], Y: p[1]})
+	}
+	m, err = Compute(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMatrix(t, m)
+}
+
+func TestComputeDegenerate(t *testing.T) {
+	samples := []Sample{
+		{Raw: panel(10, 10), X: 10, Y: 10},
+		{Raw: panel(50, 50), X: 50, Y: 50},
+		{Raw: panel(90, 90), X: 90, Y: 90},
+	}
+	if _, err := Compute(samples); err != errDegenerate {
+		t.Errorf("expected error for samples on a line, got %v", err)
+	}
+	if _, err := Compute(samples[:2]); err != errTooFewSamples {
+		t.Errorf("expected error for two samples, got %v", err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	m := Matrix{A: -1, B: 2, C: -3 << 20, D: 4, E: 5 << 20, F: 1<<31 - 1}
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m2 Matrix
+	if err := m2.UnmarshalBinary(data); err != nil || m2 != m {
+		t.Errorf("expected %v, got %v (%v)", m, m2, err)
+	}
+	if err := m2.UnmarshalBinary(data[1:]); err != errInvalidData {
+		t.Errorf("expected error for short data, got %v", err)
+	}
+	if err := m2.UnmarshalBinary(make([]byte, len(data))); err != errInvalidData {
+		t.Errorf("expected error for empty data, got %v", err)
+	}
+}
+
+func TestScale(t *testing.T) {
+	m := Scale(48000, 20800, 6400, 53760, 240, 320)
+	for _, p := range [][4]int{{48000, 6400, 0, 0}, {20800, 53760, 240, 320}, {34400, 30080, 120, 160}} {
+		if x, y := m.Transform(p[0], p[1]); x != p[2] || y != p[3] {
+			t.Errorf("expected %d,%d for raw %d,%d, got %d,%d", p[2], p[3], p[0], p[1], x, y)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	const width, height = 5, 8
+	for r := drivers.Rotation(0); r < 8; r++ {
+		w, h := r.Size(width, height)
+		for y := int16(0); y < h; y++ {
+			for x := int16(0); x < w; x++ {
+				px, py := r.Transform(x, y, width, height)
+				if rx, ry := rotate(r, px, py, width, height); rx != x || ry != y {
+					t.Fatalf("rotation %d: %d,%d was transformed to %d,%d and back to %d,%d", r, x, y, px, py, rx, ry)
+				}
+			}
+		}
+	}
+}
+
+type fakePointer struct {
+	points []touch.Point
+}
+
+func (p *fakePointer) ReadTouchPoint() touch.Point {
+	point := p.points[0]
+	p.points = p.points[1:]
+	return point
+}
+
+type fakeMultiPointer []touch.TrackedPoint
+
+func (p fakeMultiPointer) ReadTouchPoint() touch.Point {
+	return p[0].Point
+}
+
+func (p fakeMultiPointer) ReadTouchPoints(points []touch.TrackedPoint) int {
+	return copy(points, p)
+}
+
+type fakeDisplay struct {
+	rotation drivers.Rotation
+}
+
+func (d *fakeDisplay) Size() (int16, int16) {
+	return d.rotation.Size(240, 320)
+}
+
+func (d *fakeDisplay) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+func TestPointer(t *testing.T) {
+	raw := &fakePointer{}
+	display := &fakeDisplay{}
+	pointer := New(raw, display, Identity, Config{PressureThreshold: 10})
+
+	steps := []struct {
+		raw      touch.Point
+		expected touch.Point
+	}{
+		{touch.Point{X: 100, Y: 200, Z: 50}, touch.Point{X: 100, Y: 200, Z: 50}},
+		{touch.Point{X: 101, Y: 198, Z: 50}, touch.Point{X: 100, Y: 200, Z: 50}}, // jitter
+		{touch.Point{X: 180, Y: 100, Z: 50}, touch.Point{X: 100, Y: 200, Z: 50}}, // spike
+		{touch.Point{X: 110, Y: 210, Z: 50}, touch.Point{X: 110, Y: 198, Z: 50}}, // median of the last three
+		{touch.Point{X: 110, Y: 210, Z: 5}, touch.Point{}},                       // below threshold
+		{touch.Point{X: 500, Y: -10, Z: 50}, touch.Point{X: 239, Y: 0, Z: 50}},   // clamped
+	}
+	for i, step := range steps {
+		raw.points = append(raw.points, step.raw)
+		if p := pointer.ReadTouchPoint(); p != step.expected {
+			t.Errorf("step %d: expected %v, got %v", i, step.expected, p)
+		}
+	}
+
+	// The position follows the rotation of the display.
+	display.rotation = drivers.Rotation90
+	raw.points = append(raw.points, touch.Point{}, touch.Point{X: 230, Y: 20, Z: 50})
+	pointer.ReadTouchPoint()
+	if p := pointer.ReadTouchPoint(); p != (touch.Point{X: 20, Y: 9, Z: 50}) {
+		t.Errorf("expected rotated position 20,9, got %v", p)
+	}
+}
+
+func TestPointerMulti(t *testing.T) {
+	raw := fakeMultiPointer{
+		{Point: touch.Point{X: 10, Y: 20, Z: 1}, ID: 0, Event: touch.EventMove},
+		{Point: touch.Point{X: 100, Y: 400, Z: 1}, ID: 1, Event: touch.EventDown},
+	}
+	pointer := New(raw, &fakeDisplay{rotation: drivers.Rotation180}, Identity, Config{})
+	points := make([]touch.TrackedPoint, 3)
+	if n := pointer.ReadTouchPoints(points); n != 2 {
+		t.Fatalf("expected 2 touches, got %d", n)
+	}
+	expected := []touch.TrackedPoint{
+		{Point: touch.Point{X: 229, Y: 299, Z: 1}, ID: 0, Event: touch.EventMove},
+		{Point: touch.Point{X: 139, Y: 0, Z: 1}, ID: 1, Event: touch.EventDown},
+	}
+	for i, p := range expected {
+		if points[i] != p {
+			t.Errorf("expected touch %d to be %v, got %v", i, p, points[i])
+		}
+	}
+}
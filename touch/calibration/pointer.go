@@ -0,0 +1,170 @@
+package calibration
+
+import (
+	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/touch"
+)
+
+// Sizer is a display with a size. Displays that also have a Rotation method
+// are rotated by Pointer.
+type Sizer interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+}
+
+// rotator is implemented by displays that can be rotated.
+type rotator interface {
+	Rotation() drivers.Rotation
+}
+
+// Config is the configuration of a Pointer.
+type Config struct {
+	// Touches with a pressure (Z) at or below this value are ignored. The
+	// default of 0 accepts all touches reported by the controller.
+	PressureThreshold int
+
+	// Movements of at most this many pixels are ignored while touching, to
+	// keep the position from jittering. The default is 2, use a negative
+	// value to disable.
+	Jitter int16
+}
+
+// Pointer wraps a touch.Pointer and returns display coordinates.
+//
+// The raw coordinates are transformed with the calibration matrix, filtered,
+// and rotated to follow the rotation of the display, so that the returned X
+// and Y can be used directly for drawing. Z is the pressure as returned by the
+// touch controller, or 0 when the display is not touched.
+type Pointer struct {
+	pointer touch.Pointer
+	display Sizer
+	matrix  Matrix
+	config  Config
+	pressed bool
+	history [3][2]int16 // last positions, for the median filter
+	next    int
+	lastX   int16
+	lastY   int16
+}
+
+// New returns a Pointer that maps the coordinates of pointer to the display,
+// using a matrix returned by Calibrate or Compute.
+func New(pointer touch.Pointer, display Sizer, matrix Matrix, config Config) *Pointer {
+	if config.Jitter == 0 {
+		config.Jitter = 2
+	}
+	return &Pointer{
+		pointer: pointer,
+		display: display,
+		matrix:  matrix,
+		config:  config,
+	}
+}
+
+// Matrix returns the calibration matrix in use.
+func (p *Pointer) Matrix() Matrix {
+	return p.matrix
+}
+
+// SetMatrix changes the calibration matrix, for example after calibrating
+// again.
+func (p *Pointer) SetMatrix(matrix Matrix) {
+	p.matrix = matrix
+}
+
+// ReadTouchPoint reads the touch controller and returns the position on the
+// display, implementing touch.Pointer.
+func (p *Pointer) ReadTouchPoint() touch.Point {
+	raw := p.pointer.ReadTouchPoint()
+	if raw.Z <= p.config.PressureThreshold || raw.Z <= 0 {
+		p.pressed = false
+		return touch.Point{}
+	}
+
+	width, height, rotation := p.physicalSize()
+	x, y := p.matrix.Transform(raw.X, raw.Y)
+	px, py := int16(clamp(x, int(width)-1)), int16(clamp(y, int(height)-1))
+
+	if !p.pressed {
+		// Start filtering from scratch for a new touch.
+		p.pressed = true
+		p.history = [3][2]int16{{px, py}, {px, py}, {px, py}}
+		p.lastX, p.lastY = px, py
+	} else {
+		p.history[p.next] = [2]int16{px, py}
+		p.next = (p.next + 1) % len(p.history)
+		px = median(p.history[0][0], p.history[1][0], p.history[2][0])
+		py = median(p.history[0][1], p.history[1][1], p.history[2][1])
+		if abs(px-p.lastX) > p.config.Jitter || abs(py-p.lastY) > p.config.Jitter {
+			p.lastX, p.lastY = px, py
+		}
+	}
+
+	sx, sy := rotate(rotation, p.lastX, p.lastY, width, height)
+	return touch.Point{X: int(sx), Y: int(sy), Z: raw.Z}
+}
+
+// ReadTouchPoints reads the touches of the wrapped pointer and maps them to the
+// display like ReadTouchPoint, implementing touch.MultiPointer. The touches are
+// not filtered. It returns 0 when the wrapped pointer isn't a
+// touch.MultiPointer.
+func (p *Pointer) ReadTouchPoints(points []touch.TrackedPoint) int {
+	multi, ok := p.pointer.(touch.MultiPointer)
+	if !ok {
+		return 0
+	}
+	n := multi.ReadTouchPoints(points)
+	width, height, rotation := p.physicalSize()
+	for i := range points[:n] {
+		x, y := p.matrix.Transform(points[i].X, points[i].Y)
+		px, py := int16(clamp(x, int(width)-1)), int16(clamp(y, int(height)-1))
+		sx, sy := rotate(rotation, px, py, width, height)
+		points[i].X, points[i].Y = int(sx), int(sy)
+	}
+	return n
+}
+
+// physicalSize returns the size and rotation of the display, where the size is
+// without rotation.
+func (p *Pointer) physicalSize() (int16, int16, drivers.Rotation) {
+	width, height := p.display.Size()
+	rotation := drivers.Rotation(drivers.Rotation0)
+	if r, ok := p.display.(rotator); ok {
+		rotation = r.Rotation()
+		width, height = rotation.Size(width, height)
+	}
+	return width, height, rotation
+}
+
+// rotate converts a position on the display without rotation to a position on
+// the rotated display. It is the inverse of drivers.Rotation.Transform.
+func rotate(r drivers.Rotation, x, y, width, height int16) (int16, int16) {
+	switch r % 4 {
+	case drivers.Rotation90:
+		x, y = y, width-1-x
+	case drivers.Rotation180:
+		x, y = width-1-x, height-1-y
+	case drivers.Rotation270:
+		x, y = height-1-y, x
+	}
+	if r.IsMirrored() {
+		w, _ := r.Size(width, height)
+		x = w - 1 - x
+	}
+	return x, y
+}
+
+func median(a, b, c int16) int16 {
+	return max(min(a, b), min(max(a, b), c))
+}
+
+func clamp(v, upper int) int {
+	return max(0, min(v, upper))
+}
+
+func abs(v int16) int16 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,380 @@
+// Package gesture recognizes gestures such as taps, swipes and pinches from the
+// touches reported by a touch screen.
+//
+// The Recognizer works on a stream of touches, read from a touch.MultiPointer
+// with Update or from a single touch.Pointer with UpdatePoint, once every
+// iteration of the main loop. Distances are in the units of the touches, so
+// the touches should be in display coordinates, for example by reading them
+// through a calibration.Pointer.
+package gesture // import "tinygo.org/x/drivers/touch/gesture"
+
+import (
+	"math"
+	"time"
+
+	"tinygo.org/x/drivers/touch"
+)
+
+// Kind is the kind of a gesture.
+type Kind uint8
+
+const (
+	None Kind = iota
+
+	// Tap is a short touch that doesn't move.
+	Tap
+
+	// DoubleTap is a tap shortly after a tap on the same position. The first
+	// tap is reported as a Tap.
+	DoubleTap
+
+	// LongPress is a touch that doesn't move for a while. It is reported
+	// while still touching, and no other gesture is reported when the touch
+	// is released.
+	LongPress
+
+	// Swipe is a quick movement of a single touch.
+	Swipe
+
+	// Pinch is a change of the distance between two touches. It is reported
+	// repeatedly while the touches move.
+	Pinch
+)
+
+// String returns the name of the gesture kind.
+func (k Kind) String() string {
+	switch k {
+	case Tap:
+		return "tap"
+	case DoubleTap:
+		return "double tap"
+	case LongPress:
+		return "long press"
+	case Swipe:
+		return "swipe"
+	case Pinch:
+		return "pinch"
+	}
+	return "none"
+}
+
+// Direction is the direction of a swipe.
+type Direction uint8
+
+const (
+	Up Direction = iota
+	Down
+	Left
+	Right
+)
+
+// String returns the name of the direction.
+func (d Direction) String() string {
+	switch d {
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	case Left:
+		return "left"
+	}
+	return "right"
+}
+
+// Gesture is a recognized gesture.
+type Gesture struct {
+	Kind Kind
+
+	// Position of the gesture: where a tap or long press was, where a swipe
+	// started, or the center between the touches of a pinch.
+	X, Y int
+
+	// Direction of a swipe.
+	Direction Direction
+
+	// Distance between the touches of a pinch, and the change of the
+	// distance since the previous pinch gesture. The change is positive when
+	// the touches move apart.
+	Distance, Delta int
+}
+
+// Config is the configuration of a Recognizer. All distances are in the units
+// of the touches, the defaults assume pixels.
+type Config struct {
+	// Maximum movement of a touch for taps and long presses. The default is
+	// 10.
+	TapSlop int
+
+	// Maximum duration of a tap. The default is 300ms.
+	TapTime time.Duration
+
+	// Maximum time between the end of a tap and the start of the next one,
+	// and their maximum distance, for a double tap. The defaults are 300ms
+	// and 30.
+	DoubleTapTime time.Duration
+	DoubleTapSlop int
+
+	// Duration after which an unmoving touch is a long press. The default is
+	// 600ms.
+	LongPressTime time.Duration
+
+	// Minimum distance and maximum duration of a swipe. The defaults are 40
+	// and 500ms.
+	SwipeDistance int
+	SwipeTime     time.Duration
+
+	// Minimum change of the distance between two touches before a pinch is
+	// reported. The default is 8.
+	PinchThreshold int
+}
+
+// maxTouches is the number of touches that are tracked. Only the first two
+// are used for pinches.
+const maxTouches = 5
+
+// Recognizer recognizes gestures from a stream of touches.
+type Recognizer struct {
+	config     Config
+	now        func() time.Time
+	touches    [maxTouches]touch.TrackedPoint
+	numTouches int
+
+	// State of the current gesture, from the first touch down until all
+	// touches are released.
+	start       time.Time
+	startX      int
+	startY      int
+	moved       bool // moved more than TapSlop
+	multi       bool // more than one touch
+	longPressed bool
+	pinching    bool
+	distance    int // of the last pinch
+
+	// The previous tap, for double taps.
+	tapped bool
+	tapEnd time.Time
+	tapX   int
+	tapY   int
+
+	// Previous touch of UpdatePoint.
+	pressed bool
+	last    touch.Point
+}
+
+// New returns a new gesture recognizer.
+func New(config Config) *Recognizer {
+	if config.TapSlop == 0 {
+		config.TapSlop = 10
+	}
+	if config.TapTime == 0 {
+		config.TapTime = 300 * time.Millisecond
+	}
+	if config.DoubleTapTime == 0 {
+		config.DoubleTapTime = 300 * time.Millisecond
+	}
+	if config.DoubleTapSlop == 0 {
+		config.DoubleTapSlop = 30
+	}
+	if config.LongPressTime == 0 {
+		config.LongPressTime = 600 * time.Millisecond
+	}
+	if config.SwipeDistance == 0 {
+		config.SwipeDistance = 40
+	}
+	if config.SwipeTime == 0 {
+		config.SwipeTime = 500 * time.Millisecond
+	}
+	if config.PinchThreshold == 0 {
+		config.PinchThreshold = 8
+	}
+	return &Recognizer{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Update processes the touches read from a touch.MultiPointer, and returns the
+// gesture that was recognized, if any. It must be called regularly, also when
+// nothing is touched, to recognize long presses in time.
+func (r *Recognizer) Update(points []touch.TrackedPoint) Gesture {
+	now := r.now()
+	var gesture Gesture
+	for _, p := range points {
+		switch p.Event {
+		case touch.EventDown:
+			r.down(p, now)
+		case touch.EventMove:
+			r.move(p, now)
+		case touch.EventUp:
+			if g := r.up(p, now); gesture.Kind == None {
+				gesture = g
+			}
+		}
+	}
+	if gesture.Kind != None {
+		return gesture
+	}
+
+	if r.numTouches < 2 {
+		r.pinching = false
+	}
+	switch {
+	case r.numTouches >= 2:
+		return r.pinch()
+	case r.numTouches == 1 && !r.multi && !r.moved && !r.longPressed:
+		if now.Sub(r.start) >= r.config.LongPressTime {
+			r.longPressed = true
+			r.tapped = false
+			return Gesture{Kind: LongPress, X: r.startX, Y: r.startY}
+		}
+	}
+	return Gesture{}
+}
+
+// UpdatePoint processes a touch read from a touch.Pointer, which is touching
+// when Z is above zero, and returns the gesture that was recognized, if any.
+// Pinches are never recognized with a single touch.
+func (r *Recognizer) UpdatePoint(p touch.Point) Gesture {
+	var points [1]touch.TrackedPoint
+	switch {
+	case p.Z > 0 && r.pressed:
+		points[0] = touch.TrackedPoint{Point: p, Event: touch.EventMove}
+	case p.Z > 0:
+		points[0] = touch.TrackedPoint{Point: p, Event: touch.EventDown}
+	case r.pressed:
+		points[0] = touch.TrackedPoint{Point: r.last, Event: touch.EventUp}
+	default:
+		return r.Update(nil)
+	}
+	r.pressed = p.Z > 0
+	r.last = p
+	return r.Update(points[:])
+}
+
+func (r *Recognizer) down(p touch.TrackedPoint, now time.Time) {
+	if r.numTouches == 0 {
+		r.start = now
+		r.startX, r.startY = p.X, p.Y
+		r.moved = false
+		r.multi = false
+		r.longPressed = false
+	} else {
+		r.multi = true
+	}
+	if r.numTouches < maxTouches {
+		r.touches[r.numTouches] = p
+		r.numTouches++
+	}
+}
+
+func (r *Recognizer) move(p touch.TrackedPoint, now time.Time) {
+	i := r.find(p.ID)
+	if i < 0 {
+		// Missed the down event.
+		r.down(p, now)
+		return
+	}
+	r.touches[i] = p
+	if r.numTouches == 1 && r.farFromStart(p.X, p.Y) {
+		r.moved = true
+	}
+}
+
+func (r *Recognizer) up(p touch.TrackedPoint, now time.Time) Gesture {
+	i := r.find(p.ID)
+	if i < 0 {
+		return Gesture{}
+	}
+	copy(r.touches[i:], r.touches[i+1:r.numTouches])
+	r.numTouches--
+	if r.numTouches > 0 {
+		return Gesture{}
+	}
+
+	// The last touch was released, which ends the gesture.
+	if r.multi || r.longPressed {
+		return Gesture{}
+	}
+	if r.farFromStart(p.X, p.Y) {
+		r.moved = true
+	}
+	duration := now.Sub(r.start)
+	if !r.moved {
+		if duration > r.config.TapTime {
+			return Gesture{}
+		}
+		if r.tapped && r.start.Sub(r.tapEnd) <= r.config.DoubleTapTime &&
+			abs(r.startX-r.tapX) <= r.config.DoubleTapSlop && abs(r.startY-r.tapY) <= r.config.DoubleTapSlop {
+			r.tapped = false
+			return Gesture{Kind: DoubleTap, X: r.startX, Y: r.startY}
+		}
+		r.tapped = true
+		r.tapEnd = now
+		r.tapX, r.tapY = r.startX, r.startY
+		return Gesture{Kind: Tap, X: r.startX, Y: r.startY}
+	}
+
+	r.tapped = false
+	dx, dy := p.X-r.startX, p.Y-r.startY
+	if duration > r.config.SwipeTime || max(abs(dx), abs(dy)) < r.config.SwipeDistance {
+		return Gesture{}
+	}
+	gesture := Gesture{Kind: Swipe, X: r.startX, Y: r.startY}
+	switch {
+	case abs(dx) >= abs(dy) && dx < 0:
+		gesture.Direction = Left
+	case abs(dx) >= abs(dy):
+		gesture.Direction = Right
+	case dy < 0:
+		gesture.Direction = Up
+	default:
+		gesture.Direction = Down
+	}
+	return gesture
+}
+
+// pinch returns a pinch gesture when the distance between the first two
+// touches changed enough.
+func (r *Recognizer) pinch() Gesture {
+	a, b := r.touches[0], r.touches[1]
+	dx, dy := float64(a.X-b.X), float64(a.Y-b.Y)
+	distance := int(math.Sqrt(dx*dx + dy*dy))
+	if !r.pinching {
+		r.pinching = true
+		r.distance = distance
+		return Gesture{}
+	}
+	delta := distance - r.distance
+	if abs(delta) < r.config.PinchThreshold {
+		return Gesture{}
+	}
+	r.distance = distance
+	return Gesture{
+		Kind:     Pinch,
+		X:        (a.X + b.X) / 2,
+		Y:        (a.Y + b.Y) / 2,
+		Distance: distance,
+		Delta:    delta,
+	}
+}
+
+// find returns the index of the touch with the given ID, or -1.
+func (r *Recognizer) find(id uint8) int {
+	for i := 0; i < r.numTouches; i++ {
+		if r.touches[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *Recognizer) farFromStart(x, y int) bool {
+	return abs(x-r.startX) > r.config.TapSlop || abs(y-r.startY) > r.config.TapSlop
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,148 @@
+package gesture
+
+import (
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/internal/fakeclock"
+	"tinygo.org/x/drivers/touch"
+)
+
+// newRecognizer returns a recognizer that uses the returned fake clock.
+func newRecognizer() (*Recognizer, *fakeclock.Clock) {
+	clock := fakeclock.New()
+	r := New(Config{})
+	r.now = clock.Now
+	return r, clock
+}
+
+// step is a touch read with UpdatePoint, followed by a delay.
+type step struct {
+	x, y    int
+	touched bool
+	delay   time.Duration
+}
+
+// run processes the steps and returns the recognized gestures.
+func run(r *Recognizer, clock *fakeclock.Clock, steps []step) []Gesture {
+	var gestures []Gesture
+	for _, s := range steps {
+		p := touch.Point{X: s.x, Y: s.y}
+		if s.touched {
+			p.Z = 1
+		}
+		if g := r.UpdatePoint(p); g.Kind != None {
+			gestures = append(gestures, g)
+		}
+		clock.Advance(s.delay)
+	}
+	return gestures
+}
+
+func TestSingleTouch(t *testing.T) {
+	const ms = time.Millisecond
+	tests := []struct {
+		name     string
+		steps    []step
+		gestures []Gesture
+	}{
+		{"tap", []step{
+			{50, 50, true, 50 * ms},
+			{52, 49, true, 50 * ms},
+			{0, 0, false, 0},
+		}, []Gesture{{Kind: Tap, X: 50, Y: 50}}},
+		{"slow tap", []step{
+			{50, 50, true, 400 * ms},
+			{0, 0, false, 0},
+		}, nil},
+		{"double tap", []step{
+			{50, 50, true, 50 * ms},
+			{0, 0, false, 100 * ms},
+			{60, 55, true, 50 * ms},
+			{0, 0, false, 0},
+		}, []Gesture{{Kind: Tap, X: 50, Y: 50}, {Kind: DoubleTap, X: 60, Y: 55}}},
+		{"two taps", []step{
+			{50, 50, true, 50 * ms},
+			{0, 0, false, 500 * ms},
+			{50, 50, true, 50 * ms},
+			{0, 0, false, 0},
+		}, []Gesture{{Kind: Tap, X: 50, Y: 50}, {Kind: Tap, X: 50, Y: 50}}},
+		{"long press", []step{
+			{50, 50, true, 300 * ms},
+			{50, 50, true, 300 * ms},
+			{50, 50, true, 300 * ms},
+			{0, 0, false, 0},
+		}, []Gesture{{Kind: LongPress, X: 50, Y: 50}}},
+		{"swipe left", []step{
+			{100, 50, true, 50 * ms},
+			{80, 55, true, 50 * ms},
+			{40, 60, true, 50 * ms},
+			{0, 0, false, 0},
+		}, []Gesture{{Kind: Swipe, X: 100, Y: 50, Direction: Left}}},
+		{"swipe down", []step{
+			{50, 50, true, 100 * ms},
+			{60, 150, true, 0},
+			{0, 0, false, 0},
+		}, []Gesture{{Kind: Swipe, X: 50, Y: 50, Direction: Down}}},
+		{"slow drag", []step{
+			{50, 50, true, 400 * ms},
+			{150, 50, true, 400 * ms},
+			{0, 0, false, 0},
+		}, nil},
+	}
+	for _, tc := range tests {
+		r, clock := newRecognizer()
+		gestures := run(r, clock, tc.steps)
+		if len(gestures) != len(tc.gestures) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.gestures, gestures)
+			continue
+		}
+		for i := range gestures {
+			if gestures[i] != tc.gestures[i] {
+				t.Errorf("%s: expected %v, got %v", tc.name, tc.gestures, gestures)
+			}
+		}
+	}
+}
+
+func TestPinch(t *testing.T) {
+	r, clock := newRecognizer()
+	frame := func(points ...touch.TrackedPoint) Gesture {
+		clock.Advance(20 * time.Millisecond)
+		return r.Update(points)
+	}
+	point := func(id uint8, event touch.Event, x, y int) touch.TrackedPoint {
+		return touch.TrackedPoint{Point: touch.Point{X: x, Y: y, Z: 1}, ID: id, Event: event}
+	}
+
+	frame(point(0, touch.EventDown, 100, 100))
+	if g := frame(point(0, touch.EventMove, 100, 100), point(1, touch.EventDown, 100, 140)); g.Kind != None {
+		t.Errorf("unexpected gesture on second touch: %v", g)
+	}
+	if g := frame(point(0, touch.EventMove, 100, 98), point(1, touch.EventMove, 100, 142)); g.Kind != None {
+		t.Errorf("unexpected gesture below the threshold: %v", g)
+	}
+	g := frame(point(0, touch.EventMove, 100, 90), point(1, touch.EventMove, 100, 150))
+	expected := Gesture{Kind: Pinch, X: 100, Y: 120, Distance: 60, Delta: 20}
+	if g != expected {
+		t.Errorf("expected %v, got %v", expected, g)
+	}
+	g = frame(point(0, touch.EventMove, 100, 110), point(1, touch.EventMove, 100, 130))
+	if g.Kind != Pinch || g.Delta != -40 {
+		t.Errorf("expected pinch closing by 40, got %v", g)
+	}
+
+	// Releasing the touches doesn't result in a tap.
+	if g := frame(point(0, touch.EventUp, 100, 110), point(1, touch.EventMove, 100, 130)); g.Kind != None {
+		t.Errorf("unexpected gesture on release: %v", g)
+	}
+	if g := frame(point(1, touch.EventUp, 100, 130)); g.Kind != None {
+		t.Errorf("unexpected gesture on release: %v", g)
+	}
+
+	// A new gesture starts afterwards.
+	frame(point(0, touch.EventDown, 10, 10))
+	if g := frame(point(0, touch.EventUp, 10, 10)); g.Kind != Tap {
+		t.Errorf("expected tap, got %v", g)
+	}
+}
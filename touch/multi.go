@@ -0,0 +1,47 @@
+package touch
+
+// MultiPointer is a device that is capable of reading multiple touch points at
+// the same time, such as a capacitive touch screen controller.
+type MultiPointer interface {
+	// ReadTouchPoints reads the current touches into points, and returns the
+	// number of points read. Touches that don't fit in points are dropped.
+	ReadTouchPoints(points []TrackedPoint) int
+}
+
+// Event is the change of a touch since the previous read.
+type Event uint8
+
+const (
+	// EventDown is a touch that wasn't there on the previous read.
+	EventDown Event = iota + 1
+
+	// EventMove is a touch that was already there on the previous read,
+	// although it may not have moved.
+	EventMove
+
+	// EventUp is a touch that was lifted since the previous read. It is
+	// reported once, at the last known position.
+	EventUp
+)
+
+// String returns the name of the event.
+func (e Event) String() string {
+	switch e {
+	case EventDown:
+		return "down"
+	case EventMove:
+		return "move"
+	case EventUp:
+		return "up"
+	}
+	return "none"
+}
+
+// TrackedPoint is a touch point that is tracked between reads. The ID of a
+// touch stays the same from its down event up to and including its up event,
+// and may be reused for a later touch.
+type TrackedPoint struct {
+	Point
+	ID    uint8
+	Event Event
+}
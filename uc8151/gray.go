@@ -0,0 +1,93 @@
+package uc8151
+
+import (
+	"tinygo.org/x/drivers/internal/epdgray"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// DisplayGray shows a 4-level grayscale image on the whole screen. The image
+// must have the same size as the display (see Size) and is drawn using the
+// current rotation. It is sent to the display directly, the internal buffer is
+// left untouched.
+//
+// Grayscale works by sending the two bits of each pixel as the "old" and "new"
+// image, and using a different waveform for each of the four combinations. The
+// next call to Display or DisplayRect switches back to black and white, and
+// should use a full refresh (see SetFullRefresh) to remove all gray levels.
+func (d *Device) DisplayGray(img pixel.Image[pixel.Gray2]) error {
+	width, height := img.Size()
+	if w, h := d.Size(); width != int(w) || height != int(h) {
+		return errOutOfRange
+	}
+	if d.blocking {
+		d.WaitUntilIdle()
+	}
+
+	// The waveforms must be loaded from registers, not OTP.
+	d.SendCommand(PSR)
+	d.SendData(RES_128x296 | LUT_REG | FORMAT_BW | SHIFT_RIGHT | BOOSTER_ON | RESET_NONE | SCAN_UP)
+	d.setGrayLUT()
+	d.gray = true
+
+	d.PowerOn()
+	d.SendCommand(PTOU)
+
+	// The display is inverted compared to the gray levels (a set bit is black),
+	// so set the bits of the levels where the bit of the plane is clear.
+	row := make([]uint8, d.width/8)
+	for plane := 1; plane >= 0; plane-- {
+		levels := uint8(0b0011)
+		if plane == 1 {
+			d.SendCommand(DTM1)
+		} else {
+			d.SendCommand(DTM2)
+			levels = 0b0101
+		}
+		for y := int16(0); y < d.height; y++ {
+			epdgray.PackRow(row, img, d.rotation, d.width, d.height, y, levels)
+			d.SendData(row...)
+		}
+	}
+
+	d.SendCommand(DSP)
+	d.SendCommand(DRF)
+
+	if d.blocking {
+		d.WaitUntilIdle()
+		d.PowerOff()
+	}
+	return nil
+}
+
+// leaveGray restores the black and white settings after DisplayGray.
+func (d *Device) leaveGray() {
+	if !d.gray {
+		return
+	}
+	d.gray = false
+	d.SendCommand(PSR)
+	if d.speed == 0 {
+		d.SendData(RES_128x296 | LUT_OTP | FORMAT_BW | SHIFT_RIGHT | BOOSTER_ON | RESET_NONE | SCAN_UP)
+	} else {
+		d.SendData(RES_128x296 | LUT_REG | FORMAT_BW | SHIFT_RIGHT | BOOSTER_ON | RESET_NONE | SCAN_UP)
+	}
+	d.SetLUT(d.speed, d.flickerFree)
+}
+
+// setGrayLUT loads the waveforms for the four gray levels.
+func (d *Device) setGrayLUT() {
+	d.SendCommand(LUT_VCOM)
+	d.SendData(epdgray.LUTVCOM[:]...)
+
+	d.SendCommand(LUT_WW)
+	d.SendData(epdgray.LUTWW[:]...)
+
+	d.SendCommand(LUT_BW)
+	d.SendData(epdgray.LUTBW[:]...)
+
+	d.SendCommand(LUT_WB)
+	d.SendData(epdgray.LUTWB[:]...)
+
+	d.SendCommand(LUT_BB)
+	d.SendData(epdgray.LUTBB[:]...)
+}
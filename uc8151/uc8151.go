@@ -43,6 +43,8 @@ type Device struct {
 	speed                    Speed
 	blocking                 bool
 	flickerFree              bool
+	fullRefresh              bool
+	gray                     bool // DisplayGray changed the settings
 	updateCount, updateAfter int
 }
 
@@ -75,7 +77,7 @@ func (d *Device) Configure(cfg Config) {
 	} else {
 		d.height = EPD_HEIGHT
 	}
-	d.rotation = cfg.Rotation
+	d.rotation = cfg.Rotation % 8
 	d.speed = cfg.Speed
 	d.blocking = cfg.Blocking
 	d.flickerFree = cfg.FlickerFree
@@ -206,8 +208,9 @@ func (d *Device) Display() error {
 	if d.blocking {
 		d.WaitUntilIdle()
 	}
+	d.leaveGray()
 
-	if d.flickerFree && d.updateAfter != 0 && d.updateCount%d.updateAfter == 0 {
+	if d.fullRefresh || (d.flickerFree && d.updateAfter != 0 && d.updateCount%d.updateAfter == 0) {
 		// we need full refresh here
 		d.SetLUT(MEDIUM, false)
 	} else {
@@ -241,6 +244,7 @@ func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error
 	if d.blocking {
 		d.WaitUntilIdle()
 	}
+	d.leaveGray()
 
 	x, y = d.xy(x, y)
 	if x < 0 || y < 0 || x >= d.width || y >= d.height || width < 0 || height < 0 {
@@ -268,6 +272,10 @@ func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error
 		height = d.height
 	}
 
+	if d.fullRefresh {
+		d.SetLUT(MEDIUM, false)
+	}
+
 	d.SendCommand(PON)
 	d.SendCommand(PTIN)
 	d.SendCommand(PTL)
@@ -292,6 +300,10 @@ func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error
 	d.SendCommand(DSP)
 	d.SendCommand(DRF)
 
+	if d.fullRefresh {
+		d.SetLUT(d.speed, d.flickerFree)
+	}
+
 	if d.blocking {
 		d.WaitUntilIdle()
 		d.PowerOff()
@@ -318,9 +330,12 @@ func (d *Device) WaitUntilIdle() {
 	}
 }
 
-// IsBusy returns the busy status of the display
+// IsBusy returns the busy status of the display.
+//
+// The BUSY_N pin of the UC8151 is low while the controller is busy (see the
+// pin description in the datasheet), which is also how WaitUntilIdle reads it.
 func (d *Device) IsBusy() bool {
-	return d.busy.Get()
+	return !d.busy.Get()
 }
 
 // ClearBuffer sets the buffer to 0xFF (white)
@@ -332,10 +347,7 @@ func (d *Device) ClearBuffer() {
 
 // Size returns the current size of the display.
 func (d *Device) Size() (w, h int16) {
-	if d.rotation == drivers.Rotation90 || d.rotation == drivers.Rotation270 {
-		return d.height, d.width
-	}
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
 }
 
 // Rotation returns the currently configured rotation.
@@ -345,7 +357,7 @@ func (d *Device) Rotation() drivers.Rotation {
 
 // SetRotation changes the rotation (clock-wise) of the device
 func (d *Device) SetRotation(rotation drivers.Rotation) error {
-	d.rotation = rotation
+	d.rotation = rotation % 8
 	return nil
 }
 
@@ -365,19 +377,17 @@ func (d *Device) SetBlocking(blocking bool) {
 	d.blocking = blocking
 }
 
+// SetFullRefresh forces the following calls to Display and DisplayRect to use
+// a full refresh, which removes any ghosting left by flicker-free updates at
+// high speeds. When disabled, the configured speed and flicker-free mode are
+// used again.
+func (d *Device) SetFullRefresh(full bool) {
+	d.fullRefresh = full
+}
+
 // xy changes the coordinates according to the rotation
 func (d *Device) xy(x, y int16) (int16, int16) {
-	switch d.rotation {
-	case drivers.Rotation0:
-		return x, y
-	case drivers.Rotation90:
-		return d.width - y - 1, x
-	case drivers.Rotation180:
-		return d.width - x - 1, d.height - y - 1
-	case drivers.Rotation270:
-		return y, d.height - x - 1
-	}
-	return x, y
+	return d.rotation.Transform(x, y, d.width, d.height)
 }
 
 // SetSpeed changes the refresh speed of the device (the display needs to re-configure)
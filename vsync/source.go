@@ -0,0 +1,93 @@
+package vsync
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ScanLiner is a display that can report the scanline it is currently
+// refreshing, like the st7789, ili9341 and gc9a01.
+type ScanLiner interface {
+	GetScanLine() uint16
+}
+
+// ScanLine tracks the panel refresh by reading the scanline register of the
+// display. Every read is a small SPI transaction.
+type ScanLine struct {
+	display ScanLiner
+	first   uint16
+	last    uint16
+	lines   int
+}
+
+// NewScanLine returns a source that reads the scanline register of the
+// display. The first and last parameters are the scanline values of the first
+// and last visible line, and lines is the height of the display.
+//
+// For the st7789, use GetLowestScanLine and GetHighestScanLine. For the ili9341
+// and gc9a01, the scanline is the line number itself so first is 0 and last is
+// the height of the panel minus one.
+func NewScanLine(display ScanLiner, first, last uint16, lines int) *ScanLine {
+	return &ScanLine{
+		display: display,
+		first:   first,
+		last:    last,
+		lines:   lines,
+	}
+}
+
+// Line implements Source.
+func (s *ScanLine) Line() int {
+	scanline := s.display.GetScanLine()
+	if scanline < s.first || scanline > s.last {
+		return -1
+	}
+	return int(scanline-s.first) * s.lines / int(s.last-s.first+1)
+}
+
+// TE tracks the panel refresh using the TE (tearing effect) pin of the display.
+// The TE pin goes high at the start of each vertical blanking period, and the
+// position within the frame is estimated from the time since the last pulse.
+//
+// The TE output must be enabled on the display, for example using the
+// EnableTEOutput method of the st7789, ili9341 or gc9a01.
+type TE struct {
+	lines  int
+	start  atomic.Int64 // time of the last pulse, in nanoseconds
+	period atomic.Int64 // time between the last two pulses, in nanoseconds
+}
+
+// NewTE returns a new TE source for a display with the given number of lines.
+func NewTE(lines int) *TE {
+	return &TE{lines: lines}
+}
+
+// Interrupt records a pulse of the TE pin. It must be called from the rising
+// edge interrupt of the pin:
+//
+//	tePin.Configure(machine.PinConfig{Mode: machine.PinInput})
+//	tePin.SetInterrupt(machine.PinRising, func(machine.Pin) {
+//		te.Interrupt()
+//	})
+func (t *TE) Interrupt() {
+	now := time.Now().UnixNano()
+	if start := t.start.Load(); start != 0 {
+		t.period.Store(now - start)
+	}
+	t.start.Store(now)
+}
+
+// Line implements Source.
+func (t *TE) Line() int {
+	period := t.period.Load()
+	if period <= 0 {
+		// Not enough pulses seen yet.
+		return -1
+	}
+	elapsed := time.Now().UnixNano() - t.start.Load()
+	if elapsed >= period {
+		// A pulse was missed, or the TE output was disabled.
+		return -1
+	}
+	return int(elapsed * int64(t.lines) / period)
+}
@@ -0,0 +1,142 @@
+// Package vsync paces display updates to the refresh of the display panel, to
+// avoid tearing.
+//
+// TFT displays like the st7789, ili9341 and gc9a01 continuously refresh the
+// panel from their internal memory, line by line. When the memory is written
+// at the same time and the write pointer passes the refresh pointer, a part of
+// the old frame and a part of the new frame will be visible at the same time:
+// this is called tearing. A Pacer avoids this by sending a frame in two halves:
+// the top half while the panel refreshes the bottom half, and the bottom half
+// once the panel has moved on to the top half of the next frame.
+//
+// The position of the refresh can be obtained in two ways: by reading the
+// current scanline from the display (see ScanLine) or by timing the pulses on
+// the TE (tearing effect) pin of the display (see TE). The TE pin is more
+// accurate and doesn't use the SPI bus, but it needs an extra wire.
+//
+// For this to work, sending half a frame must take less time than refreshing
+// half a frame. Use a fast SPI clock and if needed lower the frame rate of the
+// display (for example using the FrameRate setting of the st7789).
+package vsync // import "tinygo.org/x/drivers/vsync"
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers/pixel"
+)
+
+// ErrNoSync is returned when the source doesn't report the panel refresh in
+// time, for example because the TE pin isn't connected or the display doesn't
+// support reading the scanline.
+var ErrNoSync = errors.New("vsync: panel refresh not detected")
+
+// Default time to wait for the panel refresh: two frames at 30Hz, which is
+// about the lowest frame rate of TFT displays.
+const defaultTimeout = 67 * time.Millisecond
+
+// Displayer is a display that can draw image buffers.
+type Displayer[T pixel.Color] interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+
+	// DrawBitmap copies the image to the display at the given coordinates.
+	DrawBitmap(x, y int16, bitmap pixel.Image[T]) error
+}
+
+// Source reports where the panel currently is in its refresh cycle.
+type Source interface {
+	// Line returns the line of the panel that is currently being refreshed,
+	// scaled to the number of lines of the display, or -1 during the vertical
+	// blanking period (or when the position is not known).
+	Line() int
+}
+
+// Pacer sends frames to a display in sync with the panel refresh.
+type Pacer[T pixel.Color] struct {
+	display Displayer[T]
+	source  Source
+	timeout time.Duration
+}
+
+// New returns a new Pacer for the given display. The source is used to track
+// the panel refresh.
+func New[T pixel.Color](display Displayer[T], source Source) *Pacer[T] {
+	return &Pacer[T]{
+		display: display,
+		source:  source,
+		timeout: defaultTimeout,
+	}
+}
+
+// SetTimeout sets how long Wait and Present wait for the panel to reach a line
+// before they give up and return ErrNoSync. The default is 67ms, which is two
+// frames at 30Hz.
+func (p *Pacer[T]) SetTimeout(timeout time.Duration) {
+	p.timeout = timeout
+}
+
+// Wait blocks until the panel starts refreshing a new frame. It can be used to
+// pace animations to the refresh rate of the display. It returns ErrNoSync
+// when the source doesn't report a new frame in time.
+func (p *Pacer[T]) Wait() error {
+	deadline := time.Now().Add(p.timeout)
+
+	// Wait until the refresh is somewhere in the frame, and then until it has
+	// wrapped around to the start of the next frame.
+	prev := p.source.Line()
+	for prev < 0 {
+		if time.Now().After(deadline) {
+			return ErrNoSync
+		}
+		prev = p.source.Line()
+	}
+	for {
+		line := p.source.Line()
+		if line < prev {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrNoSync
+		}
+		prev = line
+	}
+}
+
+// waitForLine blocks until the panel is refreshing the given line or a line
+// below it.
+func (p *Pacer[T]) waitForLine(line int) error {
+	deadline := time.Now().Add(p.timeout)
+	for p.source.Line() < line {
+		if time.Now().After(deadline) {
+			return ErrNoSync
+		}
+	}
+	return nil
+}
+
+// Present sends a full frame to the display without tearing. It assumes the
+// panel is refreshed from the top of the image to the bottom, which is usually
+// the case in the default (non-rotated) orientation.
+//
+// The frame is sent in two halves, which means that Present waits for up to
+// one and a half refresh cycles. When the panel refresh isn't detected, it
+// returns ErrNoSync without sending the rest of the frame.
+func (p *Pacer[T]) Present(frame pixel.Image[T]) error {
+	_, height := frame.Size()
+	half := height / 2
+
+	// Send the top half while the panel refreshes the bottom half.
+	if err := p.waitForLine(half); err != nil {
+		return err
+	}
+	if err := p.display.DrawBitmap(0, 0, frame.LimitHeight(half)); err != nil {
+		return err
+	}
+
+	// Send the bottom half once the panel has moved on to the next frame.
+	if err := p.Wait(); err != nil {
+		return err
+	}
+	return p.display.DrawBitmap(0, int16(half), frame.Rows(half, height))
+}
@@ -0,0 +1,113 @@
+package vsync_test
+
+import (
+	"testing"
+	"time"
+
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/vsync"
+)
+
+// fakePanel simulates a panel of 8 lines plus 2 lines of vertical blanking,
+// where every call to Line advances the refresh by one line.
+type fakePanel struct {
+	pos   int
+	lines []int // line that was being refreshed during each draw
+	rows  []int // first row of each draw
+}
+
+func (p *fakePanel) Line() int {
+	p.pos = (p.pos + 1) % 10
+	if p.pos >= 8 {
+		return -1
+	}
+	return p.pos
+}
+
+func (p *fakePanel) Size() (int16, int16) {
+	return 4, 8
+}
+
+func (p *fakePanel) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB565BE]) error {
+	p.lines = append(p.lines, p.pos)
+	p.rows = append(p.rows, int(y))
+	return nil
+}
+
+func (p *fakePanel) GetScanLine() uint16 {
+	return uint16(p.pos)
+}
+
+func TestPresent(t *testing.T) {
+	panel := &fakePanel{pos: 5}
+	pacer := vsync.New[pixel.RGB565BE](panel, panel)
+	err := pacer.Present(pixel.NewImage[pixel.RGB565BE](4, 8))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if len(panel.rows) != 2 || panel.rows[0] != 0 || panel.rows[1] != 4 {
+		t.Fatalf("expected top and bottom half to be drawn, got rows %v", panel.rows)
+	}
+	if panel.lines[0] < 4 {
+		t.Errorf("top half drawn while the panel was refreshing line %d", panel.lines[0])
+	}
+	if panel.lines[1] < 8 {
+		t.Errorf("bottom half drawn while the panel was refreshing line %d", panel.lines[1])
+	}
+}
+
+// stuckSource always reports the same line.
+type stuckSource int
+
+func (s stuckSource) Line() int {
+	return int(s)
+}
+
+func TestNoSync(t *testing.T) {
+	for _, line := range []int{-1, 0, 7} {
+		panel := &fakePanel{}
+		pacer := vsync.New[pixel.RGB565BE](panel, stuckSource(line))
+		pacer.SetTimeout(5 * time.Millisecond)
+		if err := pacer.Wait(); err != vsync.ErrNoSync {
+			t.Errorf("line %d: expected Wait to return %v, got %v", line, vsync.ErrNoSync, err)
+		}
+		err := pacer.Present(pixel.NewImage[pixel.RGB565BE](4, 8))
+		if err != vsync.ErrNoSync {
+			t.Errorf("line %d: expected Present to return %v, got %v", line, vsync.ErrNoSync, err)
+		}
+		if len(panel.rows) > 1 {
+			t.Errorf("line %d: expected at most the top half to be drawn, got rows %v", line, panel.rows)
+		}
+	}
+}
+
+func TestScanLine(t *testing.T) {
+	panel := &fakePanel{}
+	source := vsync.NewScanLine(panel, 2, 5, 8)
+	for _, tc := range []struct {
+		scanline int
+		line     int
+	}{
+		{0, -1},
+		{2, 0},
+		{3, 2},
+		{5, 6},
+		{6, -1},
+	} {
+		panel.pos = tc.scanline
+		if line := source.Line(); line != tc.line {
+			t.Errorf("scanline %d: expected line %d, got %d", tc.scanline, tc.line, line)
+		}
+	}
+}
+
+func TestTE(t *testing.T) {
+	te := vsync.NewTE(240)
+	if line := te.Line(); line != -1 {
+		t.Errorf("expected unknown position before any pulses, got %d", line)
+	}
+	te.Interrupt()
+	if line := te.Line(); line != -1 {
+		t.Errorf("expected unknown position after a single pulse, got %d", line)
+	}
+}
@@ -12,13 +12,15 @@ import (
 	"image/color"
 	"machine"
 	"time"
+
+	"tinygo.org/x/drivers"
 )
 
 type Config struct {
 	Width        int16
 	Height       int16
 	LogicalWidth int16
-	Rotation     Rotation
+	Rotation     drivers.Rotation
 }
 
 type Device struct {
@@ -29,10 +31,13 @@ type Device struct {
 	busy machine.Pin
 
 	buffer   []uint8
-	rotation Rotation
+	rotation drivers.Rotation
+	config   Config
+	hdir     bool // initialized with HDirInit
 }
 
-type Rotation uint8
+// Deprecated: use drivers.Rotation instead.
+type Rotation = drivers.Rotation
 
 var fullRefresh = [159]uint8{
 	0x80, 0x48, 0x40, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
@@ -91,6 +96,9 @@ func New(bus *machine.SPI, csPin, dcPin, rstPin, busyPin machine.Pin) Device {
 }
 
 func (d *Device) LDirInit(cfg Config) {
+	d.rotation = cfg.Rotation % 8
+	d.config = cfg
+	d.hdir = false
 	d.cs.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	d.rst.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	d.dc.Configure(machine.PinConfig{Mode: machine.PinOutput})
@@ -150,6 +158,9 @@ func (d *Device) LDirInit(cfg Config) {
 }
 
 func (d *Device) HDirInit(cfg Config) {
+	d.rotation = cfg.Rotation % 8
+	d.config = cfg
+	d.hdir = true
 	d.cs.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	d.rst.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	d.dc.Configure(machine.PinConfig{Mode: machine.PinOutput})
@@ -389,36 +400,55 @@ func (d *Device) ClearBuffer() {
 
 // Size returns the current size of the display.
 func (d *Device) Size() (w, h int16) {
-	if d.rotation == ROTATION_90 || d.rotation == ROTATION_270 {
-		return Height, Width
-	}
-	return Width, Height
+	return d.rotation.Size(Width, Height)
 }
 
-// SetRotation changes the rotation (clock-wise) of the device
-func (d *Device) SetRotation(rotation Rotation) {
-	d.rotation = rotation
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation (clock-wise) of the buffer used by SetPixel.
+// DisplayImage sends its image without rotation.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 // xy chages the coordinates according to the rotation
 func (d *Device) xy(x, y int16) (int16, int16) {
-	switch d.rotation {
-	case NO_ROTATION:
-		return x, y
-	case ROTATION_90:
-		return Width - y - 1, x
-	case ROTATION_180:
-		return Width - x - 1, Height - y - 1
-	case ROTATION_270:
-		return y, Height - x - 1
-	}
-	return x, y
+	return d.rotation.Transform(x, y, Width, Height)
 }
 
-func (d *Device) Sleep() {
+// DeepSleep puts the display into deep sleep. Only a reset can wake it up
+// again, see Sleep.
+//
+// DeepSleep is what Sleep() did before Sleep took a parameter to satisfy
+// drivers.Sleeper. Calls to Sleep() must be changed to DeepSleep() or
+// Sleep(true).
+func (d *Device) DeepSleep() {
 	d.SendCommand(0x10)
 	d.SendData(0x01)
 	time.Sleep(200 * time.Millisecond)
 
 	d.rst.Low()
 }
+
+// Sleep puts the display into deep sleep, or wakes it up again. The display
+// will still show its contents while sleeping. Waking up resets and
+// reinitializes the display in the same way as the last LDirInit or HDirInit
+// call, keeping the current rotation.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.DeepSleep()
+		return nil
+	}
+	cfg := d.config
+	cfg.Rotation = d.rotation
+	if d.hdir {
+		d.HDirInit(cfg)
+	} else {
+		d.LDirInit(cfg)
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package epd1in54
 
+import "tinygo.org/x/drivers"
+
 // Derived from https://github.com/waveshare/e-Paper/blob/master/Arduino/epd4in2/epd4in2.h
 
 const (
@@ -45,8 +47,8 @@ const (
 	READ_OTP                       = 0xA2
 	POWER_SAVING                   = 0xE3
 
-	NO_ROTATION  Rotation = 0
-	ROTATION_90  Rotation = 1
-	ROTATION_180 Rotation = 2
-	ROTATION_270 Rotation = 3
+	NO_ROTATION  = drivers.Rotation0
+	ROTATION_90  = drivers.Rotation90
+	ROTATION_180 = drivers.Rotation180
+	ROTATION_270 = drivers.Rotation270
 )
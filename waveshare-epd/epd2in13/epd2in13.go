@@ -84,13 +84,18 @@ func (d *Device) Configure(cfg Config) {
 	} else {
 		d.height = 250
 	}
-	d.rotation = cfg.Rotation
+	d.rotation = cfg.Rotation % 8
 	d.bufferLength = (uint32(d.logicalWidth) * uint32(d.height)) / 8
 	d.buffer = make([]uint8, d.bufferLength)
 	for i := uint32(0); i < d.bufferLength; i++ {
 		d.buffer[i] = 0xFF
 	}
 
+	d.init()
+}
+
+// init resets the display and sends the initialization sequence.
+func (d *Device) init() {
 	d.cs.Low()
 	d.dc.Low()
 	d.rst.Low()
@@ -132,12 +137,13 @@ func (d *Device) DeepSleep() {
 }
 
 // Set the sleep mode of the panel. The display will still show its contents,
-// but will go into a lower-power state.
+// but will go into a lower-power state. Waking up resets and reinitializes the
+// display, after which the next update should be a full update.
 func (d *Device) Sleep(sleepEnabled bool) error {
 	if sleepEnabled {
 		d.DeepSleep()
 	} else {
-		d.Reset()
+		d.init()
 	}
 	return nil
 }
@@ -178,6 +184,12 @@ func (d *Device) SetLUT(fullUpdate bool) {
 	}
 }
 
+// SetFullRefresh selects the look up table for the next updates. This is the
+// same as SetLUT.
+func (d *Device) SetFullRefresh(full bool) {
+	d.SetLUT(full)
+}
+
 // SetPixel modifies the internal buffer in a single pixel.
 // The display have 2 colors: black and white. We use a very simple cutoff to
 // determine whether a pixel is black or white (darker colors are black, lighter
@@ -218,7 +230,7 @@ func (d *Device) Display() error {
 }
 
 // DisplayRect sends only an area of the buffer to the screen.
-// The rectangle points need to be a multiple of 8 in the screen.
+// The area is rounded out to multiples of 8 in the screen x direction.
 // They might not work as expected if the screen is rotated.
 func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error {
 	x, y = d.xy(x, y)
@@ -235,9 +247,9 @@ func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error
 		width, height = height, width
 		y -= height
 	}
+	// Round the area out to whole bytes.
+	width = (x + width + 7) &^ 0x07 // reuse variables
 	x &= 0xF8
-	width &= 0xF8
-	width = x + width // reuse variables
 	if width >= d.logicalWidth {
 		width = d.logicalWidth
 	}
@@ -245,14 +257,18 @@ func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error
 	if height > d.height {
 		height = d.height
 	}
-	d.setMemoryArea(x, y, width, height)
+	if width <= x || height <= y {
+		return nil
+	}
+	d.setMemoryArea(x, y, width-1, height-1)
 	x = x / 8
 	width = width / 8
 	for ; y < height; y++ {
 		d.setMemoryPointer(8*x, y)
 		d.SendCommand(WRITE_RAM)
-		for i := int16(x); i < width; i++ {
-			d.SendData(d.buffer[i+y*d.logicalWidth/8])
+		row := int(y) * int(d.logicalWidth/8)
+		for i := int(x); i < int(width); i++ {
+			d.SendData(d.buffer[row+i])
 		}
 	}
 
@@ -317,10 +333,7 @@ func (d *Device) ClearBuffer() {
 
 // Size returns the current size of the display.
 func (d *Device) Size() (w, h int16) {
-	if d.rotation == drivers.Rotation90 || d.rotation == drivers.Rotation270 {
-		return d.height, d.logicalWidth
-	}
-	return d.logicalWidth, d.height
+	return d.rotation.Size(d.logicalWidth, d.height)
 }
 
 // Rotation returns the current rotation of the device.
@@ -330,21 +343,11 @@ func (d *Device) Rotation() drivers.Rotation {
 
 // SetRotation changes the rotation of the device.
 func (d *Device) SetRotation(rotation drivers.Rotation) error {
-	d.rotation = rotation
+	d.rotation = rotation % 8
 	return nil
 }
 
 // xy chages the coordinates according to the rotation
 func (d *Device) xy(x, y int16) (int16, int16) {
-	switch d.rotation {
-	case drivers.Rotation0:
-		return x, y
-	case drivers.Rotation90:
-		return d.width - y - 1, x
-	case drivers.Rotation180:
-		return d.width - x - 1, d.height - y - 1
-	case drivers.Rotation270:
-		return y, d.height - x - 1
-	}
-	return x, y
+	return d.rotation.Transform(x, y, d.width, d.height)
 }
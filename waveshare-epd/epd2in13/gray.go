@@ -0,0 +1,97 @@
+package epd2in13
+
+import (
+	"errors"
+
+	"tinygo.org/x/drivers/internal/epdgray"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// Duration of each of the grayscale passes, in frames. The pixels that must
+// become darker than the gray level of a pass are driven towards black for this
+// long. These may need some tuning for a particular panel.
+var grayPassDurations = [3]uint8{0x04, 0x04, 0x08}
+
+// DisplayGray shows a 4-level grayscale image on the whole screen. The image
+// must have the same size as the display (see Size) and is drawn using the
+// current rotation. It is sent to the display directly, the internal buffer is
+// left untouched.
+//
+// This controller doesn't support grayscale directly, so the image is built up
+// in passes: the screen is first cleared with a full update, after which each
+// pass darkens all pixels that are darker than the next gray level. This takes
+// a few seconds and blocks until the image is shown. Afterwards the look up
+// table for full updates is selected again.
+func (d *Device) DisplayGray(img pixel.Image[pixel.Gray2]) error {
+	width, height := img.Size()
+	if w, h := d.Size(); width != int(w) || height != int(h) {
+		return errors.New("image size does not match the display")
+	}
+
+	// Start from a white screen, in both memory areas of the display (they
+	// are swapped on every update).
+	d.SetLUT(true)
+	d.writeGrayPlane(img, 3)
+	d.activate()
+	var lut [30]uint8
+	d.setGrayLUT(&lut)
+	d.writeGrayPlane(img, 3)
+	d.activate()
+
+	// Darken the pixels in three passes. Only pixels that are black in both the
+	// previous and the current pass are driven (black to black), except in the
+	// first pass where the pixels start out white (white to black).
+	for pass, duration := range grayPassDurations {
+		lut = [30]uint8{}
+		if pass == 0 {
+			lut[0] = 0b00_00_10_00
+		} else {
+			lut[0] = 0b10_00_00_00
+		}
+		lut[20] = duration
+		d.setGrayLUT(&lut)
+		d.writeGrayPlane(img, pixel.Gray2(2-pass))
+		d.activate()
+	}
+
+	d.SetLUT(true)
+	return nil
+}
+
+// writeGrayPlane writes a black and white image to the display memory, with all
+// pixels that have a gray level of at most the given level in black. Level 3
+// writes a white image.
+func (d *Device) writeGrayPlane(img pixel.Image[pixel.Gray2], level pixel.Gray2) {
+	// A set bit is white, which are the levels above the given level.
+	white := uint8(0b1111)
+	if level < 3 {
+		white = 0b1111 &^ (1<<(level+1) - 1)
+	}
+	row := make([]uint8, d.logicalWidth/8)
+	d.setMemoryArea(0, 0, d.logicalWidth-1, d.height-1)
+	for y := int16(0); y < d.height; y++ {
+		d.setMemoryPointer(0, y)
+		d.SendCommand(WRITE_RAM)
+		epdgray.PackRow(row, img, d.rotation, d.width, d.height, y, white)
+		for _, b := range row {
+			d.SendData(b)
+		}
+	}
+}
+
+// activate updates the display and waits until it's done.
+func (d *Device) activate() {
+	d.SendCommand(DISPLAY_UPDATE_CONTROL_2)
+	d.SendData(0xC4)
+	d.SendCommand(MASTER_ACTIVATION)
+	d.SendCommand(TERMINATE_FRAME_READ_WRITE)
+	d.WaitUntilIdle()
+}
+
+// setGrayLUT sends a custom look up table.
+func (d *Device) setGrayLUT(lut *[30]uint8) {
+	d.SendCommand(WRITE_LUT_REGISTER)
+	for _, b := range lut {
+		d.SendData(b)
+	}
+}
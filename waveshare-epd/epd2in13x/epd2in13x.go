@@ -16,6 +16,7 @@ type Config struct {
 	Width     int16
 	Height    int16
 	NumColors uint8
+	Rotation  drivers.Rotation // Rotation is clock-wise
 }
 
 type Device struct {
@@ -28,6 +29,7 @@ type Device struct {
 	height       int16
 	buffer       [][]uint8
 	bufferLength uint32
+	rotation     drivers.Rotation
 }
 
 type Color uint8
@@ -64,6 +66,7 @@ func (d *Device) Configure(cfg Config) {
 	} else if cfg.NumColors == 1 {
 		cfg.NumColors = 2
 	}
+	d.rotation = cfg.Rotation % 8
 	d.bufferLength = (uint32(d.width) * uint32(d.height)) / 8
 	d.buffer = make([][]uint8, cfg.NumColors-1)
 	for i := range d.buffer {
@@ -79,6 +82,11 @@ func (d *Device) Configure(cfg Config) {
 	d.dc.Low()
 	d.rst.Low()
 
+	d.init()
+}
+
+// init resets the display and sends the initialization sequence.
+func (d *Device) init() {
 	d.Reset()
 
 	d.SendCommand(BOOSTER_SOFT_START)
@@ -113,6 +121,18 @@ func (d *Device) DeepSleep() {
 	d.SendData(0xA5)
 }
 
+// Sleep puts the display into deep sleep, or wakes it up again. The display
+// will still show its contents while sleeping. Waking up resets and
+// reinitializes the display.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.DeepSleep()
+	} else {
+		d.init()
+	}
+	return nil
+}
+
 // SendCommand sends a command to the display
 func (d *Device) SendCommand(command uint8) {
 	d.sendDataCommand(true, command)
@@ -141,9 +161,6 @@ func (d *Device) sendDataCommand(isCommand bool, data uint8) {
 // RGBA(1-255,0,0,255) as colored (red or yellow)
 // Anything else as black
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
-		return
-	}
 	if c.R != 0 && c.G == 0 && c.B == 0 { // COLORED
 		d.SetEPDPixel(x, y, COLORED)
 	} else if c.G != 0 || c.B != 0 { // BLACK
@@ -155,9 +172,11 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // SetEPDPixel modifies the internal buffer in a single pixel.
 func (d *Device) SetEPDPixel(x int16, y int16, c Color) {
-	if x < 0 || x >= d.width || y < 0 || y >= d.height {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
 		return
 	}
+	x, y = d.rotation.Transform(x, y, d.width, d.height)
 	byteIndex := (x + y*d.width) / 8
 	if c == WHITE {
 		d.buffer[BLACK-1][byteIndex] |= 0x80 >> uint8(x%8)
@@ -296,7 +315,20 @@ func (d *Device) ClearBuffer() {
 	}
 }
 
-// Size returns the current size of the display.
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (w, h int16) {
-	return d.width, d.height
+	return d.rotation.Size(d.width, d.height)
+}
+
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation (clock-wise) of the device. It is only used
+// by SetPixel; SetDisplayRect and SetDisplayRectColor write to the device SRAM
+// without rotation.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
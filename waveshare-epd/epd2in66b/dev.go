@@ -34,6 +34,7 @@ type Device struct {
 
 	blackBuffer []byte
 	redBuffer   []byte
+	rotation    drivers.Rotation
 }
 
 // New allocates a new device.
@@ -65,8 +66,22 @@ func (d *Device) Configure(c Config) error {
 	return nil
 }
 
+// Size returns the current size of the display, taking rotation into account.
 func (d *Device) Size() (x, y int16) {
-	return displayWidth, displayHeight
+	return d.rotation.Size(displayWidth, displayHeight)
+}
+
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation (clock-wise) of the device. SetPixel writes
+// to the black and red buffers with the new rotation, without moving their
+// current contents.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 // SetPixel modifies the internal buffer in a single pixel.
@@ -76,9 +91,11 @@ func (d *Device) Size() (x, y int16) {
 // - red = RGBA(1-255,0,0,1-255)
 // - Anything else as black
 func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
-	if x < 0 || x >= displayWidth || y < 0 || y >= displayHeight {
+	w, h := d.Size()
+	if x < 0 || x >= w || y < 0 || y >= h {
 		return
 	}
+	x, y = d.rotation.Transform(x, y, displayWidth, displayHeight)
 
 	bytePos, bitPos := pos(x, y, displayWidth)
 
@@ -141,6 +158,16 @@ func (d *Device) ClearBuffer() {
 	fill(d.blackBuffer, 0xff)
 }
 
+// Sleep puts the display into deep sleep, or wakes it up again. The display
+// will still show its contents while sleeping. Waking up resets the display.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		// deep sleep mode 1, RAM contents are kept
+		return d.sendCommandSequence([]byte{0x10, 0x01})
+	}
+	return d.Reset()
+}
+
 func (d *Device) turnOnDisplay() error {
 	// also documented as 'Master Activation'
 	if err := d.sendCommandByte(0x20); err != nil {
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"tinygo.org/x/drivers"
+	"tinygo.org/x/drivers/tester"
 	"tinygo.org/x/tinyfont"
 	"tinygo.org/x/tinyfont/freemono"
 )
@@ -40,6 +41,15 @@ func TestBufferDrawing(t *testing.T) {
 	writeImage(img)
 }
 
+func TestRotation(t *testing.T) {
+	dev := New(&mockBus{})
+
+	tester.TestRotation(t, &dev, func(x, y int16) bool {
+		bytePos, bitPos := pos(x, y, displayWidth)
+		return isSet(dev.blackBuffer, bytePos, bitPos)
+	})
+}
+
 func toImage(dev *Device) *image.RGBA {
 	red := color.RGBA{0xff, 0, 0, 0xff}
 
@@ -12,6 +12,7 @@
 package epd2in9 // import "tinygo.org/x/drivers/waveshare-epd/epd2in9"
 
 import (
+	"errors"
 	"image/color"
 	"machine"
 	"time"
@@ -22,8 +23,8 @@ import (
 type Config struct {
 	Width        int16 // Width is the display resolution
 	Height       int16
-	LogicalWidth int16    // LogicalWidth must be a multiple of 8 and same size or bigger than Width
-	Rotation     Rotation // Rotation is clock-wise
+	LogicalWidth int16            // LogicalWidth must be a multiple of 8 and same size or bigger than Width
+	Rotation     drivers.Rotation // Rotation is clock-wise
 }
 
 type Device struct {
@@ -37,10 +38,11 @@ type Device struct {
 	height       int16
 	buffer       []uint8
 	bufferLength uint32
-	rotation     Rotation
+	rotation     drivers.Rotation
 }
 
-type Rotation uint8
+// Deprecated: use drivers.Rotation instead.
+type Rotation = drivers.Rotation
 
 // Look up table for full updates
 var lutFullUpdate = [30]uint8{
@@ -99,6 +101,11 @@ func (d *Device) Configure(cfg Config) {
 		d.buffer[i] = 0xFF
 	}
 
+	d.init()
+}
+
+// init resets the display and sends the initialization sequence.
+func (d *Device) init() {
 	d.cs.Low()
 	d.dc.Low()
 	d.rst.Low()
@@ -139,6 +146,18 @@ func (d *Device) DeepSleep() {
 	d.WaitUntilIdle()
 }
 
+// Set the sleep mode of the panel. The display will still show its contents,
+// but will go into a lower-power state. Waking up resets and reinitializes the
+// display, after which the next update should be a full update.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.DeepSleep()
+	} else {
+		d.init()
+	}
+	return nil
+}
+
 // SendCommand sends a command to the display
 func (d *Device) SendCommand(command uint8) {
 	d.sendDataCommand(true, command)
@@ -175,6 +194,12 @@ func (d *Device) SetLUT(fullUpdate bool) {
 	}
 }
 
+// SetFullRefresh selects the look up table for the next updates. This is the
+// same as SetLUT.
+func (d *Device) SetFullRefresh(full bool) {
+	d.SetLUT(full)
+}
+
 // SetPixel modifies the internal buffer in a single pixel.
 // The display have 2 colors: black and white
 // We use RGBA(0,0,0, 255) as white (transparent)
@@ -210,6 +235,56 @@ func (d *Device) Display() error {
 	return nil
 }
 
+// DisplayRect sends only an area of the buffer to the screen.
+// The area is rounded out to multiples of 8 in the screen x direction.
+// They might not work as expected if the screen is rotated.
+func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error {
+	x, y = d.xy(x, y)
+	if x < 0 || y < 0 || x >= d.logicalWidth || y >= d.height || width < 0 || height < 0 {
+		return errors.New("wrong rectangle")
+	}
+	if d.rotation == ROTATION_90 {
+		width, height = height, width
+		x -= width
+	} else if d.rotation == ROTATION_180 {
+		x -= width - 1
+		y -= height - 1
+	} else if d.rotation == ROTATION_270 {
+		width, height = height, width
+		y -= height
+	}
+	// Round the area out to whole bytes.
+	width = (x + width + 7) &^ 0x07 // reuse variables
+	x &= 0xF8
+	if width >= d.logicalWidth {
+		width = d.logicalWidth
+	}
+	height = y + height
+	if height > d.height {
+		height = d.height
+	}
+	if width <= x || height <= y {
+		return nil
+	}
+	d.setMemoryArea(x, y, width-1, height-1)
+	x = x / 8
+	width = width / 8
+	for ; y < height; y++ {
+		d.setMemoryPointer(8*x, y)
+		d.SendCommand(WRITE_RAM)
+		row := int(y) * int(d.logicalWidth/8)
+		for i := int(x); i < int(width); i++ {
+			d.SendData(d.buffer[row+i])
+		}
+	}
+
+	d.SendCommand(DISPLAY_UPDATE_CONTROL_2)
+	d.SendData(0xC4)
+	d.SendCommand(MASTER_ACTIVATION)
+	d.SendCommand(TERMINATE_FRAME_READ_WRITE)
+	return nil
+}
+
 // ClearDisplay erases the device SRAM
 func (d *Device) ClearDisplay() {
 	d.setMemoryArea(0, 0, d.logicalWidth-1, d.height-1)
@@ -264,28 +339,23 @@ func (d *Device) ClearBuffer() {
 
 // Size returns the current size of the display.
 func (d *Device) Size() (w, h int16) {
-	if d.rotation == ROTATION_90 || d.rotation == ROTATION_270 {
-		return d.height, d.logicalWidth
-	}
-	return d.logicalWidth, d.height
+	return d.rotation.Size(d.logicalWidth, d.height)
 }
 
-// SetRotation changes the rotation (clock-wise) of the device
-func (d *Device) SetRotation(rotation Rotation) {
-	d.rotation = rotation
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation (clock-wise) of the device. The display
+// memory always has the native orientation: SetPixel places pixels in the
+// buffer according to the rotation, and Display sends the buffer as is.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 // xy chages the coordinates according to the rotation
 func (d *Device) xy(x, y int16) (int16, int16) {
-	switch d.rotation {
-	case NO_ROTATION:
-		return x, y
-	case ROTATION_90:
-		return d.width - y - 1, x
-	case ROTATION_180:
-		return d.width - x - 1, d.height - y - 1
-	case ROTATION_270:
-		return y, d.height - x - 1
-	}
-	return x, y
+	return d.rotation.Transform(x, y, d.width, d.height)
 }
@@ -1,5 +1,7 @@
 package epd2in9
 
+import "tinygo.org/x/drivers"
+
 // Registers
 const (
 	DRIVER_OUTPUT_CONTROL                = 0x01
@@ -24,8 +26,8 @@ const (
 	SET_RAM_Y_ADDRESS_COUNTER            = 0x4F
 	TERMINATE_FRAME_READ_WRITE           = 0xFF
 
-	NO_ROTATION  Rotation = 0
-	ROTATION_90  Rotation = 1 // 90 degrees clock-wise rotation
-	ROTATION_180 Rotation = 2
-	ROTATION_270 Rotation = 3
+	NO_ROTATION  = drivers.Rotation0
+	ROTATION_90  = drivers.Rotation90 // 90 degrees clock-wise rotation
+	ROTATION_180 = drivers.Rotation180
+	ROTATION_270 = drivers.Rotation270
 )
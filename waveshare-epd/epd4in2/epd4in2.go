@@ -9,6 +9,7 @@
 package epd4in2
 
 import (
+	"errors"
 	"image/color"
 	"machine"
 	"time"
@@ -19,8 +20,8 @@ import (
 type Config struct {
 	Width        int16 // Width is the display resolution
 	Height       int16
-	LogicalWidth int16    // LogicalWidth must be a multiple of 8 and same size or bigger than Width
-	Rotation     Rotation // Rotation is clock-wise
+	LogicalWidth int16            // LogicalWidth must be a multiple of 8 and same size or bigger than Width
+	Rotation     drivers.Rotation // Rotation is clock-wise
 }
 
 type Device struct {
@@ -34,10 +35,12 @@ type Device struct {
 	height       int16
 	buffer       []uint8
 	bufferLength uint32
-	rotation     Rotation
+	rotation     drivers.Rotation
+	blocking     bool
 }
 
-type Rotation uint8
+// Deprecated: use drivers.Rotation instead.
+type Rotation = drivers.Rotation
 
 // New returns a new epd4in2 driver. Pass in a fully configured SPI bus.
 func New(bus drivers.SPI, csPin, dcPin, rstPin, busyPin machine.Pin) Device {
@@ -46,11 +49,12 @@ func New(bus drivers.SPI, csPin, dcPin, rstPin, busyPin machine.Pin) Device {
 	rstPin.Configure(machine.PinConfig{Mode: machine.PinOutput})
 	busyPin.Configure(machine.PinConfig{Mode: machine.PinInput})
 	return Device{
-		bus:  bus,
-		cs:   csPin,
-		dc:   dcPin,
-		rst:  rstPin,
-		busy: busyPin,
+		bus:      bus,
+		cs:       csPin,
+		dc:       dcPin,
+		rst:      rstPin,
+		busy:     busyPin,
+		blocking: true,
 	}
 }
 
@@ -78,6 +82,11 @@ func (d *Device) Configure(cfg Config) {
 		d.buffer[i] = 0xFF
 	}
 
+	d.init()
+}
+
+// init resets the display and sends the initialization sequence.
+func (d *Device) init() {
 	d.cs.Low()
 	d.dc.Low()
 	d.rst.Low()
@@ -132,6 +141,31 @@ func (d *Device) DeepSleep() {
 	d.SendData(0xA5)
 }
 
+// Set the sleep mode of the panel. The display will still show its contents,
+// but will go into a lower-power state. Waking up resets and reinitializes the
+// display.
+func (d *Device) Sleep(sleepEnabled bool) error {
+	if sleepEnabled {
+		d.DeepSleep()
+	} else {
+		d.init()
+	}
+	return nil
+}
+
+// SetBlocking changes whether Display, DisplayRect and ClearDisplay wait for
+// the refresh to finish, which is the default. When not blocking, use IsBusy
+// or WaitUntilIdle to check whether the display is ready for the next update.
+func (d *Device) SetBlocking(blocking bool) {
+	d.blocking = blocking
+}
+
+// SetFullRefresh is a no-op for this display: there is only a look up table for
+// full updates, which is also used by DisplayRect. It exists so that the
+// display can be used with the epaper package.
+func (d *Device) SetFullRefresh(full bool) {
+}
+
 // SendCommand sends a command to the display
 func (d *Device) SendCommand(command uint8) {
 	d.sendDataCommand(true, command)
@@ -248,6 +282,7 @@ func (d *Device) SetPixel(x int16, y int16, c color.RGBA) {
 
 // Display sends the buffer to the screen.
 func (d *Device) Display() error {
+	d.SendCommand(PARTIAL_OUT)
 	d.SendCommand(RESOLUTION_SETTING)
 	d.SendData(uint8(d.height >> 8))
 	d.SendData(uint8(d.logicalWidth & 0xff))
@@ -275,14 +310,86 @@ func (d *Device) Display() error {
 	d.SetLUT()
 
 	d.SendCommand(DISPLAY_REFRESH)
-	time.Sleep(100 * time.Millisecond)
-	d.WaitUntilIdle()
+	if d.blocking {
+		time.Sleep(100 * time.Millisecond)
+		d.WaitUntilIdle()
+	}
+
+	return nil
+}
+
+// DisplayRect sends only an area of the buffer to the screen, and only
+// refreshes that area.
+// The rectangle points need to be a multiple of 8 in the screen.
+// They might not work as expected if the screen is rotated.
+func (d *Device) DisplayRect(x int16, y int16, width int16, height int16) error {
+	x, y = d.xy(x, y)
+	if x < 0 || y < 0 || x >= d.logicalWidth || y >= d.height || width < 0 || height < 0 {
+		return errors.New("wrong rectangle")
+	}
+	if d.rotation == ROTATION_90 {
+		width, height = height, width
+		x -= width
+	} else if d.rotation == ROTATION_180 {
+		x -= width - 1
+		y -= height - 1
+	} else if d.rotation == ROTATION_270 {
+		width, height = height, width
+		y -= height
+	}
+	x &= 0xF8
+	width = (width + 7) &^ 0x07
+	width = x + width // reuse variables
+	if width >= d.logicalWidth {
+		width = d.logicalWidth
+	}
+	height = y + height
+	if height > d.height {
+		height = d.height
+	}
+
+	d.SendCommand(PARTIAL_IN)
+	d.SendCommand(PARTIAL_WINDOW)
+	d.SendData(uint8(x >> 8))
+	d.SendData(uint8(x & 0xF8))
+	d.SendData(uint8((width - 1) >> 8))
+	d.SendData(uint8(width-1) | 0x07)
+	d.SendData(uint8(y >> 8))
+	d.SendData(uint8(y & 0xff))
+	d.SendData(uint8((height - 1) >> 8))
+	d.SendData(uint8((height - 1) & 0xff))
+	d.SendData(0x01) // gates scan both inside and outside of the partial window
+
+	x = x / 8
+	width = width / 8
+	d.SendCommand(DATA_START_TRANSMISSION_1)
+	for j := y; j < height; j++ {
+		for i := x; i < width; i++ {
+			d.SendData(0xFF)
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+	d.SendCommand(DATA_START_TRANSMISSION_2)
+	for j := y; j < height; j++ {
+		for i := x; i < width; i++ {
+			d.SendData(d.buffer[i+j*(d.logicalWidth/8)])
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	d.SetLUT()
 
+	d.SendCommand(DISPLAY_REFRESH)
+	if d.blocking {
+		time.Sleep(100 * time.Millisecond)
+		d.WaitUntilIdle()
+	}
 	return nil
 }
 
 // ClearDisplay erases the device SRAM
 func (d *Device) ClearDisplay() {
+	d.SendCommand(PARTIAL_OUT)
 	d.SendCommand(RESOLUTION_SETTING)
 	d.SendData(uint8(d.height >> 8))
 	d.SendData(uint8(d.logicalWidth & 0xff))
@@ -305,8 +412,10 @@ func (d *Device) ClearDisplay() {
 
 	d.SetLUT()
 	d.SendCommand(DISPLAY_REFRESH)
-	time.Sleep(100 * time.Millisecond)
-	d.WaitUntilIdle()
+	if d.blocking {
+		time.Sleep(100 * time.Millisecond)
+		d.WaitUntilIdle()
+	}
 }
 
 // WaitUntilIdle waits until the display is ready
@@ -330,28 +439,24 @@ func (d *Device) ClearBuffer() {
 
 // Size returns the current size of the display.
 func (d *Device) Size() (w, h int16) {
-	if d.rotation == ROTATION_90 || d.rotation == ROTATION_270 {
-		return d.height, d.logicalWidth
-	}
-	return d.logicalWidth, d.height
+	return d.rotation.Size(d.logicalWidth, d.height)
 }
 
-// SetRotation changes the rotation (clock-wise) of the device
-func (d *Device) SetRotation(rotation Rotation) {
-	d.rotation = rotation
+// Rotation returns the current rotation of the device.
+func (d *Device) Rotation() drivers.Rotation {
+	return d.rotation
+}
+
+// SetRotation changes the rotation (clock-wise) of the device. Pixels already in
+// the buffer stay where they are, so the buffer should be cleared before
+// drawing a new screen. DisplayRect only supports the 90 and 180 degree
+// rotations.
+func (d *Device) SetRotation(rotation drivers.Rotation) error {
+	d.rotation = rotation % 8
+	return nil
 }
 
 // xy chages the coordinates according to the rotation
 func (d *Device) xy(x, y int16) (int16, int16) {
-	switch d.rotation {
-	case NO_ROTATION:
-		return x, y
-	case ROTATION_90:
-		return d.width - y - 1, x
-	case ROTATION_180:
-		return d.width - x - 1, d.height - y - 1
-	case ROTATION_270:
-		return y, d.height - x - 1
-	}
-	return x, y
+	return d.rotation.Transform(x, y, d.width, d.height)
 }
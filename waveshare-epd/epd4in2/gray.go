@@ -0,0 +1,90 @@
+package epd4in2
+
+import (
+	"errors"
+	"time"
+
+	"tinygo.org/x/drivers/internal/epdgray"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// DisplayGray shows a 4-level grayscale image on the whole screen. The image
+// must have the same size as the display (see Size) and is drawn using the
+// current rotation. It is sent to the display directly, the internal buffer is
+// left untouched.
+//
+// Grayscale works by sending the two bits of each pixel as the "old" and "new"
+// image, and using a different waveform for each of the four combinations. The
+// next call to Display or DisplayRect switches back to black and white.
+func (d *Device) DisplayGray(img pixel.Image[pixel.Gray2]) error {
+	width, height := img.Size()
+	if w, h := d.Size(); width != int(w) || height != int(h) {
+		return errors.New("image size does not match the display")
+	}
+
+	d.SendCommand(PARTIAL_OUT)
+	d.SendCommand(RESOLUTION_SETTING)
+	d.SendData(uint8(d.height >> 8))
+	d.SendData(uint8(d.logicalWidth & 0xff))
+	d.SendData(uint8(d.height >> 8))
+	d.SendData(uint8(d.height & 0xff))
+
+	d.SendCommand(VCM_DC_SETTING)
+	d.SendData(0x12)
+
+	d.SendCommand(VCOM_AND_DATA_INTERVAL_SETTING)
+	d.SendData(0x97) // white border
+
+	// The most significant bit of each pixel is sent as the old image, the
+	// least significant bit as the new image (a set bit is white).
+	row := make([]uint8, d.logicalWidth/8)
+	for plane := 1; plane >= 0; plane-- {
+		levels := uint8(0b1100)
+		if plane == 1 {
+			d.SendCommand(DATA_START_TRANSMISSION_1)
+		} else {
+			d.SendCommand(DATA_START_TRANSMISSION_2)
+			levels = 0b1010
+		}
+		for y := int16(0); y < d.height; y++ {
+			epdgray.PackRow(row, img, d.rotation, d.width, d.height, y, levels)
+			for _, b := range row {
+				d.SendData(b)
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	d.setGrayLUT()
+
+	d.SendCommand(DISPLAY_REFRESH)
+	if d.blocking {
+		time.Sleep(100 * time.Millisecond)
+		d.WaitUntilIdle()
+	}
+	return nil
+}
+
+// setGrayLUT loads the look up tables for 4-level grayscale.
+func (d *Device) setGrayLUT() {
+	d.SendCommand(LUT_FOR_VCOM)
+	for _, b := range epdgray.LUTVCOM {
+		d.SendData(b)
+	}
+	d.SendCommand(LUT_WHITE_TO_WHITE)
+	for _, b := range epdgray.LUTWW {
+		d.SendData(b)
+	}
+	d.SendCommand(LUT_BLACK_TO_WHITE)
+	for _, b := range epdgray.LUTBW {
+		d.SendData(b)
+	}
+	d.SendCommand(LUT_WHITE_TO_BLACK)
+	for _, b := range epdgray.LUTWB {
+		d.SendData(b)
+	}
+	d.SendCommand(LUT_BLACK_TO_BLACK)
+	for _, b := range epdgray.LUTBB {
+		d.SendData(b)
+	}
+}
@@ -0,0 +1,124 @@
+package widget
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+)
+
+// Kinds of widgets.
+const (
+	kindFill uint8 = iota
+	kindLabel
+	kindButton
+	kindToggle
+	kindSlider
+	kindProgress
+	kindList
+)
+
+// widget is everything needed to draw a widget. Two widgets with the same
+// fields look the same.
+type widget struct {
+	kind     uint8
+	rect     Rect
+	text     string
+	items    []string
+	on       bool // pressed, checked or dragged, depending on the kind
+	value    int
+	min, max int
+	offset   int16
+	color    color.RGBA
+}
+
+// hash returns a hash of everything that affects how the widget looks.
+func (w *widget) hash() uint32 {
+	h := newHasher()
+	h.add(uint32(w.kind))
+	h.addString(w.text)
+	for _, item := range w.items {
+		h.addString(item)
+	}
+	if w.on {
+		h.add(1)
+	}
+	h.add(uint32(w.value))
+	h.add(uint32(w.min))
+	h.add(uint32(w.max))
+	h.add(uint32(w.offset))
+	h.add(uint32(w.color.R)<<16 | uint32(w.color.G)<<8 | uint32(w.color.B))
+	return uint32(h)
+}
+
+// hasher computes a 32-bit FNV-1a hash.
+type hasher uint32
+
+func newHasher() hasher {
+	return 2166136261
+}
+
+// add adds the four bytes of v to the hash.
+func (h *hasher) add(v uint32) {
+	for i := 0; i < 4; i++ {
+		*h = (*h ^ hasher(v&0xff)) * 16777619
+		v >>= 8
+	}
+}
+
+// addString adds the string and its length to the hash.
+func (h *hasher) addString(s string) {
+	for i := 0; i < len(s); i++ {
+		*h = (*h ^ hasher(s[i])) * 16777619
+	}
+	h.add(uint32(len(s)))
+}
+
+// canvas is a part of a widget in the drawing buffer. All coordinates are
+// relative to the top left of the widget, and everything outside the buffer is
+// clipped.
+type canvas[T pixel.Color] struct {
+	img  pixel.Image[T]
+	x, y int // position of the buffer in the widget
+	font *font.Font
+}
+
+// fill fills a rectangle with a solid color.
+func (c canvas[T]) fill(x, y, width, height int, col color.RGBA) {
+	imgWidth, imgHeight := c.img.Size()
+	x0, y0 := max(x-c.x, 0), max(y-c.y, 0)
+	x1, y1 := min(x+width-c.x, imgWidth), min(y+height-c.y, imgHeight)
+	if x1 <= x0 || y1 <= y0 {
+		return
+	}
+	value := pixel.NewColor[T](col.R, col.G, col.B)
+	if x0 == 0 && y0 == 0 && x1 == imgWidth && y1 == imgHeight {
+		c.img.FillSolidColor(value)
+		return
+	}
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			c.img.Set(px, py, value)
+		}
+	}
+}
+
+// border draws a one pixel wide border inside the rectangle.
+func (c canvas[T]) border(x, y, width, height int, col color.RGBA) {
+	c.fill(x, y, width, 1, col)
+	c.fill(x, y+height-1, width, 1, col)
+	c.fill(x, y, 1, height, col)
+	c.fill(x+width-1, y, 1, height, col)
+}
+
+// text draws a line of text with the pen at x, y.
+func (c canvas[T]) text(x, y int, text string, col color.RGBA) {
+	font.Draw(c.img, c.font, x-c.x, y-c.y, text, col)
+}
+
+// baseline returns the baseline that vertically centers a line of text in the
+// given rows.
+func (c canvas[T]) baseline(top, height int) int {
+	textHeight := int(c.font.Ascent) - int(c.font.Descent)
+	return top + (height-textHeight)/2 + int(c.font.Ascent)
+}
@@ -0,0 +1,469 @@
+// Package widget implements a small immediate-mode widget toolkit for control
+// panels on color displays with a touch screen, such as an ili9341 or st7789
+// with an xpt2046 or ft6336 touch controller.
+//
+// Immediate mode means that the application doesn't build a tree of widget
+// objects. Instead, it calls a method for every widget on every frame, which
+// draws the widget if needed and returns whether it was used:
+//
+//	for {
+//		ui.Begin(touchscreen.ReadTouchPoint())
+//		ui.Label(widget.Rect{10, 10, 220, 20}, "Volume")
+//		ui.Slider(widget.Rect{10, 40, 220, 30}, &volume, 0, 100)
+//		if ui.Button(widget.Rect{10, 80, 100, 40}, "Mute") {
+//			volume = 0
+//		}
+//		ui.End()
+//	}
+//
+// Widgets are identified by the order in which they are called. The UI keeps a
+// hash of every widget, and only redraws the widgets that changed since the
+// previous frame. Drawing is done in bands through a single small buffer, so
+// the UI doesn't allocate memory after New.
+//
+// Touch points must be in display coordinates. A touch is pressed when its Z
+// value is above the configured threshold. A widget captures the touch when
+// it's pressed inside the widget, and keeps it until it's released: dragging
+// outside a slider still moves the slider, and a button only clicks when the
+// touch is released inside the button.
+package widget // import "tinygo.org/x/drivers/widget"
+
+import (
+	"image/color"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/touch"
+)
+
+// Displayer is a display that can draw image buffers. It is implemented by
+// most color display drivers, such as the st7789, ili9341 and gc9a01.
+type Displayer[T pixel.Color] interface {
+	// Size returns the current size of the display.
+	Size() (x, y int16)
+
+	// DrawBitmap copies the image to the display at the given coordinates.
+	DrawBitmap(x, y int16, bitmap pixel.Image[T]) error
+}
+
+// Config is the configuration of a UI.
+type Config struct {
+	// Font used for all text. Required.
+	Font *font.Font
+
+	// Colors of the UI. The defaults are white text on a black background,
+	// with dark gray widgets and a blue accent color.
+	Background color.RGBA
+	Foreground color.RGBA
+	Surface    color.RGBA
+	Accent     color.RGBA
+
+	// Space between the border of a widget and its contents, in pixels. The
+	// default is 4.
+	Padding int16
+
+	// Touches with a Z value above this threshold are pressed. The default
+	// of zero works with the touch drivers that return a Z of zero when the
+	// screen isn't touched.
+	PressureThreshold int
+
+	// Maximum number of widgets per frame. Widgets after this are drawn on
+	// every frame. The default is 32.
+	MaxWidgets int
+
+	// Number of display lines in the drawing buffer. Larger buffers need
+	// fewer transfers to the display. The default is 8.
+	BufferLines int
+}
+
+// Rect is a rectangle on the screen.
+type Rect struct {
+	X, Y, Width, Height int16
+}
+
+// Contains returns whether the point is inside the rectangle.
+func (r Rect) Contains(x, y int16) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Empty returns whether the rectangle has no area.
+func (r Rect) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// ListState is the state of a List that is kept by the application.
+type ListState struct {
+	// Index of the selected item, or -1 if no item is selected.
+	Selected int
+
+	// Scroll position in pixels.
+	Offset int16
+}
+
+// Minimum distance in pixels a touch must move before it's a drag instead of a
+// tap, in lists.
+const dragThreshold = 6
+
+// UI draws widgets and routes touches to them.
+type UI[T pixel.Color] struct {
+	display Displayer[T]
+	config  Config
+	width   int16
+	height  int16
+	buf     pixel.Image[T]
+
+	hashes []uint32 // hash of every widget drawn in the previous frame
+	rects  []Rect   // area of every widget drawn in the previous frame
+	count  int      // number of widgets in the current frame
+	prev   int      // number of widgets in the previous frame
+	clear  bool     // clear the screen at the start of the next frame
+	err    error    // first error of the current frame
+
+	// Touch state.
+	x, y       int16
+	startX     int16 // position where the touch was pressed
+	startY     int16
+	down       bool
+	pressed    bool // pressed in this frame
+	released   bool // released in this frame
+	active     int  // widget that captured the touch, or -1
+	dragging   bool // the touch moved far enough to be a drag
+	dragOffset int16
+}
+
+// New returns a new UI for the given display. The screen is cleared on the
+// first frame.
+func New[T pixel.Color](display Displayer[T], config Config) *UI[T] {
+	if config.Font == nil {
+		panic("widget: no font configured")
+	}
+	if config.Background == (color.RGBA{}) && config.Foreground == (color.RGBA{}) {
+		config.Background = color.RGBA{A: 255}
+		config.Foreground = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	if config.Surface == (color.RGBA{}) {
+		config.Surface = color.RGBA{R: 64, G: 64, B: 64, A: 255}
+	}
+	if config.Accent == (color.RGBA{}) {
+		config.Accent = color.RGBA{R: 0, G: 120, B: 215, A: 255}
+	}
+	if config.Padding == 0 {
+		config.Padding = 4
+	}
+	if config.MaxWidgets == 0 {
+		config.MaxWidgets = 32
+	}
+	if config.BufferLines == 0 {
+		config.BufferLines = 8
+	}
+	width, height := display.Size()
+	return &UI[T]{
+		display: display,
+		config:  config,
+		width:   width,
+		height:  height,
+		buf:     pixel.NewImage[T](int(width), config.BufferLines),
+		hashes:  make([]uint32, config.MaxWidgets),
+		rects:   make([]Rect, config.MaxWidgets),
+		clear:   true,
+		active:  -1,
+	}
+}
+
+// Invalidate clears the screen and redraws all widgets on the next frame, for
+// example after something else has drawn on the screen.
+func (u *UI[T]) Invalidate() {
+	u.clear = true
+}
+
+// Begin starts a new frame with the current touch point.
+func (u *UI[T]) Begin(p touch.Point) {
+	down := p.Z > u.config.PressureThreshold
+	u.pressed = down && !u.down
+	u.released = !down && u.down
+	u.down = down
+	if down {
+		// Touch controllers don't report a position on release, so keep
+		// the last one.
+		u.x, u.y = int16(p.X), int16(p.Y)
+	}
+	if u.pressed {
+		u.startX, u.startY = u.x, u.y
+		u.active = -1
+		u.dragging = false
+	}
+	if !u.down && !u.released {
+		u.active = -1
+	}
+
+	u.count = 0
+	u.err = nil
+	if u.clear {
+		u.clear = false
+		u.fill(Rect{0, 0, u.width, u.height}, u.config.Background)
+		for i := range u.hashes {
+			u.hashes[i] = 0
+			u.rects[i] = Rect{}
+		}
+		u.prev = 0
+	}
+}
+
+// End finishes the frame: the areas of widgets that were drawn in the previous
+// frame but not in this one are cleared. It returns the first error that
+// occurred while drawing the frame.
+func (u *UI[T]) End() error {
+	for i := u.count; i < u.prev && i < len(u.rects); i++ {
+		u.fill(u.rects[i], u.config.Background)
+		u.hashes[i] = 0
+		u.rects[i] = Rect{}
+	}
+	u.prev = u.count
+	return u.err
+}
+
+// Touching returns whether the screen is touched, and where.
+func (u *UI[T]) Touching() (x, y int16, down bool) {
+	return u.x, u.y, u.down
+}
+
+// Label draws a line of text, vertically centered in the rectangle.
+func (u *UI[T]) Label(r Rect, text string) {
+	u.add(&widget{kind: kindLabel, rect: r, text: text})
+}
+
+// Button draws a button with a centered label. It returns true when the button
+// is clicked: pressed and then released inside the button.
+func (u *UI[T]) Button(r Rect, text string) bool {
+	captured := u.capture(r)
+	inside := r.Contains(u.x, u.y)
+	u.add(&widget{kind: kindButton, rect: r, text: text, on: captured && u.down && inside})
+	return captured && u.released && inside
+}
+
+// Toggle draws a check box followed by a label. Clicking it flips the value of
+// on, in which case it returns true.
+func (u *UI[T]) Toggle(r Rect, text string, on *bool) bool {
+	captured := u.capture(r)
+	clicked := captured && u.released && r.Contains(u.x, u.y)
+	if clicked {
+		*on = !*on
+	}
+	u.add(&widget{kind: kindToggle, rect: r, text: text, on: *on})
+	return clicked
+}
+
+// Slider draws a horizontal slider for a value between min and max. Pressing or
+// dragging the slider changes the value, in which case it returns true.
+func (u *UI[T]) Slider(r Rect, value *int, min, max int) bool {
+	captured := u.capture(r)
+	changed := false
+	if captured && u.down && max > min {
+		knob := u.knobWidth(r)
+		track := int(r.Width - knob)
+		pos := int(u.x - r.X - knob/2)
+		pos = clamp(pos, 0, track)
+		v := min
+		if track > 0 {
+			v = min + (pos*(max-min)+track/2)/track
+		}
+		if v != *value {
+			*value = v
+			changed = true
+		}
+	}
+	*value = clamp(*value, min, max)
+	u.add(&widget{kind: kindSlider, rect: r, on: captured && u.down, value: *value, min: min, max: max})
+	return changed
+}
+
+// Progress draws a progress bar that is filled for value out of max.
+func (u *UI[T]) Progress(r Rect, value, max int) {
+	u.add(&widget{kind: kindProgress, rect: r, value: clamp(value, 0, max), max: max})
+}
+
+// List draws a scrollable list of items, one per line. Tapping an item selects
+// it, in which case List returns true. Dragging the list scrolls it.
+func (u *UI[T]) List(r Rect, items []string, state *ListState) bool {
+	captured := u.capture(r)
+	if captured && u.pressed {
+		u.dragOffset = state.Offset
+	}
+	rowHeight := u.rowHeight()
+	maxOffset := int16(max(0, len(items)*int(rowHeight)-int(r.Height)))
+
+	selected := false
+	if captured {
+		dy := u.y - u.startY
+		if !u.dragging && (dy > dragThreshold || dy < -dragThreshold) {
+			u.dragging = true
+		}
+		if u.dragging && u.down {
+			state.Offset = u.dragOffset - dy
+		}
+		if u.released && !u.dragging && r.Contains(u.x, u.y) {
+			index := int((u.startY - r.Y + state.Offset) / rowHeight)
+			if index < len(items) && index != state.Selected {
+				state.Selected = index
+				selected = true
+			}
+		}
+	}
+	state.Offset = clamp(state.Offset, 0, maxOffset)
+	u.add(&widget{kind: kindList, rect: r, items: items, value: state.Selected, offset: state.Offset})
+	return selected
+}
+
+// capture returns whether the next widget has captured the touch. A widget
+// captures the touch when it's pressed inside the widget.
+func (u *UI[T]) capture(r Rect) bool {
+	if u.pressed && u.active < 0 && r.Contains(u.startX, u.startY) {
+		u.active = u.count
+	}
+	return u.active == u.count
+}
+
+// add adds a widget to the frame, and draws it if it changed since the previous
+// frame.
+func (u *UI[T]) add(w *widget) {
+	id := u.count
+	u.count++
+	if id >= len(u.hashes) {
+		// Too many widgets to remember, so always draw them.
+		u.draw(w)
+		return
+	}
+	h := w.hash()
+	if h == u.hashes[id] && w.rect == u.rects[id] {
+		return
+	}
+	if old := u.rects[id]; old != w.rect && !old.Empty() {
+		// The widget moved or changed size, so clear its old area.
+		u.fill(old, u.config.Background)
+	}
+	u.hashes[id] = h
+	u.rects[id] = w.rect
+	u.draw(w)
+}
+
+// fill fills a rectangle with a solid color.
+func (u *UI[T]) fill(r Rect, c color.RGBA) {
+	u.draw(&widget{kind: kindFill, rect: r, color: c})
+}
+
+// draw draws the widget in bands through the drawing buffer.
+func (u *UI[T]) draw(w *widget) {
+	// Clip the widget to the screen. Widgets are drawn in their own
+	// coordinates, so remember how much was clipped at the top left.
+	r := w.rect
+	x0, y0 := max(r.X, 0), max(r.Y, 0)
+	x1, y1 := min(r.X+r.Width, u.width), min(r.Y+r.Height, u.height)
+	if x1 <= x0 || y1 <= y0 {
+		return
+	}
+	width := int(x1 - x0)
+	lines := u.buf.Len() / width
+	for y := y0; y < y1; y += int16(lines) {
+		n := min(lines, int(y1-y))
+		img := u.buf.Rescale(width, n)
+		c := canvas[T]{
+			img:  img,
+			x:    int(x0 - r.X),
+			y:    int(y - r.Y),
+			font: u.config.Font,
+		}
+		u.paint(c, w)
+		err := u.display.DrawBitmap(x0, y, img)
+		if err != nil && u.err == nil {
+			u.err = err
+		}
+	}
+}
+
+// paint draws (part of) a widget onto the canvas.
+func (u *UI[T]) paint(c canvas[T], w *widget) {
+	cfg := &u.config
+	width, height := int(w.rect.Width), int(w.rect.Height)
+	pad := int(cfg.Padding)
+	switch w.kind {
+	case kindFill:
+		c.fill(0, 0, width, height, w.color)
+
+	case kindLabel:
+		c.fill(0, 0, width, height, cfg.Background)
+		c.text(0, c.baseline(0, height), w.text, cfg.Foreground)
+
+	case kindButton:
+		bg := cfg.Surface
+		if w.on {
+			bg = cfg.Accent
+		}
+		c.fill(0, 0, width, height, bg)
+		c.border(0, 0, width, height, cfg.Foreground)
+		textWidth := font.LineWidth(cfg.Font, w.text)
+		c.text((width-textWidth)/2, c.baseline(0, height), w.text, cfg.Foreground)
+
+	case kindToggle:
+		c.fill(0, 0, width, height, cfg.Background)
+		size := height - 2*pad
+		c.fill(pad, pad, size, size, cfg.Surface)
+		c.border(pad, pad, size, size, cfg.Foreground)
+		if w.on {
+			c.fill(pad+3, pad+3, size-6, size-6, cfg.Accent)
+		}
+		c.text(height+pad, c.baseline(0, height), w.text, cfg.Foreground)
+
+	case kindSlider:
+		c.fill(0, 0, width, height, cfg.Background)
+		knob := int(u.knobWidth(w.rect))
+		track := width - knob
+		pos := 0
+		if w.max > w.min {
+			pos = track * (w.value - w.min) / (w.max - w.min)
+		}
+		c.fill(knob/2, height/2-2, pos, 4, cfg.Accent)
+		c.fill(knob/2+pos, height/2-2, track-pos, 4, cfg.Surface)
+		knobColor := cfg.Foreground
+		if w.on {
+			knobColor = cfg.Accent
+		}
+		c.fill(pos, pad, knob, height-2*pad, knobColor)
+
+	case kindProgress:
+		c.fill(0, 0, width, height, cfg.Surface)
+		if w.max > 0 {
+			c.fill(1, 1, (width-2)*w.value/w.max, height-2, cfg.Accent)
+		}
+		c.border(0, 0, width, height, cfg.Foreground)
+
+	case kindList:
+		c.fill(0, 0, width, height, cfg.Background)
+		rowHeight := int(u.rowHeight())
+		first := int(w.offset) / rowHeight
+		for i := first; i < len(w.items); i++ {
+			top := i*rowHeight - int(w.offset)
+			if top >= height {
+				break
+			}
+			if i == w.value {
+				c.fill(0, top, width, rowHeight, cfg.Accent)
+			}
+			c.text(pad, c.baseline(top, rowHeight), w.items[i], cfg.Foreground)
+		}
+		c.border(0, 0, width, height, cfg.Surface)
+	}
+}
+
+// knobWidth returns the width of the knob of a slider.
+func (u *UI[T]) knobWidth(r Rect) int16 {
+	return min(r.Height/2, r.Width)
+}
+
+// rowHeight returns the height of a list item.
+func (u *UI[T]) rowHeight() int16 {
+	return int16(u.config.Font.LineHeight()) + u.config.Padding
+}
+
+func clamp[N int | int16](v, lo, hi N) N {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,241 @@
+package widget_test
+
+import (
+	"image/color"
+	"testing"
+
+	"tinygo.org/x/drivers/font"
+	"tinygo.org/x/drivers/pixel"
+	"tinygo.org/x/drivers/touch"
+	"tinygo.org/x/drivers/widget"
+)
+
+// testFont is a tiny 1-bit font with 2x2 glyphs.
+var testFont = &font.Font{
+	BitsPerPixel: 1,
+	Ascent:       2,
+	Descent:      0,
+	YAdvance:     4,
+	Fallback:     'A',
+	Glyphs: []font.Glyph{
+		{Rune: ' ', XAdvance: 3},
+		{Rune: 'A', Width: 2, Height: 2, XAdvance: 3, YOffset: -2, Bitmaps: []byte{0b1111_0000}},
+	},
+}
+
+// fakeDisplay records the area drawn by every DrawBitmap call.
+type fakeDisplay struct {
+	memory pixel.Image[pixel.RGB888]
+	draws  []widget.Rect
+}
+
+func newFakeDisplay(width, height int) *fakeDisplay {
+	return &fakeDisplay{memory: pixel.NewImage[pixel.RGB888](width, height)}
+}
+
+func (d *fakeDisplay) Size() (int16, int16) {
+	width, height := d.memory.Size()
+	return int16(width), int16(height)
+}
+
+func (d *fakeDisplay) DrawBitmap(x, y int16, bitmap pixel.Image[pixel.RGB888]) error {
+	width, height := bitmap.Size()
+	for by := 0; by < height; by++ {
+		for bx := 0; bx < width; bx++ {
+			d.memory.Set(int(x)+bx, int(y)+by, bitmap.Get(bx, by))
+		}
+	}
+	d.draws = append(d.draws, widget.Rect{x, y, int16(width), int16(height)})
+	return nil
+}
+
+var (
+	black  = pixel.NewRGB888(0, 0, 0)
+	accent = pixel.NewRGB888(0, 120, 215)
+)
+
+func newUI() (*widget.UI[pixel.RGB888], *fakeDisplay) {
+	display := newFakeDisplay(64, 48)
+	ui := widget.New[pixel.RGB888](display, widget.Config{Font: testFont})
+	return ui, display
+}
+
+func TestRedraw(t *testing.T) {
+	ui, display := newUI()
+	label := widget.Rect{0, 0, 64, 10}
+	progress := widget.Rect{0, 20, 64, 10}
+
+	frame := func(text string, value int) {
+		ui.Begin(touch.Point{})
+		ui.Label(label, text)
+		ui.Progress(progress, value, 100)
+		if err := ui.End(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	frame("A", 50)
+	if len(display.draws) == 0 {
+		t.Fatal("nothing was drawn on the first frame")
+	}
+	if c := display.memory.Get(10, 25); c != accent {
+		t.Errorf("expected filled progress bar at 10,25, got %v", c)
+	}
+
+	// Nothing changed, so nothing is drawn.
+	display.draws = nil
+	frame("A", 50)
+	if len(display.draws) != 0 {
+		t.Errorf("expected no redraw, got %v", display.draws)
+	}
+
+	// Only the progress bar changed.
+	frame("A", 60)
+	for _, r := range display.draws {
+		if r.Y < progress.Y || r.Y+r.Height > progress.Y+progress.Height {
+			t.Errorf("unexpected redraw outside the progress bar: %v", r)
+		}
+	}
+
+	// The progress bar is no longer drawn, so its area is cleared.
+	display.draws = nil
+	ui.Begin(touch.Point{})
+	ui.Label(label, "A")
+	ui.End()
+	if len(display.draws) == 0 || display.memory.Get(10, 25) != black {
+		t.Errorf("expected the progress bar to be cleared")
+	}
+}
+
+func TestButton(t *testing.T) {
+	ui, display := newUI()
+	button := widget.Rect{10, 10, 20, 10}
+	frame := func(p touch.Point) bool {
+		ui.Begin(p)
+		clicked := ui.Button(button, "A")
+		ui.End()
+		return clicked
+	}
+
+	frame(touch.Point{})
+	if frame(touch.Point{X: 15, Y: 15, Z: 100}) {
+		t.Error("button clicked on press")
+	}
+	if c := display.memory.Get(12, 12); c != accent {
+		t.Errorf("expected pressed button to be highlighted, got %v", c)
+	}
+	if !frame(touch.Point{}) {
+		t.Error("button not clicked on release")
+	}
+
+	// Dragging out of the button cancels the click.
+	frame(touch.Point{X: 15, Y: 15, Z: 100})
+	frame(touch.Point{X: 50, Y: 40, Z: 100})
+	if frame(touch.Point{}) {
+		t.Error("button clicked after dragging out of it")
+	}
+
+	// A press outside the button that's dragged into it doesn't click it.
+	frame(touch.Point{X: 50, Y: 40, Z: 100})
+	frame(touch.Point{X: 15, Y: 15, Z: 100})
+	if frame(touch.Point{}) {
+		t.Error("button clicked after dragging into it")
+	}
+}
+
+func TestToggle(t *testing.T) {
+	ui, _ := newUI()
+	on := false
+	for _, p := range []touch.Point{{X: 5, Y: 5, Z: 1}, {}} {
+		ui.Begin(p)
+		ui.Toggle(widget.Rect{0, 0, 40, 12}, "A", &on)
+		ui.End()
+	}
+	if !on {
+		t.Error("toggle wasn't switched on")
+	}
+}
+
+func TestSlider(t *testing.T) {
+	ui, _ := newUI()
+	r := widget.Rect{0, 0, 60, 20} // knob is 10 pixels wide, track is 50
+	value := 0
+
+	steps := []struct {
+		point   touch.Point
+		value   int
+		changed bool
+	}{
+		{touch.Point{X: 30, Y: 10, Z: 1}, 50, true},
+		{touch.Point{X: 30, Y: 10, Z: 1}, 50, false},
+		{touch.Point{X: 55, Y: 40, Z: 1}, 100, true}, // dragged outside
+		{touch.Point{X: -20, Y: 10, Z: 1}, 0, true},
+		{touch.Point{}, 0, false},
+		{touch.Point{X: 30, Y: 30, Z: 1}, 0, false}, // pressed outside
+	}
+	for i, step := range steps {
+		ui.Begin(step.point)
+		changed := ui.Slider(r, &value, 0, 100)
+		ui.End()
+		if value != step.value || changed != step.changed {
+			t.Errorf("step %d: expected value %d (changed %v), got %d (changed %v)", i, step.value, step.changed, value, changed)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	ui, display := newUI()
+	r := widget.Rect{0, 0, 64, 24} // rows are 8 pixels high, 3 are visible
+	items := []string{"A", "A A", "A", "A A", "A"}
+	state := widget.ListState{Selected: -1}
+	frame := func(p touch.Point) bool {
+		ui.Begin(p)
+		selected := ui.List(r, items, &state)
+		ui.End()
+		return selected
+	}
+
+	// Tap the second row.
+	frame(touch.Point{X: 10, Y: 10, Z: 1})
+	if !frame(touch.Point{}) || state.Selected != 1 {
+		t.Errorf("expected second item to be selected, got %d", state.Selected)
+	}
+	if c := display.memory.Get(30, 9); c != accent {
+		t.Errorf("expected selected row to be highlighted, got %v", c)
+	}
+
+	// Drag up to scroll, which doesn't select anything.
+	frame(touch.Point{X: 10, Y: 20, Z: 1})
+	frame(touch.Point{X: 10, Y: 12, Z: 1})
+	if state.Offset != 8 {
+		t.Errorf("expected scroll offset 8, got %d", state.Offset)
+	}
+	frame(touch.Point{X: 10, Y: 0, Z: 1}) // beyond the end of the list
+	if frame(touch.Point{}) || state.Selected != 1 {
+		t.Errorf("drag changed the selection to %d", state.Selected)
+	}
+	if state.Offset != 16 {
+		t.Errorf("expected scroll offset to be limited to 16, got %d", state.Offset)
+	}
+
+	// Tapping takes the scroll offset into account.
+	frame(touch.Point{X: 10, Y: 2, Z: 1})
+	frame(touch.Point{})
+	if state.Selected != 2 {
+		t.Errorf("expected third item to be selected, got %d", state.Selected)
+	}
+}
+
+func TestColors(t *testing.T) {
+	display := newFakeDisplay(32, 32)
+	ui := widget.New[pixel.RGB888](display, widget.Config{
+		Font:       testFont,
+		Background: color.RGBA{R: 255, A: 255},
+		Foreground: color.RGBA{A: 255},
+	})
+	ui.Begin(touch.Point{})
+	ui.End()
+	if c := display.memory.Get(31, 31); c != pixel.NewRGB888(255, 0, 0) {
+		t.Errorf("expected screen to be cleared with the background color, got %v", c)
+	}
+}